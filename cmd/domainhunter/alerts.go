@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/alerting"
+)
+
+func runSnooze(args []string) error {
+	fs := newFlagSet("snooze")
+	days := fs.Int("days", 7, "how many days to silence the domain's alerts for")
+	remove := fs.Bool("clear", false, "lift the snooze instead")
+	db := addDBFlags(fs, "database file")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return errors.New("usage: domainhunter snooze [--days N] <domain>...")
+	}
+	if *days <= 0 && !*remove {
+		return errors.New("--days must be positive")
+	}
+	until := time.Now().AddDate(0, 0, *days)
+	if *remove {
+		until = time.Time{}
+	}
+	return editAlerts(db, fs.Args(), func(ctx context.Context, g alerting.Gate, domain string) (string, error) {
+		if err := g.Snooze(ctx, domain, until); err != nil {
+			return "", err
+		}
+		if *remove {
+			return "snooze lifted", nil
+		}
+		return "alerts snoozed until " + until.Format("Jan 2 15:04"), nil
+	})
+}
+
+func runAck(args []string) error {
+	fs := newFlagSet("ack")
+	db := addDBFlags(fs, "database file")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return errors.New("usage: domainhunter ack <domain>...")
+	}
+	return editAlerts(db, fs.Args(), func(ctx context.Context, g alerting.Gate, domain string) (string, error) {
+		n, err := g.Ack(ctx, domain)
+		if err != nil || n == 0 {
+			return "no alerts to acknowledge", err
+		}
+		return fmt.Sprintf("%d alerts acknowledged, quiet until they change", n), nil
+	})
+}
+
+// editAlerts applies edit to the alert state of each domain, printing what
+// it reports
+func editAlerts(db dbFlags, domains []string, edit func(ctx context.Context, g alerting.Gate, domain string) (string, error)) error {
+	store, err := db.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	g := alerting.Gate{Store: store}
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		msg, err := edit(ctx, g, domain)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n", domain, msg)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/autobuy"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+func runAutoBuy(args []string) error {
+	fs := newFlagSet("autobuy")
+	registrar := fs.String("registrar", "", "registrar to buy through: "+strings.Join(autobuy.Names, ", "))
+	maxPrice := fs.Float64("max", 0, "price ceiling for one year, in USD")
+	dryRun := fs.Bool("dry-run", false, "only quote and log what would be bought")
+	remove := fs.Bool("clear", false, "remove the auto-buy instead")
+	db := addDBFlags(fs, "database file")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return errors.New("usage: domainhunter autobuy --registrar NAME --max USD [--dry-run] <domain>...")
+	}
+	if *remove {
+		return editWatches(db, fs.Args(), func(w *models.WatchedDomain, found bool) (string, bool) {
+			if !found || w.AutoBuy == nil {
+				return "no auto-buy", false
+			}
+			w.AutoBuy = nil
+			return "auto-buy cleared", true
+		})
+	}
+
+	if !slices.Contains(autobuy.Names, *registrar) {
+		return fmt.Errorf("--registrar must be one of %s", strings.Join(autobuy.Names, ", "))
+	}
+	if *maxPrice <= 0 {
+		return errors.New("--max must be positive")
+	}
+	return editWatches(db, fs.Args(), func(w *models.WatchedDomain, _ bool) (string, bool) {
+		if w.Owned {
+			return "already owned", false
+		}
+		w.AutoBuy = &models.AutoBuy{Registrar: *registrar, MaxPrice: *maxPrice, DryRun: *dryRun}
+		msg := fmt.Sprintf("will register with %s for up to $%.2f once available", *registrar, *maxPrice)
+		if *dryRun {
+			msg += " (dry run)"
+		}
+		return msg, true
+	})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/backorder"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+func runBackorder(args []string) error {
+	fs := newFlagSet("backorder")
+	provider := fs.String("provider", "", "backorder service: "+strings.Join(backorder.Names, ", "))
+	maxPrice := fs.Float64("max", 0, "most to spend on the domain, in USD")
+	remove := fs.Bool("clear", false, "remove the backorder instead")
+	db := addDBFlags(fs, "database file")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return errors.New("usage: domainhunter backorder --provider NAME --max USD <domain>...")
+	}
+	if *remove {
+		return editWatches(db, fs.Args(), func(w *models.WatchedDomain, found bool) (string, bool) {
+			if !found || w.Backorder == nil {
+				return "no backorder", false
+			}
+			w.Backorder = nil
+			return "backorder cleared", true
+		})
+	}
+
+	if strings.EqualFold(*provider, "snapnames") {
+		return errors.New("SnapNames has no public API to place backorders through; place it on snapnames.com instead")
+	}
+	if !slices.Contains(backorder.Names, *provider) {
+		return fmt.Errorf("--provider must be one of %s", strings.Join(backorder.Names, ", "))
+	}
+	if *maxPrice <= 0 {
+		return errors.New("--max must be positive")
+	}
+	return editWatches(db, fs.Args(), func(w *models.WatchedDomain, _ bool) (string, bool) {
+		w.Backorder = &models.Backorder{Provider: *provider, MaxPrice: *maxPrice}
+		return fmt.Sprintf("backorder with %s up to $%.2f once it's pending delete", *provider, *maxPrice), true
+	})
+}
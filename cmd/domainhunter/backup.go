@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/backup"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+func runBackup(args []string) error {
+	fs := newFlagSet("backup")
+	out := fs.String("out", "", "archive to write (required)")
+	from := fs.String("from", "", "download a hot backup from a running server (base URL); uses ADMIN_TOKEN")
+	var configs stringList
+	fs.Var(&configs, "config", "config file to include (repeatable)")
+	db := addDBFlags(fs, "database file to back up")
+	fs.Parse(args)
+
+	if *out == "" {
+		return errors.New("--out is required")
+	}
+
+	f, err := os.Create(*out + ".partial")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if *from != "" {
+		err = download(f, strings.TrimSuffix(*from, "/")+"/admin/backup")
+	} else {
+		var path string
+		if path, err = db.path(); err == nil {
+			err = backupLocal(f, path, configs)
+		}
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if *from != "" {
+		if err := verifyArchive(f.Name()); err != nil {
+			return fmt.Errorf("downloaded backup is unusable: %w", err)
+		}
+	}
+	if err := os.Rename(f.Name(), *out); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup written to %s\n", *out)
+	return nil
+}
+
+// backupLocal snapshots a database file that isn't held open by a server
+func backupLocal(w io.Writer, path string, configs []string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	store, err := storage.Open(path)
+	if err != nil {
+		return fmt.Errorf("open %s (is the server running? use --from): %w", path, err)
+	}
+	defer store.Close()
+
+	return backup.Write(w, store, configs)
+}
+
+// download fetches a backup from a running server's admin endpoint
+func download(w io.Writer, url string) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth("admin", os.Getenv("ADMIN_TOKEN"))
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.ContentLength >= 0 && n != resp.ContentLength {
+		return fmt.Errorf("download cut short: got %d of %d bytes", n, resp.ContentLength)
+	}
+	return nil
+}
+
+// verifyArchive checks that the archive at path reads through as a backup
+func verifyArchive(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return backup.Verify(f)
+}
+
+func runRestore(args []string) error {
+	fs := newFlagSet("restore")
+	in := fs.String("in", "", "archive to restore (required)")
+	db := addDBFlags(fs, "where to write the database")
+	cfgDir := fs.String("config-dir", ".", "where to write config files")
+	force := fs.Bool("force", false, "overwrite existing files")
+	fs.Parse(args)
+
+	if *in == "" {
+		return errors.New("--in is required")
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	path, err := db.path()
+	if err != nil {
+		return err
+	}
+	restored, err := backup.Restore(f, path, *cfgDir, *force)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range restored {
+		fmt.Printf("Restored %s\n", path)
+	}
+	return nil
+}
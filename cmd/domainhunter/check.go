@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+func runCheck(args []string) error {
+	fs := newFlagSet("check")
+	configPath := addConfigFlag(fs)
+	format := fs.String("format", "table", "json, ndjson, csv or table")
+	addColorFlag(fs)
+	tlds := fs.String("tlds", "", "check bare names under these comma-separated TLDs, e.g. com,io,dev,ai (default: com)")
+	tldList := fs.String("tld-list", "", "check bare names under a named TLD list: premium, common or one from the config's tld_lists")
+	only := fs.String("only", "", "comma-separated statuses to print, e.g. available or available,premium (default: all)")
+	failOnFlag := fs.String("fail-on", "", "exit non-zero when: none-available (3), errors or errors=RATE, e.g. errors=5% (4)")
+	whoisOnly := fs.Bool("whois", false, "confirm every domain by WHOIS instead of settling taken ones by DNS")
+	checkFlags := addCheckerFlags(fs)
+	logs := addLogFlags(fs)
+	args = parseArgs(fs, args)
+
+	// Keep stdout for the results alone
+	if err := logs.setup(os.Stderr); err != nil {
+		return err
+	}
+	if !export.Valid(*format) {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	statuses, err := parseStatuses(*only)
+	if err != nil {
+		return err
+	}
+	fail, err := parseFailOn(*failOnFlag)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 || len(args) == 1 && args[0] == "-" {
+		if len(args) == 0 && isTerminal(os.Stdin) {
+			return errors.New("usage: domainhunter check [flags] <domain>... or - to read them from stdin")
+		}
+		if args, err = readDomains(os.Stdin); err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	c, err := newChecker(cfg, checkFlags)
+	if err != nil {
+		return err
+	}
+
+	domains, err := expandNames(cfg, args, *tlds, *tldList)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	check := c.CheckBulkHybrid
+	if *whoisOnly {
+		check = c.CheckBulk
+	}
+	start := time.Now()
+	results, err := check(ctx, domains)
+	logServers(ctx, c, time.Since(start))
+	if err != nil && results == nil {
+		return err
+	}
+	if werr := export.Write(os.Stdout, *format, export.Only(results, statuses...)); werr != nil {
+		return werr
+	}
+	if err != nil {
+		return err
+	}
+	var stats models.ScanStats
+	stats.Add(results)
+	return fail.check(stats)
+}
+
+// readDomains reads one domain per line, skipping blank lines and
+// # comments
+func readDomains(r io.Reader) ([]string, error) {
+	var domains []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			domains = append(domains, line)
+		}
+	}
+	return domains, sc.Err()
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
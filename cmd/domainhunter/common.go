@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/logging"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/render"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// logFlags are --log-level and --log-format, defaulting to LOG_LEVEL and
+// LOG_FORMAT, and the --quiet and -v/-vv shorthands that override the level
+type logFlags struct {
+	level, format *string
+	quiet         *bool
+	verbose       int
+}
+
+// addLogFlags registers the logging flags on fs
+func addLogFlags(fs *flag.FlagSet) *logFlags {
+	f := &logFlags{
+		level:  fs.String("log-level", os.Getenv("LOG_LEVEL"), "log level: trace, debug, info, warn or error (default: LOG_LEVEL, or info)"),
+		format: fs.String("log-format", os.Getenv("LOG_FORMAT"), "log format: text or json (default: LOG_FORMAT, or text)"),
+		quiet:  fs.Bool("quiet", false, "only results on stdout; nothing logged but warnings and errors, to stderr"),
+	}
+	fs.BoolVar(f.quiet, "q", false, "short for --quiet")
+	fs.BoolFunc("v", "verbose: also log each WHOIS server's query rate, failures and backoff; -vv adds every query and retry", func(string) error {
+		f.verbose++
+		return nil
+	})
+	fs.BoolFunc("vv", "as -v -v", func(string) error {
+		f.verbose += 2
+		return nil
+	})
+	return f
+}
+
+// setup makes slog write to w at the flags' level and format. --quiet
+// writes to stderr whatever w is.
+func (f *logFlags) setup(w io.Writer) error {
+	level := *f.level
+	switch {
+	case *f.quiet && f.verbose > 0:
+		return errors.New("--quiet and -v are mutually exclusive")
+	case *f.quiet:
+		level, w = "warn", os.Stderr
+	case f.verbose == 1:
+		level = "debug"
+	case f.verbose > 1:
+		level = "trace"
+	}
+	return logging.Setup(w, level, *f.format)
+}
+
+// logServers logs, for -v, how each WHOIS server c queried fared over
+// elapsed: its query rate, failures and where its adaptive limit stands
+func logServers(ctx context.Context, c *checker.Checker, elapsed time.Duration) {
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	for _, s := range c.Telemetry().Snapshot() {
+		limit, spacing := c.Limit(s.Server)
+		slog.DebugContext(ctx, "whois server", "server", s.Server, "queries", s.Queries,
+			"qps", fmt.Sprintf("%.2f", float64(s.Queries)/max(elapsed.Seconds(), 1)),
+			"errors", s.Errors, "timeouts", s.Timeouts, "refused", s.Refused, "rate_limited", s.RateLimited,
+			"avg_latency", (time.Duration(s.AvgLatency) * time.Millisecond).Round(time.Millisecond),
+			"concurrency", limit, "spacing", spacing, "circuit_open", !c.Circuit(s.Server).IsZero())
+	}
+}
+
+// addColorFlag registers --no-color on fs, for commands that print tables
+func addColorFlag(fs *flag.FlagSet) {
+	fs.BoolFunc("no-color", "print tables without color (default: color on terminals unless NO_COLOR is set)", func(string) error {
+		render.DisableColor = true
+		return nil
+	})
+}
+
+// checkerFlags are the checker tuning flags shared by every command that
+// checks domains. Each overrides the config, or the environment without
+// one, when given. --qps, --qps-per-server, --max-concurrency and
+// --timeout are the politeness limits; the rest tune each phase.
+type checkerFlags struct {
+	profile      *string
+	qps          *float64
+	maxConc      *int
+	timeout      *time.Duration
+	dnsConc      *int
+	dnsQPS       *float64
+	dnsTimeout   *time.Duration
+	whoisConc    *int
+	whoisQPS     *float64
+	whoisTimeout *time.Duration
+	dialInterval *time.Duration
+	preDial      *bool
+	proxies      *string
+	proxyRotate  *int
+	sourceAddr   *string
+}
+
+// addCheckerFlags registers the checker flags on fs
+func addCheckerFlags(fs *flag.FlagSet) *checkerFlags {
+	f := &checkerFlags{
+		profile:      fs.String("profile", "", "performance preset: gentle, normal or aggressive; the flags below override it (default: CHECK_PROFILE or config)"),
+		qps:          fs.Float64("qps", 0, "max DNS and WHOIS queries per second in all (default: CHECK_QPS, config or unlimited)"),
+		whoisQPS:     fs.Float64("qps-per-server", 0, "max WHOIS queries per second to each server (default: CHECK_QPS_PER_SERVER, config or unlimited)"),
+		maxConc:      fs.Int("max-concurrency", 0, "cap on parallel DNS lookups and on parallel WHOIS queries to each server, whatever the profile or --dns-concurrency and --whois-concurrency say (default: CHECK_MAX_CONCURRENCY, config or none)"),
+		timeout:      fs.Duration("timeout", 0, "DNS and WHOIS timeout, unless --dns-timeout or --whois-timeout is given (default: CHECK_TIMEOUT or config)"),
+		dnsConc:      fs.Int("dns-concurrency", 0, "parallel DNS lookups (default: CHECK_DNS_CONCURRENCY, config or 50)"),
+		dnsQPS:       fs.Float64("dns-qps", 0, "max DNS queries per second (default: config or 1000)"),
+		dnsTimeout:   fs.Duration("dns-timeout", 0, "time the DNS phase may spend on one domain, retries included (default: CHECK_DNS_TIMEOUT, config or 10s)"),
+		whoisConc:    fs.Int("whois-concurrency", 0, "parallel WHOIS queries to each server (default: CHECK_WHOIS_CONCURRENCY, config or 5)"),
+		whoisTimeout: fs.Duration("whois-timeout", 0, "time one WHOIS query may take (default: CHECK_WHOIS_TIMEOUT, config or 30s)"),
+		dialInterval: fs.Duration("whois-dial-interval", 0, "min time between new connections to each WHOIS server (default: config or 100ms)"),
+		preDial:      fs.Bool("whois-pre-dial", false, "keep a connection to each WHOIS server dialed ahead of the next query, paced like one (default: config or off)"),
+		proxies:      fs.String("whois-proxy", "", "comma-separated socks5:// or http:// proxies for WHOIS queries (default: WHOIS_PROXIES or config)"),
+		proxyRotate:  fs.Int("whois-proxy-rotate", 0, "WHOIS connections per proxy before moving to the next (default: WHOIS_PROXY_ROTATE, config, or stick to the first)"),
+		sourceAddr:   fs.String("source-addr", "", "local IP to make WHOIS connections from (default: config or chosen by the OS)"),
+	}
+	fs.Float64Var(f.whoisQPS, "whois-qps-per-server", 0, "same as --qps-per-server")
+	return f
+}
+
+// apply overrides cc with the flags that were given. --profile and
+// --timeout replace everything they cover, then the other flags override
+// them.
+func (f *checkerFlags) apply(cc *config.Concurrency) {
+	if *f.profile != "" {
+		cc.UseProfile(*f.profile)
+	}
+	if *f.qps > 0 {
+		cc.QPS = *f.qps
+	}
+	if *f.maxConc > 0 {
+		cc.MaxConcurrency = *f.maxConc
+	}
+	if *f.timeout > 0 {
+		cc.UseTimeout(*f.timeout)
+	}
+	if *f.dnsConc > 0 {
+		cc.DNS = *f.dnsConc
+	}
+	if *f.dnsQPS > 0 {
+		cc.DNSQPS = *f.dnsQPS
+	}
+	if *f.dnsTimeout > 0 {
+		cc.DNSTimeout = *f.dnsTimeout
+	}
+	if *f.whoisConc > 0 {
+		cc.WHOIS = *f.whoisConc
+	}
+	if *f.whoisQPS > 0 {
+		cc.WHOISQPS = *f.whoisQPS
+	}
+	if *f.whoisTimeout > 0 {
+		cc.WHOISTimeout = *f.whoisTimeout
+	}
+	if *f.dialInterval > 0 {
+		cc.WHOISDialInterval = *f.dialInterval
+	}
+	if *f.preDial {
+		cc.WHOISPreDial = true
+	}
+	if *f.sourceAddr != "" {
+		cc.SourceAddr = *f.sourceAddr
+	}
+	if *f.proxies != "" {
+		cc.WHOISProxies = splitList(*f.proxies)
+	}
+	if *f.proxyRotate > 0 {
+		cc.WHOISProxyRotate = *f.proxyRotate
+	}
+}
+
+// newChecker creates the checker for cfg's concurrency settings, overridden
+// by the flags given. Registrar accounts for auto-buy also quote premium
+// names.
+func newChecker(cfg *config.Scan, f *checkerFlags) (*checker.Checker, error) {
+	f.apply(&cfg.Concurrency)
+	if err := cfg.Concurrency.Validate(); err != nil {
+		return nil, fmt.Errorf("concurrency: %w", err)
+	}
+	return cfg.Checker(), nil
+}
+
+// parseArgs parses args with fs, allowing flags after the positional
+// arguments, as in "check myname --tlds io,ai", and returns the positional
+// ones. Everything after "--" is positional.
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		rest := fs.Args()
+		if used := len(args) - len(rest); used > 0 && args[used-1] == "--" {
+			return append(positional, rest...)
+		}
+		if len(rest) == 0 {
+			return positional
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// expandNames lowercases names and checks bare ones under each of tlds, or
+// of the TLD list named tldList, higher-priority TLDs first; without either
+// they get .com. Names with a dot are kept as they are.
+func expandNames(cfg *config.Scan, names []string, tlds, tldList string) ([]string, error) {
+	if tlds != "" && tldList != "" {
+		return nil, errors.New("--tlds and --tld-list are mutually exclusive")
+	}
+	expand := []string{"com"}
+	if tlds != "" || tldList != "" {
+		var err error
+		if expand, err = cfg.TLDsFor(config.ScanSpec{TLDs: splitList(tlds), TLDList: tldList}); err != nil {
+			return nil, err
+		}
+	}
+	var domains []string
+	for _, d := range names {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if strings.Contains(d, ".") {
+			domains = append(domains, d)
+			continue
+		}
+		for _, tld := range expand {
+			domains = append(domains, d+"."+tld)
+		}
+	}
+	return domains, nil
+}
+
+// parseStatuses parses a comma-separated list of result statuses
+func parseStatuses(s string) ([]models.DomainStatus, error) {
+	var statuses []models.DomainStatus
+	for _, item := range splitList(strings.ToLower(s)) {
+		st := models.DomainStatus(item)
+		switch st {
+		case models.StatusAvailable, models.StatusTaken, models.StatusPremium,
+			models.StatusUnknown, models.StatusUnchecked, models.StatusError:
+			statuses = append(statuses, st)
+		default:
+			return nil, fmt.Errorf("unknown status %q", item)
+		}
+	}
+	return statuses, nil
+}
+
+// addConfigFlag registers --config on fs. Commands load it with
+// config.Load, so the environment overrides the file and their flags
+// override both.
+func addConfigFlag(fs *flag.FlagSet) *string {
+	return fs.String("config", "", "YAML config file; environment variables override it, flags override both (default: built-in settings and the environment)")
+}
+
+// dbFlags are --db, and --config to take the database from
+type dbFlags struct {
+	config, db *string
+}
+
+// addDBFlags registers --db on fs, and --config unless fs has its own
+func addDBFlags(fs *flag.FlagSet, usage string) dbFlags {
+	f := dbFlags{config: new(string), db: fs.String("db", "", usage+" (default: DB_PATH, config or domainhunter.db)")}
+	if fs.Lookup("config") == nil {
+		f.config = addConfigFlag(fs)
+	}
+	return f
+}
+
+// path returns --db if given, or else the configured database
+func (f dbFlags) path() (string, error) {
+	if *f.db != "" {
+		return *f.db, nil
+	}
+	cfg, err := config.Load(*f.config)
+	if err != nil {
+		return "", fmt.Errorf("loading config: %w", err)
+	}
+	return cfg.Database, nil
+}
+
+// open opens the database
+func (f dbFlags) open() (storage.Store, error) {
+	path, err := f.path()
+	if err != nil {
+		return nil, err
+	}
+	return storage.Open(path)
+}
+
+// flushTraces sends the spans still buffered, giving up after a few
+// seconds if the collector is unreachable
+func flushTraces(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		slog.Warn("flushing traces", "err", err)
+	}
+}
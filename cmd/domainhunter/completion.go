@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/render"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// The completion scripts ask the binary itself what to complete, by
+// running "domainhunter __complete <words before the cursor> <current
+// word>". It prints one candidate per line, a tab and a description after
+// some; printing nothing leaves the shell to complete file names.
+const (
+	bashCompletion = `# bash completion for domainhunter
+_domainhunter() {
+	local IFS=$'\n'
+	COMPREPLY=($(domainhunter __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | cut -f1))
+}
+complete -o default -F _domainhunter domainhunter
+`
+	zshCompletion = `#compdef domainhunter
+_domainhunter() {
+	local -a completions
+	completions=("${(@f)$(domainhunter __complete "${(@)words[2,CURRENT]}" 2>/dev/null | cut -f1)}")
+	completions=(${completions:#})
+	if (( ${#completions} )); then
+		compadd -- $completions
+	else
+		_files
+	fi
+}
+compdef _domainhunter domainhunter
+`
+	fishCompletion = `# fish completion for domainhunter
+function __domainhunter_complete
+	set -l out (domainhunter __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)
+	if test (count $out) -eq 0
+		__fish_complete_path (commandline -ct)
+	else
+		printf '%s\n' $out
+	end
+end
+complete -c domainhunter -f -a '(__domainhunter_complete)'
+`
+)
+
+func runCompletion(args []string) error {
+	fs := newFlagSet("completion")
+	fs.Parse(args)
+
+	scripts := map[string]string{"bash": bashCompletion, "zsh": zshCompletion, "fish": fishCompletion}
+	script, ok := scripts[fs.Arg(0)]
+	if !ok {
+		return errors.New("usage: domainhunter completion bash|zsh|fish")
+	}
+	_, err := os.Stdout.WriteString(script)
+	return err
+}
+
+// completing makes newFlagSet stop commands as soon as they parse their
+// flags; see commandFlags
+var (
+	completing  bool
+	parsedFlags *flag.FlagSet
+)
+
+// newFlagSet creates a command's flag set
+func newFlagSet(name string) *flag.FlagSet {
+	if !completing {
+		return flag.NewFlagSet(name, flag.ExitOnError)
+	}
+	fs := flag.NewFlagSet(name, flag.PanicOnError)
+	fs.SetOutput(io.Discard)
+	parsedFlags = fs
+	return fs
+}
+
+// commandFlags returns cmd's flags. It runs cmd asking for help with its
+// flag set panicking instead of exiting, which stops cmd before it does
+// anything.
+func commandFlags(cmd command) (fs *flag.FlagSet) {
+	completing, parsedFlags = true, nil
+	defer func() {
+		if r := recover(); r != nil && r != flag.ErrHelp {
+			panic(r)
+		}
+		fs = parsedFlags
+	}()
+	cmd.run([]string{"-h"})
+	return nil
+}
+
+// runComplete prints the candidates for the last of words, the ones
+// before it being the command line so far after "domainhunter"
+func runComplete(words []string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	for _, c := range complete(words[:len(words)-1], words[len(words)-1]) {
+		fmt.Println(c)
+	}
+}
+
+// complete returns the candidates for cur, given the words before it
+func complete(before []string, cur string) []string {
+	// bash splits --flag=value into three words, and completes the value
+	// alone
+	for i := 1; i < len(before); i++ {
+		if before[i] == "=" {
+			before = slices.Replace(before, i-1, i+1, before[i-1]+"=")
+		}
+	}
+	bashValue := false
+	if n := len(before); n > 0 && strings.HasSuffix(before[n-1], "=") && strings.HasPrefix(before[n-1], "-") {
+		cur = before[n-1] + cur
+		before = before[:n-1]
+		bashValue = true
+	}
+
+	if len(before) == 0 {
+		var out []string
+		for _, cmd := range commands {
+			out = append(out, cmd.name+"\t"+cmd.summary)
+		}
+		return matching(out, cur)
+	}
+	if before[0] == "completion" {
+		return matching([]string{"bash", "fish", "zsh"}, cur)
+	}
+	i := slices.IndexFunc(commands, func(c command) bool { return c.name == before[0] })
+	if i < 0 {
+		return nil
+	}
+	fs := commandFlags(commands[i])
+	if fs == nil {
+		return nil
+	}
+
+	if name, value, ok := strings.Cut(cur, "="); ok && strings.HasPrefix(name, "-") {
+		values := flagValues(fs, strings.TrimLeft(name, "-"), value, before)
+		if bashValue {
+			return values
+		}
+		var out []string
+		for _, v := range values {
+			out = append(out, name+"="+v)
+		}
+		return out
+	}
+	if strings.HasPrefix(cur, "-") {
+		var out []string
+		fs.VisitAll(func(f *flag.Flag) {
+			out = append(out, "--"+f.Name+"\t"+f.Usage)
+		})
+		return matching(out, cur)
+	}
+	if last := before[len(before)-1]; len(before) > 1 && strings.HasPrefix(last, "-") && !strings.Contains(last, "=") {
+		if f := fs.Lookup(strings.TrimLeft(last, "-")); f != nil && !isBoolFlag(f) {
+			return flagValues(fs, f.Name, cur, before)
+		}
+	}
+	return nil
+}
+
+// flagValues returns the values for flag name that start with cur. Flags
+// taking comma-separated lists complete their last item.
+func flagValues(fs *flag.FlagSet, name, cur string, before []string) []string {
+	var values []string
+	list := false
+	switch name {
+	case "format":
+		values = render.Formats
+		switch fs.Name() {
+		case "whois", "dns":
+			values = []string{"text", "json"}
+		case "generate":
+			values = generateFormats
+		}
+	case "tld-list":
+		values = slices.Collect(maps.Keys(config.BuiltinTLDLists))
+		if cfg, err := config.Load(flagValue(before, "config")); err == nil {
+			values = append(values, slices.Collect(maps.Keys(cfg.TLDLists))...)
+		}
+	case "profile":
+		values = slices.Collect(maps.Keys(config.Profiles))
+	case "charset":
+		values = slices.Collect(maps.Keys(checker.Charsets))
+	case "only":
+		values, list = []string{"available", "taken", "premium", "unknown", "unchecked", "error"}, true
+		if fs.Name() == "diff" {
+			values = diffKinds
+		}
+	case "fail-on":
+		values, list = []string{"none-available", "errors", "errors="}, true
+	case "scan":
+		// Latest first, as listed
+		if fs.Name() == "export" {
+			db := dbFlags{config: new(string), db: new(string)}
+			*db.config, *db.db = flagValue(before, "config"), flagValue(before, "db")
+			path, err := db.path()
+			if err != nil {
+				return nil
+			}
+			return matching(savedScans(path), cur)
+		}
+	}
+	slices.Sort(values)
+	values = slices.Compact(values)
+
+	prefix := ""
+	if list {
+		if i := strings.LastIndex(cur, ","); i >= 0 {
+			prefix, cur = cur[:i+1], cur[i+1:]
+		}
+	}
+	var out []string
+	for _, v := range matching(values, cur) {
+		out = append(out, prefix+v)
+	}
+	return out
+}
+
+// savedScans returns the IDs of the saved scans, latest first, described
+// by when they ran and what they found. A database another process holds
+// open gives none rather than holding up the shell.
+func savedScans(db string) []string {
+	if _, err := os.Stat(db); err != nil {
+		return nil
+	}
+	done := make(chan []string, 1)
+	go func() {
+		store, err := storage.Open(db)
+		if err != nil {
+			done <- nil
+			return
+		}
+		defer store.Close()
+		scans, _ := store.ListScans(context.Background(), 20)
+		var ids []string
+		for _, s := range scans {
+			ids = append(ids, fmt.Sprintf("%d\t%s scan, %s, %d available", s.ID, s.Kind, s.StartedAt.Format(time.DateTime), len(s.Available)))
+		}
+		done <- ids
+	}()
+	select {
+	case ids := <-done:
+		return ids
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+// flagValue returns the value given to flag name among words, if any
+func flagValue(words []string, name string) string {
+	for i, w := range words {
+		w = strings.TrimLeft(w, "-")
+		if w == name && i+1 < len(words) {
+			return words[i+1]
+		}
+		if v, ok := strings.CutPrefix(w, name+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// matching returns the candidates starting with prefix; descriptions after
+// a tab don't count
+func matching(candidates []string, prefix string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// isBoolFlag reports whether f takes no value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
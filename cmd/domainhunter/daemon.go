@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/schedule"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// runDaemon scans on the cron schedule until ctx is done. Runs happen one
+// at a time, so a run that overshoots the next slot skips it. If the last
+// recorded run predates a slot that has already passed, e.g. because the
+// process was down, one catch-up run starts immediately.
+func runDaemon(ctx context.Context, cfg *config.Scan, store storage.Store, opts runOptions, expr string) error {
+	sched, err := schedule.Parse(expr)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "daemon started", "schedule", expr)
+
+	last, err := lastRun(ctx, store)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load last run", "err", err)
+	}
+	if !last.IsZero() {
+		if slot := sched.Next(last); !slot.IsZero() && !slot.After(time.Now()) {
+			slog.InfoContext(ctx, "missed run, catching up", "slot", slot)
+			daemonRun(ctx, cfg, store, opts)
+		}
+	}
+
+	for {
+		next := sched.Next(time.Now())
+		if next.IsZero() {
+			return errors.New("schedule never fires")
+		}
+		slog.InfoContext(ctx, "next run", "at", next)
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "daemon stopped")
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+		daemonRun(ctx, cfg, store, opts)
+	}
+}
+
+// daemonRun runs one scan, logging failures instead of exiting
+func daemonRun(ctx context.Context, cfg *config.Scan, store storage.Store, opts runOptions) {
+	if err := run(ctx, cfg, store, opts); err != nil {
+		slog.ErrorContext(ctx, "run failed", "err", err)
+	}
+}
+
+// lastRun returns when the most recent daily scan started, or the zero
+// time if there is none
+func lastRun(ctx context.Context, store storage.Store) (time.Time, error) {
+	scans, err := store.ListScans(ctx, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, s := range scans {
+		if s.Kind == "daily" {
+			return s.StartedAt, nil
+		}
+	}
+	return time.Time{}, nil
+}
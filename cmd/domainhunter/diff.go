@@ -0,0 +1,179 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/render"
+)
+
+// The ways a domain can differ between two runs, in the order diff lists
+// them
+const (
+	diffAvailable = "available" // wasn't available, now is
+	diffTaken     = "taken"     // wasn't taken, now is, or dropped out of findings
+	diffChanged   = "changed"   // any other change of status
+	diffAdded     = "added"     // only in the new run
+	diffRemoved   = "removed"   // only in the old run
+)
+
+var diffKinds = []string{diffAvailable, diffTaken, diffChanged, diffAdded, diffRemoved}
+
+func runDiff(args []string) error {
+	fs := newFlagSet("diff")
+	only := fs.String("only", "available,taken,changed,removed", "comma-separated changes to list: available, taken, changed, added or removed")
+	format := fs.String("format", "table", "json, ndjson, csv or table")
+	exitCode := fs.Bool("exit-code", false, "exit 1 if any change is listed, as git diff --exit-code does")
+	findings := fs.Bool("findings", false, "compare two scans' findings (output.file): a domain new to them became available, one gone from them was taken")
+	addColorFlag(fs)
+	args = parseArgs(fs, args)
+
+	if !render.Valid(*format) {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	kinds := splitList(*only)
+	for _, k := range kinds {
+		if !slices.Contains(diffKinds, k) {
+			return fmt.Errorf("unknown change %q (want available, taken, changed, added or removed)", k)
+		}
+	}
+	if len(args) != 2 {
+		return errors.New("usage: domainhunter diff [flags] <old> <new>")
+	}
+
+	old, err := readResults(args[0])
+	if err != nil {
+		return err
+	}
+	cur, err := readResults(args[1])
+	if err != nil {
+		return err
+	}
+	if *findings && len(cur) == 0 {
+		// Most likely a run that failed, not every domain taken
+		return fmt.Errorf("%s has no findings", args[1])
+	}
+
+	var changes []statusChange
+	for _, c := range diffResults(old, cur, *findings) {
+		if slices.Contains(kinds, c.Change) {
+			changes = append(changes, c)
+		}
+	}
+	if err := statusChanges.Write(os.Stdout, *format, changes); err != nil {
+		return err
+	}
+	if *exitCode && len(changes) > 0 {
+		return &exitError{1, fmt.Sprintf("%d domains changed", len(changes))}
+	}
+	return nil
+}
+
+// readResults reads a json or ndjson results file, keyed by domain. A
+// domain listed more than once, as in output appended to run after run,
+// keeps its last result.
+func readResults(path string) (map[string]models.DomainResult, error) {
+	rs, err := export.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	byDomain := make(map[string]models.DomainResult, len(rs))
+	for _, r := range rs {
+		byDomain[r.Domain] = r
+	}
+	return byDomain, nil
+}
+
+// statusChange is how a domain differs between two runs
+type statusChange struct {
+	Domain    string              `json:"domain"`
+	Change    string              `json:"change"`
+	Old       models.DomainStatus `json:"old,omitempty"`
+	New       models.DomainStatus `json:"new,omitempty"`
+	CheckedAt time.Time           `json:"checked_at,omitzero"` // of the newer result
+}
+
+// diffResults returns the domains whose status differs between old and
+// cur, by kind of change, then domain. Comparing findings, which list only
+// available domains, a domain new to cur became available rather than
+// being added and one missing from it was taken rather than removed.
+func diffResults(old, cur map[string]models.DomainResult, findings bool) []statusChange {
+	var changes []statusChange
+	for d, now := range cur {
+		c := statusChange{Domain: d, New: now.Status, CheckedAt: now.CheckedAt}
+		was, ok := old[d]
+		switch {
+		case !ok && findings:
+			c.Change = diffAvailable
+		case !ok:
+			c.Change = diffAdded
+		case was.Status == now.Status:
+			continue
+		case now.Status == models.StatusAvailable:
+			c.Change = diffAvailable
+		case now.Status == models.StatusTaken:
+			c.Change = diffTaken
+		default:
+			c.Change = diffChanged
+		}
+		c.Old = was.Status
+		changes = append(changes, c)
+	}
+	for d, was := range old {
+		if _, ok := cur[d]; !ok {
+			c := statusChange{Domain: d, Change: diffRemoved, Old: was.Status, CheckedAt: was.CheckedAt}
+			if findings {
+				c.Change, c.New = diffTaken, models.StatusTaken
+			}
+			changes = append(changes, c)
+		}
+	}
+	slices.SortFunc(changes, func(a, b statusChange) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(diffKinds, a.Change), slices.Index(diffKinds, b.Change)),
+			cmp.Compare(a.Domain, b.Domain))
+	})
+	return changes
+}
+
+// statusChanges prints diff's changes; statuses missing from one side
+// show as "-"
+var statusChanges = render.Table[statusChange]{
+	Columns: []string{"DOMAIN", "CHANGE", "WAS", "NOW"},
+	Row: func(c statusChange) []string {
+		return []string{c.Domain, c.Change, diffCell(c.Old), diffCell(c.New)}
+	},
+	CSVColumns: []string{"domain", "change", "old", "new", "checked_at"},
+	CSVRow: func(c statusChange) []string {
+		checked := ""
+		if !c.CheckedAt.IsZero() {
+			checked = c.CheckedAt.Format(time.RFC3339)
+		}
+		return []string{c.Domain, c.Change, string(c.Old), string(c.New), checked}
+	},
+	Colors: func(c statusChange) []render.Color {
+		change := render.Yellow
+		switch c.Change {
+		case diffAvailable:
+			change = render.Green
+		case diffTaken:
+			change = render.Red
+		case diffAdded, diffRemoved:
+			change = render.Dim
+		}
+		return []render.Color{render.Default, change, export.StatusColor(c.Old), export.StatusColor(c.New)}
+	},
+}
+
+func diffCell(status models.DomainStatus) string {
+	if status == "" {
+		return "-"
+	}
+	return export.StatusCell(status)
+}
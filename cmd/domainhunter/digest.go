@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/findings"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// digestKey is the setting holding when the last digest was sent
+const digestKey = "daily/digest"
+
+// buildDigest collects what changed since the last digest, or the last
+// week if none was sent yet
+func buildDigest(ctx context.Context, store storage.Store, available []models.DomainResult, checked func(string) bool) (*notify.Digest, error) {
+	since := time.Now().AddDate(0, 0, -7)
+	v, err := store.GetSetting(ctx, digestKey)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		since = t
+	}
+
+	d := &notify.Digest{Since: since}
+	if d.Taken, err = findings.GotTaken(ctx, store, since, available, checked); err != nil {
+		return nil, err
+	}
+	if d.Watches, err = findings.WatchChanges(ctx, store, since); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/render"
+)
+
+// dnsTypes are the record types the dns command asks for by default
+const dnsTypes = "NS,A,AAAA,MX,SOA"
+
+func runDNS(args []string) error {
+	fs := newFlagSet("dns")
+	configPath := addConfigFlag(fs)
+	types := fs.String("types", dnsTypes, "comma-separated record types to ask for")
+	format := fs.String("format", "text", "text or json")
+	addColorFlag(fs)
+	checkFlags := addCheckerFlags(fs)
+	logs := addLogFlags(fs)
+	args = parseArgs(fs, args)
+
+	if err := logs.setup(os.Stderr); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q (want text or json)", *format)
+	}
+	if len(args) == 0 {
+		return errors.New("usage: domainhunter dns [flags] <domain>...")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	c, err := newChecker(cfg, checkFlags)
+	if err != nil {
+		return err
+	}
+	domains, err := expandNames(cfg, args, "", "")
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for i, domain := range domains {
+		d := dnsDump{Domain: domain, Verdict: c.ExplainDNS(ctx, domain)}
+		for _, t := range splitList(*types) {
+			d.Answers = append(d.Answers, c.Query(ctx, domain, t))
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if *format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(d)
+		} else {
+			if i > 0 {
+				fmt.Println()
+			}
+			err = d.print(os.Stdout)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dnsDump is what the dns command shows of a domain: how the DNS phase
+// treats it, then its records
+type dnsDump struct {
+	Domain  string              `json:"domain"`
+	Verdict checker.DNSVerdict  `json:"verdict"`
+	Answers []checker.DNSAnswer `json:"answers"`
+}
+
+// print writes the dump for people
+func (d dnsDump) print(w io.Writer) error {
+	v := d.Verdict
+	status := string(v.Status)
+	if render.Colorful(w) {
+		status = render.Paint(status, export.StatusColor(v.Status))
+	}
+	fmt.Fprintf(w, "%-9s %s\n", "Domain", d.Domain)
+	fmt.Fprintf(w, "%-9s %s\n", "Resolver", v.Server)
+	if v.Rcode != "" {
+		fmt.Fprintf(w, "%-9s %s\n", "NS query", v.Rcode)
+	}
+	fmt.Fprintf(w, "%-9s %s: %s\n", "Verdict", status, v.Outcome)
+	if v.Error != "" {
+		fmt.Fprintf(w, "%-9s %s\n", "Error", v.Error)
+	}
+
+	fmt.Fprintln(w)
+	for _, a := range d.Answers {
+		switch {
+		case a.Error != "":
+			fmt.Fprintf(w, "%-5s error: %s\n", a.Type, a.Error)
+		case len(a.Records) == 0:
+			line := fmt.Sprintf("%-5s none (%s)", a.Type, a.Rcode)
+			if len(a.Authority) > 0 {
+				line += ", SOA " + strings.Join(a.Authority, ", ")
+			}
+			fmt.Fprintln(w, line)
+		default:
+			for _, r := range a.Records {
+				fmt.Fprintf(w, "%-5s %s\n", a.Type, r)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/findings"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// escalate sends each rule's findings through the rule's channels and marks
+// them reported. It returns the findings whose escalation failed, which go
+// out with the regular report instead.
+func escalate(ctx context.Context, cfg config.Notify, store storage.Store, escalations []notify.Escalation, dryRun bool) []models.DomainResult {
+	var failed []models.DomainResult
+	for _, e := range escalations {
+		channels := cfg.Channels(e.Rule.Channels)
+		names := make([]string, len(channels))
+		for i, n := range channels {
+			names[i] = n.Name()
+		}
+		if dryRun {
+			slog.InfoContext(ctx, "dry run: would escalate", "rule", e.Rule.Name, "domains", len(e.Domains), "notifiers", names)
+			continue
+		}
+
+		report := notify.Report{Title: "Urgent: " + e.Rule.Name, Domains: e.Domains, Date: time.Now()}
+		sent := false
+		for _, n := range channels {
+			if err := n.Notify(ctx, report); err != nil {
+				slog.ErrorContext(ctx, "escalate", "rule", e.Rule.Name, "notifier", n.Name(), "err", err)
+				continue
+			}
+			sent = true
+		}
+		if !sent {
+			failed = append(failed, e.Domains...)
+			continue
+		}
+		slog.InfoContext(ctx, "escalated", "rule", e.Rule.Name, "domains", len(e.Domains), "notifiers", names)
+		if err := findings.MarkReported(ctx, store, e.Domains, nil); err != nil {
+			slog.WarnContext(ctx, "could not save reported findings", "err", err)
+		}
+	}
+	return failed
+}
+
+// escalator applies the escalation rules as a scan goes, a chunk's findings
+// at a time, so urgent ones don't wait for the whole run
+type escalator struct {
+	cfg    config.Notify
+	store  storage.Store
+	dryRun bool
+	sent   map[string]bool // escalated this run
+}
+
+// chunk escalates the unreported findings of one chunk that match a rule.
+// Findings it can't escalate are left for rest.
+func (e *escalator) chunk(ctx context.Context, available []models.DomainResult) {
+	if len(e.cfg.Escalate) == 0 {
+		return
+	}
+	fresh, err := findings.Unreported(ctx, e.store, available)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load reported findings, escalating at the end", "err", err)
+		return
+	}
+	escalations, _ := notify.Escalate(e.cfg.Escalate, fresh)
+	failed := escalate(ctx, e.cfg, e.store, escalations, e.dryRun)
+	for _, esc := range escalations {
+		for _, r := range esc.Domains {
+			e.sent[r.Domain] = !slices.ContainsFunc(failed, func(f models.DomainResult) bool { return f.Domain == r.Domain })
+		}
+	}
+}
+
+// rest escalates what the chunks left to escalate among toSend and returns
+// the findings for the regular report
+func (e *escalator) rest(ctx context.Context, toSend []models.DomainResult) []models.DomainResult {
+	toSend = slices.DeleteFunc(slices.Clone(toSend), func(r models.DomainResult) bool { return e.sent[r.Domain] })
+	escalations, toSend := notify.Escalate(e.cfg.Escalate, toSend)
+	return append(toSend, escalate(ctx, e.cfg, e.store, escalations, e.dryRun)...)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+func runExport(args []string) error {
+	fs := newFlagSet("export")
+	id := fs.Int64("scan", 0, "ID of the scan to export (default: the latest)")
+	format := fs.String("format", "", "json, ndjson, csv or table (default: by --out extension, or table)")
+	addColorFlag(fs)
+	out := fs.String("out", "", "file to write (default: stdout)")
+	db := addDBFlags(fs, "database file")
+	fs.Parse(args)
+
+	if *format != "" && !export.Valid(*format) {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	store, err := db.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	var scan models.Scan
+	if *id > 0 {
+		if scan, err = store.GetScan(ctx, *id); err != nil {
+			return fmt.Errorf("scan %d: %w", *id, err)
+		}
+	} else {
+		scans, err := store.ListScans(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if len(scans) == 0 {
+			return errors.New("no scans saved yet")
+		}
+		scan = scans[0]
+	}
+
+	results := make([]models.DomainResult, len(scan.Available))
+	for i, d := range scan.Available {
+		results[i] = models.DomainResult{Domain: d, Status: models.StatusAvailable, CheckedAt: scan.FinishedAt}
+	}
+
+	if *out == "" {
+		if *format == "" {
+			*format = "table"
+		}
+		return export.Write(os.Stdout, *format, results)
+	}
+	if *format == "" {
+		*format = export.FormatFor(*out)
+	}
+	if err := export.WriteFile(*out, *format, results); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d domains from scan %d to %s\n", len(results), scan.ID, *out)
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Exit codes besides 1 for errors and 2 for usage, so that cron jobs and CI
+// pipelines can branch on what a check or scan found
+const (
+	exitNoneAvailable = 3
+	exitErrorRate     = 4
+)
+
+// exitError is an error that exits with its own code
+type exitError struct {
+	code int
+	msg  string
+}
+
+func (e *exitError) Error() string { return e.msg }
+
+// failOn are the --fail-on conditions that make a finished check or scan
+// exit non-zero
+type failOn struct {
+	noneAvailable bool
+	// errorRate fails runs whose share of failed lookups exceeds it; below
+	// zero it is off
+	errorRate float64
+}
+
+// parseFailOn parses a comma-separated list of conditions:
+//   - none-available: no domain was available
+//   - errors: any lookup failed
+//   - errors=RATE: more than RATE of the lookups failed, as a fraction
+//     (0.05) or percentage (5%)
+func parseFailOn(s string) (failOn, error) {
+	f := failOn{errorRate: -1}
+	for _, item := range splitList(strings.ToLower(s)) {
+		name, value, hasValue := strings.Cut(item, "=")
+		switch {
+		case name == "none-available" && !hasValue:
+			f.noneAvailable = true
+		case name == "errors" && !hasValue:
+			f.errorRate = 0
+		case name == "errors":
+			rate, err := parseRate(value)
+			if err != nil {
+				return f, fmt.Errorf("--fail-on %s: %w", item, err)
+			}
+			f.errorRate = rate
+		default:
+			return f, fmt.Errorf("--fail-on: unknown condition %q (want none-available, errors or errors=RATE)", item)
+		}
+	}
+	return f, nil
+}
+
+// parseRate parses a fraction from 0 to 1, or a percentage
+func parseRate(s string) (float64, error) {
+	pct := strings.HasSuffix(s, "%")
+	rate, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q", s)
+	}
+	if pct {
+		rate /= 100
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate %q must be between 0 and 1, or 0%% and 100%%", s)
+	}
+	return rate, nil
+}
+
+// set reports whether any condition is on
+func (f failOn) set() bool {
+	return f.noneAvailable || f.errorRate >= 0
+}
+
+// check returns an exitError if stats meet a condition
+func (f failOn) check(stats models.ScanStats) error {
+	if f.errorRate >= 0 && stats.Checked > 0 {
+		if rate := float64(stats.Errors) / float64(stats.Checked); rate > f.errorRate {
+			return &exitError{exitErrorRate, fmt.Sprintf("%d of %d lookups failed (%.1f%%)", stats.Errors, stats.Checked, 100*rate)}
+		}
+	}
+	if f.noneAvailable && stats.Available == 0 {
+		return &exitError{exitNoneAvailable, fmt.Sprintf("no available domains among %d checked", stats.Checked)}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/config"
+)
+
+// generateChunk is how many candidates generate holds in memory at once
+const generateChunk = 10000
+
+// generateFormats are what generate prints candidates as: one per line,
+// which check reads back, CSV with the name and TLD apart, or a JSON array
+var generateFormats = []string{"txt", "csv", "json"}
+
+func runGenerate(args []string) error {
+	fs := newFlagSet("generate")
+	configPath := addConfigFlag(fs)
+	lengths := fs.String("lengths", "", "comma-separated name lengths, e.g. 1,2 (replaces configured scans)")
+	fs.StringVar(lengths, "length", "", "same as --lengths")
+	tlds := fs.String("tlds", "", "comma-separated TLDs, e.g. io,dev,ai")
+	tldList := fs.String("tld-list", "", "TLDs from a named list: premium, common or one from the config's tld_lists")
+	prefix := fs.String("prefix", "", "only names starting with this prefix")
+	charset := fs.String("charset", "", "characters to use: alnum, letters or digits")
+	format := fs.String("format", "txt", "txt (one per line, as check reads them), csv or json")
+	fs.Parse(args)
+
+	if !slices.Contains(generateFormats, *format) {
+		return fmt.Errorf("unknown format %q (want txt, csv or json)", *format)
+	}
+	if *tlds != "" && *tldList != "" {
+		return errors.New("--tlds and --tld-list are mutually exclusive")
+	}
+	scope, err := parseScope(*lengths, *tlds, *prefix, *charset)
+	if err != nil {
+		return err
+	}
+	scope.TLDList = *tldList
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	cfg.ApplyScope(scope)
+	// Nothing is reported, so a config without notifiers will do
+	if err := cfg.Validate(); err != nil && !errors.Is(err, config.ErrNoOutput) {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	out := newCandidateWriter(w, *format)
+	for _, spec := range cfg.Scans {
+		domains, _ := specDomains(cfg, spec)
+		for from := 0; from < domains.Len(); from += generateChunk {
+			for _, d := range domains.Slice(from, min(from+generateChunk, domains.Len())) {
+				out.write(d)
+			}
+		}
+	}
+	out.close()
+	return w.Flush()
+}
+
+// candidateWriter streams candidates in one of generateFormats, however
+// many there are
+type candidateWriter struct {
+	w      io.Writer
+	format string
+	csv    *csv.Writer
+	n      int
+}
+
+func newCandidateWriter(w io.Writer, format string) *candidateWriter {
+	cw := &candidateWriter{w: w, format: format}
+	switch format {
+	case "csv":
+		cw.csv = csv.NewWriter(w)
+		cw.csv.Write([]string{"domain", "name", "tld"})
+	case "json":
+		io.WriteString(w, "[")
+	}
+	return cw
+}
+
+func (cw *candidateWriter) write(domain string) {
+	switch cw.format {
+	case "csv":
+		i := strings.LastIndex(domain, ".")
+		cw.csv.Write([]string{domain, domain[:i], domain[i+1:]})
+	case "json":
+		if cw.n > 0 {
+			io.WriteString(cw.w, ",")
+		}
+		b, _ := json.Marshal(domain)
+		fmt.Fprintf(cw.w, "\n  %s", b)
+	default:
+		fmt.Fprintln(cw.w, domain)
+	}
+	cw.n++
+}
+
+// close ends the output; write errors surface when the caller flushes
+func (cw *candidateWriter) close() {
+	switch cw.format {
+	case "csv":
+		cw.csv.Flush()
+	case "json":
+		if cw.n > 0 {
+			io.WriteString(cw.w, "\n")
+		}
+		io.WriteString(cw.w, "]\n")
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+func runInterval(args []string) error {
+	fs := newFlagSet("interval")
+	every := fs.Duration("every", 0, "how often to re-check the domain, e.g. 10m or 168h")
+	remove := fs.Bool("clear", false, "go back to the server's WATCH_INTERVAL instead")
+	db := addDBFlags(fs, "database file")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return errors.New("usage: domainhunter interval --every DURATION <domain>...")
+	}
+	if *remove {
+		return editWatches(db, fs.Args(), func(w *models.WatchedDomain, found bool) (string, bool) {
+			if !found || w.CheckInterval == 0 {
+				return "no interval of its own", false
+			}
+			w.SetCheckInterval(0, time.Now())
+			return "re-checked every WATCH_INTERVAL", true
+		})
+	}
+
+	if *every < time.Minute {
+		return errors.New("--every must be at least 1m")
+	}
+	return editWatches(db, fs.Args(), func(w *models.WatchedDomain, _ bool) (string, bool) {
+		w.SetCheckInterval(*every, time.Now())
+		return "re-checked every " + every.String() + ", more often around its drop", true
+	})
+}
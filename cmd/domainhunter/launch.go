@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/launch"
+	"github.com/berckan/domainhunter/internal/scan"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+const (
+	// launchNotice is how far ahead upcoming launch phases are logged
+	launchNotice = 30 * 24 * time.Hour
+	// launchCatchUp is how long after its GA date a TLD is still scanned
+	// if no run happened on the day
+	launchCatchUp = 7 * 24 * time.Hour
+)
+
+// launchScan is what scanLaunches checked
+type launchScan struct {
+	res     scan.Result
+	checked []string
+	keys    []string
+	tlds    []string
+}
+
+// scanLaunches checks the launch keywords on each TLD that reached general
+// availability lately and wasn't scanned for it yet. Upcoming phases are
+// logged so openings don't come as a surprise.
+func scanLaunches(ctx context.Context, cfg config.Launches, store storage.Store, runner *scan.Runner, dryRun bool) (launchScan, error) {
+	var ls launchScan
+	if cfg.Calendar == "" {
+		return ls, nil
+	}
+	launches, err := launch.Load(ctx, cfg.Calendar)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load launch calendar", "err", err)
+		return ls, nil
+	}
+
+	now := time.Now()
+	for _, e := range launch.Upcoming(launches, now, launchNotice) {
+		slog.InfoContext(ctx, "tld launch upcoming", "tld", e.TLD, "phase", e.Phase, "at", e.At)
+	}
+
+	for _, tld := range launch.Opened(launches, now, launchCatchUp) {
+		doneKey := "launch/" + tld
+		if _, err := store.GetSetting(ctx, doneKey); err == nil {
+			continue
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return ls, err
+		}
+
+		domains := make([]string, len(cfg.Keywords))
+		for i, k := range cfg.Keywords {
+			domains[i] = k + "." + tld
+		}
+		slog.InfoContext(ctx, "tld open, checking keywords", "tld", tld, "keywords", len(domains))
+
+		key := "launch:" + tld
+		if dryRun {
+			key = "dry-run:launch:" + tld
+		}
+		res, err := runner.Run(ctx, key, domains)
+		if err != nil {
+			return ls, fmt.Errorf("scan interrupted: %w", err)
+		}
+		ls.res.Available = append(ls.res.Available, res.Available...)
+		ls.res.Dropping = append(ls.res.Dropping, res.Dropping...)
+		ls.res.Stats.Merge(res.Stats)
+		ls.checked = append(ls.checked, domains...)
+		ls.keys = append(ls.keys, key)
+		ls.tlds = append(ls.tlds, tld)
+	}
+	return ls, nil
+}
+
+// markLaunched records the TLDs as scanned on opening
+func markLaunched(ctx context.Context, store storage.Store, tlds []string) {
+	for _, tld := range tlds {
+		if err := store.SetSetting(ctx, "launch/"+tld, time.Now().Format(time.RFC3339)); err != nil {
+			slog.WarnContext(ctx, "could not record launch scan", "tld", tld, "err", err)
+		}
+	}
+}
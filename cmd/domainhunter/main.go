@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// command is a domainhunter subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "Run the web server and domain watcher", runServe},
+	{"scan", "Run the daily scan, once or on a schedule", runScan},
+	{"check", "Check domains and print the results", runCheck},
+	{"whois", "Show a domain's WHOIS response and how it was read", runWhois},
+	{"dns", "Show a domain's DNS records and how the DNS phase reads them", runDNS},
+	{"watch", "Add domains to the watch list, list or remove them", runWatch},
+	{"generate", "Print the candidate domains a scan would check", runGenerate},
+	{"export", "Write a saved scan's findings to a file", runExport},
+	{"diff", "Compare two result files: newly available, taken or changed", runDiff},
+	{"tui", "Hunt interactively, with live progress and findings", runTUI},
+	{"backup", "Snapshot the database and config into a .tar.gz", runBackup},
+	{"restore", "Restore a backup archive", runRestore},
+	{"backorder", "Set or clear a watched domain's drop-catch backorder", runBackorder},
+	{"autobuy", "Set or clear a watched domain's auto-registration", runAutoBuy},
+	{"interval", "Set how often a watched domain is re-checked", runInterval},
+	{"snooze", "Silence a domain's alerts for a number of days", runSnooze},
+	{"ack", "Acknowledge a domain's alerts so they aren't repeated", runAck},
+	{"prices", "Show tracked registrar prices, optionally for one TLD", runPrices},
+	{"completion", "Print a bash, zsh or fish completion script", runCompletion},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		usage()
+		os.Exit(2)
+	}
+
+	if os.Args[1] == "__complete" {
+		runComplete(os.Args[2:])
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				var exit *exitError
+				if errors.As(err, &exit) {
+					os.Exit(exit.code)
+				}
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: domainhunter <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string { return fmt.Sprint(*l) }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
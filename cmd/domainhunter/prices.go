@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/pricing"
+	"github.com/berckan/domainhunter/internal/render"
+)
+
+func runPrices(args []string) error {
+	fs := newFlagSet("prices")
+	compare := fs.Bool("compare", false, "compare each TLD's latest prices across registrars, flagging renewal traps")
+	format := fs.String("format", "table", "json, ndjson, csv or table")
+	addColorFlag(fs)
+	db := addDBFlags(fs, "database file")
+	fs.Parse(args)
+
+	if !render.Valid(*format) {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	store, err := db.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	tld := strings.TrimPrefix(strings.ToLower(fs.Arg(0)), ".")
+	prices, err := store.ListPrices(context.Background(), tld)
+	if err != nil {
+		return err
+	}
+	if len(prices) == 0 && *format == "table" {
+		fmt.Println("No prices recorded yet; the server fetches them daily for PRICE_TLDS")
+		return nil
+	}
+
+	if *compare {
+		return printComparison(prices, *format)
+	}
+	return priceTable.Write(os.Stdout, *format, prices)
+}
+
+// priceTable prints recorded prices
+var priceTable = render.Table[models.Price]{
+	Columns: []string{"TLD", "REGISTRAR", "REGISTER", "RENEW", "TRANSFER", "CHECKED"},
+	Row: func(p models.Price) []string {
+		return []string{"." + p.TLD, p.Registrar, usd(p.Register), usd(p.Renew), usd(p.Transfer), p.CheckedAt.Format(time.DateOnly)}
+	},
+	CSVColumns: []string{"tld", "registrar", "register", "renew", "transfer", "checked_at"},
+	CSVRow: func(p models.Price) []string {
+		return []string{p.TLD, p.Registrar, amount(p.Register), amount(p.Renew), amount(p.Transfer), p.CheckedAt.Format(time.RFC3339)}
+	},
+}
+
+// comparedPrice is a registrar's price in a comparison, with what stands
+// out about it
+type comparedPrice struct {
+	models.Price
+	Note string `json:"note,omitempty"`
+}
+
+// comparisonTable prints compared prices
+var comparisonTable = render.Table[comparedPrice]{
+	Columns: []string{"TLD", "REGISTRAR", "FIRST YEAR", "RENEWAL", ""},
+	Row: func(p comparedPrice) []string {
+		return []string{"." + p.TLD, p.Registrar, usd(p.Register), usd(p.Renew), p.Note}
+	},
+	CSVColumns: []string{"tld", "registrar", "first_year", "renewal", "note"},
+	CSVRow: func(p comparedPrice) []string {
+		return []string{p.TLD, p.Registrar, amount(p.Register), amount(p.Renew), p.Note}
+	},
+	Colors: func(p comparedPrice) []render.Color {
+		note := render.Green
+		if p.Note == "renewal trap" {
+			note = render.Red
+		}
+		return []render.Color{render.Default, render.Default, render.Default, render.Default, note}
+	},
+}
+
+// printComparison lists each TLD's registrars cheapest first year first,
+// marking the cheapest renewal and any renewal traps
+func printComparison(prices []models.Price, format string) error {
+	var rows []comparedPrice
+	var traps []string
+	for _, c := range pricing.Compare(prices) {
+		cheapest := c.CheapestRenewal()
+		for _, p := range c.Prices {
+			row := comparedPrice{Price: p}
+			switch {
+			case p.Trap():
+				row.Note = "renewal trap"
+				traps = append(traps, p.TrapWarning())
+			case p == cheapest:
+				row.Note = "cheapest renewal"
+			}
+			rows = append(rows, row)
+		}
+	}
+	if err := comparisonTable.Write(os.Stdout, format, rows); err != nil {
+		return err
+	}
+	// Kept off stdout, which carries the table alone
+	if format == "table" {
+		for _, t := range traps {
+			fmt.Fprintln(os.Stderr, "warning: "+t)
+		}
+	}
+	return nil
+}
+
+// usd formats a price, or "-" if there is none
+func usd(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
+
+// amount formats a price for machines, empty if there is none
+func amount(v float64) string {
+	if v <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/findings"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+	"github.com/berckan/domainhunter/internal/scan"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// runRecordKey is the setting holding the run in progress
+const runRecordKey = "daily/run"
+
+// runRecord is the scope of a run in progress. It is kept until the run
+// completes, so --resume knows what the interrupted run was scanning.
+// Paused marks a run stopped by its budget, which the next run carries on.
+type runRecord struct {
+	Scans     []config.ScanSpec `json:"scans"`
+	StartedAt time.Time         `json:"started_at"`
+	Paused    bool              `json:"paused,omitempty"`
+}
+
+// loadRunRecord returns the interrupted run, or a zero record if none
+func loadRunRecord(ctx context.Context, store storage.Store) (runRecord, error) {
+	var rec runRecord
+	v, err := store.GetSetting(ctx, runRecordKey)
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && v == "") {
+		return rec, nil
+	}
+	if err != nil {
+		return rec, err
+	}
+	return rec, json.Unmarshal([]byte(v), &rec)
+}
+
+func saveRunRecord(ctx context.Context, store storage.Store, rec runRecord) error {
+	v, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return store.SetSetting(ctx, runRecordKey, string(v))
+}
+
+// finishRun drops the completed run's checkpoints and, unless this was a
+// dry run, its record
+func finishRun(ctx context.Context, store storage.Store, keys []string, clearRecord bool) {
+	for _, key := range keys {
+		if err := store.DeleteCheckpoint(ctx, key); err != nil {
+			slog.WarnContext(ctx, "could not delete checkpoint", "scan", key, "err", err)
+		}
+	}
+	if clearRecord {
+		if err := store.SetSetting(ctx, runRecordKey, ""); err != nil {
+			slog.WarnContext(ctx, "could not clear run record", "err", err)
+		}
+	}
+}
+
+// errInterrupted is why a run stopped by SIGTERM or SIGINT ended
+var errInterrupted = errors.New("stopped by a signal")
+
+// pauseRun ends a run that spent its budget or was interrupted, logging
+// how much of it was checked and writing what wasn't to uncheckedPath, if
+// set. Its checkpoints stay, and unless this is a dry run its record is
+// marked paused so the next run carries on.
+func pauseRun(ctx context.Context, store storage.Store, rec runRecord, dryRun bool, checked int, unchecked scan.Domains, uncheckedPath string, reason error) {
+	coverage := 100.0
+	if total := checked + unchecked.Len(); total > 0 {
+		coverage = 100 * float64(checked) / float64(total)
+	}
+	slog.WarnContext(ctx, "run paused, the next run carries on",
+		"checked", checked, "unchecked", unchecked.Len(), "coverage", fmt.Sprintf("%.1f%%", coverage), "reason", reason)
+	if dryRun {
+		return
+	}
+	if uncheckedPath != "" {
+		if err := writeUnchecked(uncheckedPath, unchecked); err != nil {
+			slog.WarnContext(ctx, "could not write unchecked domains", "file", uncheckedPath, "err", err)
+		} else {
+			slog.InfoContext(ctx, "unchecked domains written", "file", uncheckedPath)
+		}
+	}
+	rec.Paused = true
+	if err := saveRunRecord(ctx, store, rec); err != nil {
+		slog.WarnContext(ctx, "could not record paused run", "err", err)
+	}
+}
+
+// writeUnchecked writes domains to path, one per line, a chunk at a time
+func writeUnchecked(path string, domains scan.Domains) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for i := 0; i < domains.Len(); i += scan.DefaultChunkSize {
+		for _, d := range domains.Slice(i, min(i+scan.DefaultChunkSize, domains.Len())) {
+			fmt.Fprintln(w, d)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// clearUnchecked removes the list a paused run left at path, now that a
+// run has checked everything
+func clearUnchecked(ctx context.Context, path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.WarnContext(ctx, "could not remove unchecked domains", "file", path, "err", err)
+	}
+}
+
+// partialTimeout bounds sending a partial report; preempted machines get
+// about 30 seconds after SIGTERM
+const partialTimeout = 20 * time.Second
+
+// flushInterrupted saves what a run stopped by a signal found: to the
+// output file, or stdout on a dry run, and with notify.partial as a
+// partial report of the findings not reported yet. The report is held
+// back, as a finished run's would be, for min_domains or digest mode,
+// and skipped when there's nothing new; what it sends is marked reported
+// so the run that carries on doesn't repeat it.
+func flushInterrupted(ctx context.Context, cfg *config.Scan, store storage.Store, esc *escalator, dryRun bool, available []models.DomainResult, stats models.ScanStats, startedAt time.Time) {
+	slog.WarnContext(ctx, "run interrupted, saving what it found", "available", len(available), "checked", stats.Checked)
+	if dryRun {
+		if err := export.Write(os.Stdout, cmp.Or(cfg.Output.Format, "table"), available); err != nil {
+			slog.WarnContext(ctx, "could not print findings", "err", err)
+		}
+		return
+	}
+	if cfg.Output.File != "" {
+		if err := export.WriteFile(cfg.Output.File, cfg.Output.Format, available); err != nil {
+			slog.ErrorContext(ctx, "could not write findings", "file", cfg.Output.File, "err", err)
+		} else {
+			slog.InfoContext(ctx, "results written", "file", cfg.Output.File)
+		}
+	}
+
+	notifiers := cfg.Notifiers()
+	if !cfg.Notify.Partial || len(notifiers) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, partialTimeout)
+	defer cancel()
+	toSend, err := findings.Unreported(ctx, store, available)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load reported findings, sending all", "err", err)
+		toSend = available
+	}
+	toSend, enough := cfg.Notify.Select(esc.rest(ctx, toSend))
+	switch {
+	case cfg.DigestWeekday != "":
+		slog.InfoContext(ctx, "holding findings for the digest", "domains", len(toSend))
+		return
+	case !enough:
+		slog.InfoContext(ctx, "holding findings for the next report", "domains", len(toSend), "min_domains", cfg.Notify.MinDomains)
+		return
+	case len(toSend) == 0:
+		slog.InfoContext(ctx, "no new available domains found, skipping partial report")
+		return
+	}
+	report := notify.Report{
+		Title:   "Partial Run",
+		Domains: toSend,
+		Date:    time.Now(),
+		Summary: &notify.Summary{ScanStats: stats, Duration: time.Since(startedAt).Round(time.Second)},
+	}
+	failed := false
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, report); err != nil {
+			slog.ErrorContext(ctx, "send partial report", "notifier", n.Name(), "err", err)
+			failed = true
+			continue
+		}
+		slog.InfoContext(ctx, "partial report sent", "notifier", n.Name())
+	}
+	if failed {
+		path, err := notify.SaveReport(undeliveredDir(cfg.Database), report)
+		if err != nil {
+			slog.ErrorContext(ctx, "could not save undelivered report", "err", err)
+		} else {
+			slog.InfoContext(ctx, "undelivered report saved", "file", path)
+		}
+		return
+	}
+	if err := findings.MarkReported(ctx, store, toSend, nil); err != nil {
+		slog.WarnContext(ctx, "could not save reported findings", "err", err)
+	}
+}
@@ -0,0 +1,645 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/debug"
+	"github.com/berckan/domainhunter/internal/drop"
+	"github.com/berckan/domainhunter/internal/enrich"
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/findings"
+	"github.com/berckan/domainhunter/internal/logging"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+	"github.com/berckan/domainhunter/internal/scan"
+	"github.com/berckan/domainhunter/internal/storage"
+	"github.com/berckan/domainhunter/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func runScan(args []string) error {
+	fs := newFlagSet("scan")
+	configPath := addConfigFlag(fs)
+	lengths := fs.String("lengths", "", "comma-separated name lengths to scan, e.g. 1,2 (replaces configured scans)")
+	tlds := fs.String("tlds", "", "comma-separated TLDs, e.g. io,dev,ai")
+	prefix := fs.String("prefix", "", "only names starting with this prefix")
+	charset := fs.String("charset", "", "characters to use: alnum, letters or digits")
+	diff := fs.Bool("diff", false, "only report domains that became available since the previous run")
+	emailTemplate := fs.String("email-template", "", "html/template file for report emails (default: built-in)")
+	notifyPartial := fs.Bool("notify-partial", false, "when SIGTERM or SIGINT stops the run, report what it found so far (default: config's notify.partial)")
+	daemon := fs.Bool("daemon", false, "keep running and scan on --schedule")
+	schedExpr := fs.String("schedule", "", `cron schedule for --daemon, e.g. "0 7 * * *" (default: SCAN_SCHEDULE, config or daily at 7:00)`)
+	dryRun := fs.Bool("dry-run", false, "print findings to stdout and skip notifications, output files and history")
+	out := fs.String("out", "", "also write findings to this file (replaces output.file)")
+	results := fs.String("results", "", `append every checked domain to this file as NDJSON while scanning, "-" for stdout (replaces output.results)`)
+	unchecked := fs.String("unchecked", "", "when the budget stops a run, write the domains it left unchecked to this file, one per line (replaces output.unchecked)")
+	format := fs.String("format", "", "json, ndjson, csv or table for --out (default: by extension) and --dry-run (default: table)")
+	addColorFlag(fs)
+	chunkSize := fs.Int("chunk-size", 0, "domains checked between checkpoints (default: config or 1000)")
+	failOnFlag := fs.String("fail-on", "", "exit non-zero when the run finds: none-available (3), errors or errors=RATE, e.g. errors=5% (4)")
+	resume := fs.Bool("resume", false, "continue the last interrupted run with its original scope, however old")
+	checkFlags := addCheckerFlags(fs)
+	debugAddr := fs.String("debug-addr", "", "serve pprof and expvar on this localhost address while running, e.g. localhost:6060")
+	maxWhois := fs.Int64("max-whois", 0, "stop the run after this many WHOIS queries; the next run carries on (default: config or no limit)")
+	maxDuration := fs.Duration("max-duration", 0, "stop the run after this long; the next run carries on (default: config or no limit)")
+	stealth := fs.Duration("stealth", 0, "spread each run's queries evenly over this long, e.g. 12h (default: config or full speed)")
+	jitter := fs.Float64("jitter", 0, "with --stealth, randomize each gap between queries by up to this fraction of it (default: config or 0.3)")
+	logs := addLogFlags(fs)
+	fs.Parse(args)
+
+	if *dryRun || *results == "-" {
+		// Keep stdout for the findings or results alone
+		logw = os.Stderr
+	}
+	if err := logs.setup(logw); err != nil {
+		return err
+	}
+	if *format != "" && !export.Valid(*format) {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if *debugAddr != "" {
+		addr, err := debug.Serve(*debugAddr)
+		if err != nil {
+			return fmt.Errorf("debug endpoints: %w", err)
+		}
+		slog.Info("debug endpoints", "url", "http://"+addr.String()+"/debug/pprof/")
+	}
+
+	scope, err := parseScope(*lengths, *tlds, *prefix, *charset)
+	if err != nil {
+		return err
+	}
+	fail, err := parseFailOn(*failOnFlag)
+	if err != nil {
+		return err
+	}
+	if *daemon && fail.set() {
+		return errors.New("--fail-on doesn't apply to --daemon, which keeps running")
+	}
+	if *resume && (*lengths != "" || *tlds != "" || *prefix != "" || *charset != "") {
+		slog.Warn("scope flags only apply if there is no interrupted run to resume")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	cfg.ApplyScope(scope)
+	if *emailTemplate != "" {
+		cfg.Notify.EmailTemplate = *emailTemplate
+	}
+	if *notifyPartial {
+		cfg.Notify.Partial = true
+	}
+	if *out != "" {
+		cfg.Output.File = *out
+	}
+	if *results != "" {
+		cfg.Output.Results = *results
+	}
+	if *unchecked != "" {
+		cfg.Output.Unchecked = *unchecked
+	}
+	if *chunkSize > 0 {
+		cfg.ChunkSize = *chunkSize
+	}
+	checkFlags.apply(&cfg.Concurrency)
+	if *maxWhois > 0 {
+		cfg.Budget.MaxWHOIS = *maxWhois
+	}
+	if *maxDuration > 0 {
+		cfg.Budget.MaxDuration = *maxDuration
+	}
+	if *stealth > 0 {
+		cfg.Stealth.Window = *stealth
+	}
+	if *jitter > 0 {
+		cfg.Stealth.Jitter = *jitter
+	}
+	if *format != "" {
+		cfg.Output.Format = *format
+	}
+	if err := cfg.Validate(); err != nil && !(*dryRun && errors.Is(err, config.ErrNoOutput)) {
+		if *configPath == "" && len(cfg.Notifiers()) == 0 {
+			return errors.New("no notifier configured; set EMAIL_TO with RESEND_API_KEY or SMTP_HOST, or a Slack, Discord, Telegram or webhook variable (or use --config)")
+		}
+		return fmt.Errorf("config: %w", err)
+	}
+	if cfg.Output.Results == "-" && logw != os.Stderr {
+		// Keep stdout for the results alone
+		logw = os.Stderr
+		logs.setup(logw)
+	}
+
+	store, err := storage.Open(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer store.Close()
+
+	// A signal stops the checks in flight, and the run saves what it found;
+	// progress is checkpointed. A second signal kills it outright.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// Traces go to the OTLP endpoint in OTEL_EXPORTER_OTLP_ENDPOINT, if set
+	shutdownTracing, err := tracing.Setup(ctx, "domainhunter-daily-scan")
+	if err != nil {
+		return fmt.Errorf("tracing: %w", err)
+	}
+	defer flushTraces(shutdownTracing)
+
+	opts := runOptions{diff: *diff, dryRun: *dryRun, resume: *resume, failOn: fail}
+	if *daemon {
+		expr := cfg.Schedule
+		if *schedExpr != "" {
+			expr = *schedExpr
+		}
+		if err := runDaemon(ctx, cfg, store, opts, expr); err != nil {
+			return fmt.Errorf("daemon: %w", err)
+		}
+		return nil
+	}
+	if err := run(ctx, cfg, store, opts); err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			return err
+		}
+		return fmt.Errorf("run failed: %w", err)
+	}
+	return nil
+}
+
+// tracer traces each run, as the root of its checker spans
+var tracer = otel.Tracer("github.com/berckan/domainhunter/cmd/domainhunter")
+
+// logw receives log lines; stderr when stdout carries findings or results
+var logw io.Writer = os.Stdout
+
+// runOptions are the per-run flags
+type runOptions struct {
+	diff   bool
+	dryRun bool
+	resume bool
+	failOn failOn
+}
+
+// run performs one scan, saves it and sends the report
+func run(ctx context.Context, cfg *config.Scan, store storage.Store, opts runOptions) (err error) {
+	ctx, span := tracer.Start(ctx, "daily-scan.run")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	// Every line logged for this run carries its ID
+	ctx = logging.With(ctx, "run_id", logging.NewID())
+	slog.InfoContext(ctx, "starting daily domain scan")
+	began := time.Now() // this attempt; startedAt goes back to a resumed run's start
+
+	// Progress is checkpointed per chunk, so a killed run resumes next time.
+	// Finished scans keep theirs until the whole run is done.
+	runner := scan.NewRunner(cfg.Checker(), store)
+	debug.Watch(runner.Checker)
+	runner.KeepCompleted = true
+	if cfg.ChunkSize > 0 {
+		runner.ChunkSize = cfg.ChunkSize
+	}
+	runner.Budget.MaxWHOIS = cfg.Budget.MaxWHOIS
+	if cfg.Budget.MaxDuration > 0 {
+		runner.Budget.Deadline = time.Now().Add(cfg.Budget.MaxDuration)
+	}
+	runner.OnProgress = func(done, total int) {
+		slog.InfoContext(ctx, "progress", "done", done, "total", total)
+	}
+	// Findings are logged as each chunk completes, and escalation rules
+	// send theirs then rather than waiting for the whole run
+	recap := isToday(cfg.RecapWeekday)
+	esc := &escalator{cfg: cfg.Notify, store: store, dryRun: opts.dryRun, sent: make(map[string]bool)}
+	runner.OnChunk = func(available, _ []models.DomainResult) {
+		if len(available) == 0 {
+			return
+		}
+		slog.InfoContext(ctx, "available", "domains", domainNames(available))
+		if !recap {
+			esc.chunk(ctx, available)
+		}
+	}
+	// Every result is on disk as soon as its chunk is checked, so even a
+	// killed run leaves usable output
+	if cfg.Output.Results != "" && !opts.dryRun {
+		results, err := export.OpenAppender(cfg.Output.Results)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", cfg.Output.Results, err)
+		}
+		defer results.Close()
+		runner.OnResult = results.Write
+	}
+	var allAvailable, dropping []models.DomainResult
+	var stats models.ScanStats
+	var checked scan.Concat
+	var scopes []string
+	var keys []string
+	specs := cfg.Scans
+	startedAt := time.Now()
+	// A finished run that meets a --fail-on condition fails, once reported;
+	// a paused one has more to check
+	finished := false
+	defer func() {
+		if err == nil && finished {
+			err = opts.failOn.check(stats)
+		}
+	}()
+
+	// A run paused by its budget is carried on without asking
+	if !opts.dryRun {
+		rec, err := loadRunRecord(ctx, store)
+		if err != nil {
+			slog.WarnContext(ctx, "could not load interrupted run", "err", err)
+		}
+		switch {
+		case len(rec.Scans) > 0 && (opts.resume || rec.Paused):
+			// The recorded scope wins over config and flags, and checkpoints
+			// of any age are picked up
+			specs, startedAt = rec.Scans, rec.StartedAt
+			runner.MaxAge = 0
+			slog.InfoContext(ctx, "resuming run", "started_at", startedAt, "paused", rec.Paused)
+		case opts.resume:
+			slog.InfoContext(ctx, "no interrupted run to resume, starting fresh")
+		}
+	}
+	if !opts.dryRun {
+		if err := saveRunRecord(ctx, store, runRecord{Scans: specs, StartedAt: startedAt}); err != nil {
+			slog.WarnContext(ctx, "could not record run", "err", err)
+		}
+	}
+
+	// Stealth mode spreads the whole run over its window, so it needs to
+	// know how much the run holds
+	if cfg.Stealth.Window > 0 {
+		total := 0
+		for _, spec := range specs {
+			d, _ := specDomains(cfg, spec)
+			total += d.Len()
+		}
+		runner.Spread = &scan.Spread{End: time.Now().Add(cfg.Stealth.Window), Remaining: total}
+		slog.InfoContext(ctx, "stealth mode", "domains", total, "window", cfg.Stealth.Window, "end", runner.Spread.End)
+	}
+
+	for i, spec := range specs {
+		// Higher-priority TLDs are checked first, so a scan cut short by its
+		// window has covered the TLDs that matter most
+		domains, tlds := specDomains(cfg, spec)
+		slog.InfoContext(ctx, "scanning", "name", spec.Name, "length", spec.Length, "prefix", spec.Prefix, "tlds", tlds, "domains", domains.Len())
+
+		// Dry runs keep their own checkpoints so they never consume a real one
+		key := "daily:" + spec.Name
+		if opts.dryRun {
+			key = "dry-run:" + spec.Name
+		}
+		keys = append(keys, key)
+		res, err := runner.RunDomains(ctx, key, domains)
+		// A signal, like a spent budget, leaves the rest of the run to the
+		// next one; it also saves what was found, the run being over
+		if interrupted := err != nil && ctx.Err() != nil; interrupted || errors.Is(err, scan.ErrBudgetSpent) {
+			stats.Merge(res.Stats)
+			unchecked := scan.Concat{res.Unchecked}
+			for _, rest := range specs[i+1:] {
+				d, _ := specDomains(cfg, rest)
+				unchecked = append(unchecked, d)
+			}
+			reason := err
+			if interrupted {
+				ctx, reason = context.WithoutCancel(ctx), errInterrupted
+			}
+			pauseRun(ctx, store, runRecord{Scans: specs, StartedAt: startedAt}, opts.dryRun, stats.Checked, unchecked, cfg.Output.Unchecked, reason)
+			if !interrupted {
+				return nil
+			}
+			stats.Unchecked = unchecked.Len()
+			flushInterrupted(ctx, cfg, store, esc, opts.dryRun, append(allAvailable, res.Available...), stats, startedAt)
+			return errInterrupted
+		}
+		if err != nil {
+			return fmt.Errorf("scan interrupted: %w", err)
+		}
+		allAvailable = append(allAvailable, res.Available...)
+		dropping = append(dropping, res.Dropping...)
+		stats.Merge(res.Stats)
+		checked = append(checked, domains)
+		scopes = append(scopes, spec.Name)
+	}
+
+	finished = true
+
+	// Runs are only compared with earlier runs over the same candidates
+	params := "scans=" + strings.Join(scopes, ",") + " fingerprint=" + checked.Fingerprint()[:12]
+	previous, hasPrevious, err := findings.PreviousRun(ctx, store, "daily", params)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load previous run", "err", err)
+	}
+
+	// Keywords are checked on new TLDs as they open. Being one-offs, they're
+	// left out of params so the run still compares with the previous one.
+	launched, err := scanLaunches(ctx, cfg.Launches, store, runner, opts.dryRun)
+	if err != nil {
+		return err
+	}
+	allAvailable = append(allAvailable, launched.res.Available...)
+	dropping = append(dropping, launched.res.Dropping...)
+	stats.Merge(launched.res.Stats)
+	checked = append(checked, scan.List(launched.checked))
+	keys = append(keys, launched.keys...)
+
+	run := models.Scan{
+		Kind:       "daily",
+		Params:     params,
+		Checked:    checked.Len(),
+		Available:  domainNames(allAvailable),
+		Stats:      stats,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+	}
+	// A dry run leaves no history behind
+	if !opts.dryRun {
+		if err := store.SaveScan(ctx, &run); err != nil {
+			slog.WarnContext(ctx, "could not save run", "err", err)
+		}
+	}
+	finishRun(ctx, store, keys, !opts.dryRun)
+	if !opts.dryRun {
+		markLaunched(ctx, store, launched.tlds)
+		if cfg.Output.Unchecked != "" {
+			clearUnchecked(ctx, cfg.Output.Unchecked)
+		}
+	}
+
+	// Domains on their way to deletion go on the watch list, where the
+	// server's watcher follows them to the drop
+	if len(dropping) > 0 && !opts.dryRun {
+		n, err := drop.Track(ctx, store, dropping)
+		if err != nil {
+			slog.WarnContext(ctx, "could not track dropping domains", "err", err)
+		}
+		slog.InfoContext(ctx, "dropping domains", "seen", len(dropping), "newly_watched", n)
+	}
+
+	slog.InfoContext(ctx, "scan complete", "available", len(allAvailable), "checked", stats.Checked, "duration", time.Since(startedAt).Round(time.Second))
+	if stats.Errors > 0 {
+		slog.WarnContext(ctx, "lookups failed and were counted as taken", "failed", stats.Errors, "checked", stats.Checked)
+	}
+	logWorkers(ctx, runner.Checker.WorkerStats())
+	logServers(ctx, runner.Checker, time.Since(began))
+	if cs := runner.Checker.DNSCacheStats(); cs.Hits > 0 {
+		slog.InfoContext(ctx, "dns cache", "hits", cs.Hits, "lookups", cs.Hits+cs.Misses, "entries", cs.Entries)
+	}
+
+	if cfg.Output.File != "" && !opts.dryRun {
+		if err := export.WriteFile(cfg.Output.File, cfg.Output.Format, allAvailable); err != nil {
+			return fmt.Errorf("writing %s: %w", cfg.Output.File, err)
+		}
+		slog.InfoContext(ctx, "results written", "file", cfg.Output.File)
+	}
+
+	notifiers := cfg.Notifiers()
+	if len(notifiers) == 0 && !opts.dryRun {
+		return nil
+	}
+
+	// Only report findings that weren't in a previous email (or, with --diff,
+	// weren't available in the previous run), except on recap day
+	toSend := allAvailable
+	title := "Daily Report"
+	switch {
+	case recap:
+		title = "Weekly Recap"
+	case opts.diff:
+		if hasPrevious {
+			toSend = findings.NewSince(previous, allAvailable)
+		}
+		slog.InfoContext(ctx, "newly available since previous run", "domains", len(toSend))
+	default:
+		fresh, err := findings.Unreported(ctx, store, allAvailable)
+		if err != nil {
+			slog.WarnContext(ctx, "could not load reported findings, sending all", "err", err)
+		} else {
+			toSend = fresh
+			slog.InfoContext(ctx, "new since last report", "domains", len(toSend))
+		}
+	}
+
+	// Escalation rules send the findings they match right away, whatever
+	// the quiet conditions or digest day; recaps only repeat past news
+	if !recap {
+		toSend = esc.rest(ctx, toSend)
+	}
+
+	// Quiet conditions: findings outside notify.tlds are dropped, and too
+	// few for notify.min_domains are held back for a later report
+	toSend, enough := cfg.Notify.Select(toSend)
+	hold := ""
+	if !enough {
+		hold = fmt.Sprintf("%d new domains, fewer than min_domains (%d)", len(toSend), cfg.Notify.MinDomains)
+	}
+
+	// In digest mode new findings pile up unreported until digest day,
+	// when they go out along with the week's other changes
+	var digest *notify.Digest
+	if cfg.DigestWeekday != "" {
+		if isToday(cfg.DigestWeekday) {
+			title, hold = "Weekly Digest", ""
+			digest, err = buildDigest(ctx, store, allAvailable, checked.Contains)
+			if err != nil {
+				return fmt.Errorf("building digest: %w", err)
+			}
+		} else {
+			hold = fmt.Sprintf("%d new domains, waiting for the %s digest", len(toSend), cfg.DigestWeekday)
+		}
+	}
+
+	if opts.dryRun {
+		names := make([]string, len(notifiers))
+		for i, n := range notifiers {
+			names[i] = n.Name()
+		}
+		if hold == "" {
+			slog.InfoContext(ctx, "dry run: would send report", "title", title, "domains", len(toSend), "notifiers", names)
+		} else {
+			slog.InfoContext(ctx, "dry run: would hold back report", "reason", hold)
+		}
+		format := cfg.Output.Format
+		if format == "" {
+			format = "table"
+		}
+		return export.Write(os.Stdout, format, allAvailable)
+	}
+
+	if hold != "" {
+		slog.InfoContext(ctx, "holding findings for the next report", "reason", hold)
+		return nil
+	}
+
+	// Enrichment only covers what's about to be reported; a failed lookup
+	// leaves findings without it rather than holding up the report
+	if enrichers := cfg.Enrich.Enrichers(enrich.StoreCache{Store: store}); len(enrichers) > 0 && len(toSend) > 0 {
+		slog.InfoContext(ctx, "enriching", "domains", len(toSend))
+		if err := enrich.Run(ctx, enrichers, toSend); err != nil {
+			slog.WarnContext(ctx, "enrichment incomplete", "err", err)
+		}
+		if n := len(toSend); cfg.Enrich.MinAppraisal > 0 {
+			toSend = cfg.Enrich.Appraised(toSend)
+			slog.InfoContext(ctx, "appraised", "kept", len(toSend), "domains", n, "min_appraisal", cfg.Enrich.MinAppraisal)
+		}
+	}
+
+	// Every channel gets the report; each filters it per recipient
+	failed := false
+	report := notify.Report{
+		Title:   title,
+		Domains: toSend,
+		Date:    time.Now(),
+		Summary: &notify.Summary{ScanStats: stats, Duration: run.FinishedAt.Sub(startedAt).Round(time.Second)},
+		Digest:  digest,
+	}
+	// A scan with failed lookups is reported even when it found nothing,
+	// since its "nothing" may just be the failures
+	if len(toSend) == 0 && digest.Empty() && stats.Errors == 0 {
+		slog.InfoContext(ctx, "no new available domains found, skipping notifications")
+	} else {
+		for _, n := range notifiers {
+			if err := n.Notify(ctx, report); err != nil {
+				slog.ErrorContext(ctx, "send report", "notifier", n.Name(), "err", err)
+				failed = true
+				continue
+			}
+			slog.InfoContext(ctx, "report sent", "notifier", n.Name())
+		}
+	}
+	if failed {
+		// Keep the report on disk so a long scan's findings are never lost
+		path, err := notify.SaveReport(undeliveredDir(cfg.Database), report)
+		if err != nil {
+			slog.ErrorContext(ctx, "could not save undelivered report", "err", err)
+		} else {
+			slog.InfoContext(ctx, "undelivered report saved", "file", path)
+		}
+		return errors.New("some notifications failed")
+	}
+
+	if err := findings.MarkReported(ctx, store, allAvailable, checked.Contains); err != nil {
+		slog.WarnContext(ctx, "could not save reported findings", "err", err)
+	}
+	if digest != nil {
+		if err := store.SetSetting(ctx, digestKey, time.Now().Format(time.RFC3339)); err != nil {
+			slog.WarnContext(ctx, "could not record digest", "err", err)
+		}
+	}
+	return nil
+}
+
+// specDomains returns spec's candidates, higher-priority TLDs first, and
+// how many TLDs they span
+func specDomains(cfg *config.Scan, spec config.ScanSpec) (scan.Concat, int) {
+	tiers, _ := cfg.Candidates(spec) // validated on load
+	var domains scan.Concat
+	var tlds int
+	for _, tier := range tiers {
+		domains = append(domains, tier)
+		tlds += len(tier.TLDs)
+	}
+	return domains, tlds
+}
+
+// logWorkers summarizes how evenly each phase's workers shared the load;
+// WHOIS has a phase per registry server
+func logWorkers(ctx context.Context, stats []checker.WorkerStats) {
+	for len(stats) > 0 {
+		n := 1
+		for n < len(stats) && stats[n].Phase == stats[0].Phase {
+			n++
+		}
+		ws := stats[:n]
+		stats = stats[n:]
+
+		checked, errs := 0, 0
+		least, most := ws[0].Busy, ws[0].Busy
+		for _, w := range ws {
+			checked += w.Checked
+			errs += w.Errors
+			least, most = min(least, w.Busy), max(most, w.Busy)
+		}
+		slog.InfoContext(ctx, "workers", "phase", ws[0].Phase, "workers", len(ws), "checked", checked, "errors", errs,
+			"busy_min", least.Round(time.Second), "busy_max", most.Round(time.Second))
+	}
+}
+
+// isToday reports whether day, e.g. "monday", is today's weekday
+func isToday(day string) bool {
+	return day != "" && strings.EqualFold(time.Now().Weekday().String(), day)
+}
+
+// undeliveredDir is where reports go when notifications fail: next to the
+// database, or the working directory for an in-memory one
+func undeliveredDir(dbPath string) string {
+	if dbPath == ":memory:" {
+		return "undelivered"
+	}
+	return filepath.Join(filepath.Dir(dbPath), "undelivered")
+}
+
+// domainNames extracts the domain names from results
+func domainNames(results []models.DomainResult) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Domain
+	}
+	return names
+}
+
+// parseScope turns the scope flags into a config override
+func parseScope(lengths, tlds, prefix, charset string) (config.Scope, error) {
+	sc := config.Scope{
+		Prefix:  strings.ToLower(strings.TrimSpace(prefix)),
+		Charset: charset,
+	}
+	for _, l := range splitList(lengths) {
+		n, err := strconv.Atoi(l)
+		if err != nil {
+			return sc, fmt.Errorf("invalid length %q", l)
+		}
+		sc.Lengths = append(sc.Lengths, n)
+	}
+	sc.TLDs = splitList(tlds)
+	return sc, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
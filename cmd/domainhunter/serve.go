@@ -0,0 +1,188 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/autobuy"
+	"github.com/berckan/domainhunter/internal/cache"
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/debug"
+	"github.com/berckan/domainhunter/internal/handlers"
+	"github.com/berckan/domainhunter/internal/storage"
+	"github.com/berckan/domainhunter/internal/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	configPath := addConfigFlag(fs)
+	port := fs.String("port", "", "HTTP listen port (default: PORT, config or 8080)")
+	db := fs.String("db", "", "database file (default: DB_PATH, config or domainhunter.db)")
+	debugAddr := fs.String("debug-addr", "", "serve pprof and expvar on this localhost address, e.g. localhost:6060 (default: DEBUG_ADDR, config, or off)")
+	tlsCert := fs.String("tls-cert", "", "serve HTTPS with this PEM certificate, given with --tls-key (default: TLS_CERT or config)")
+	tlsKey := fs.String("tls-key", "", "PEM private key for --tls-cert (default: TLS_KEY or config)")
+	var autocertDomains stringList
+	fs.Var(&autocertDomains, "autocert-domain", "serve HTTPS with a Let's Encrypt certificate for this host name, answering challenges on port 80; repeatable or comma-separated (default: AUTOCERT_DOMAINS or config)")
+	autocertCache := fs.String("autocert-cache", "", "directory for Let's Encrypt certificates and account key (default: AUTOCERT_CACHE, config or ./autocert)")
+	checkFlags := addCheckerFlags(fs)
+	logs := addLogFlags(fs)
+	fs.Parse(args)
+	if err := logs.setup(os.Stderr); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	srv := &cfg.Server
+	srv.Port = cmp.Or(*port, srv.Port)
+	srv.DebugAddr = cmp.Or(*debugAddr, srv.DebugAddr)
+	cfg.Database = cmp.Or(*db, cfg.Database)
+	srv.AutocertCache = cmp.Or(*autocertCache, srv.AutocertCache)
+	// Either TLS flag replaces the other way of getting a certificate
+	if *tlsCert != "" || *tlsKey != "" {
+		srv.TLSCert, srv.TLSKey, srv.AutocertDomains = *tlsCert, *tlsKey, nil
+	}
+	if len(autocertDomains) > 0 {
+		srv.AutocertDomains = splitList(strings.Join(autocertDomains, ","))
+		srv.TLSCert, srv.TLSKey = "", ""
+	}
+	if err := srv.Validate(); err != nil {
+		return err
+	}
+
+	if srv.DebugAddr != "" {
+		addr, err := debug.Serve(srv.DebugAddr)
+		if err != nil {
+			return fmt.Errorf("debug endpoints: %w", err)
+		}
+		slog.Info("debug endpoints", "url", "http://"+addr.String()+"/debug/pprof/")
+	}
+
+	if err := handlers.LoadTemplates("web/templates/*.html"); err != nil {
+		return fmt.Errorf("templates: %w", err)
+	}
+	store, err := storage.Open(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer store.Close()
+	handlers.SetStore(store)
+
+	ab := config.DefaultAutoBuy()
+	c, err := newChecker(cfg, checkFlags)
+	if err != nil {
+		return err
+	}
+	handlers.SetChecker(c)
+	debug.Watch(c)
+
+	// Shared cache for multi-instance deployments
+	if url := srv.RedisURL; url != "" {
+		c, err := cache.NewRedis(url)
+		if err != nil {
+			return fmt.Errorf("redis: %w", err)
+		}
+		defer c.Close()
+		handlers.SetCache(c)
+		slog.Info("using redis cache")
+	}
+
+	// WHOIS telemetry survives restarts
+	ctx := context.Background()
+	handlers.PersistTelemetry(ctx, time.Minute)
+	if err := handlers.StartJobs(ctx, 2); err != nil {
+		return fmt.Errorf("start jobs: %w", err)
+	}
+	handlers.SetAdminToken(srv.AdminToken)
+	proxies, _ := srv.TrustedProxies() // validated on load
+	handlers.SetTrustedProxies(proxies, os.Getenv("FLY_APP_NAME") != "")
+	handlers.SetAlertRemind(srv.AlertRemind)
+	bo := config.DefaultBackorder()
+	handlers.SetBackorders(bo.Providers(), bo.MonthlyBudget)
+	handlers.SetAutoBuy(ab.Registrars(), &autobuy.Audit{Path: ab.AuditLog})
+	handlers.SetCTKeywords(srv.CTKeywords)
+	handlers.SetPriceTracking(ab.PriceSources(), config.PriceTLDs())
+	handlers.SetTLDPriority(cfg.TLDPriority())
+	// Watchlist alerts go to the same channels as the daily scan
+	handlers.WatchDomains(ctx, srv.WatchInterval, cfg.Notifiers())
+
+	// Traces go to the OTLP endpoint in OTEL_EXPORTER_OTLP_ENDPOINT, if set
+	if _, err := tracing.Setup(ctx, "domainhunter-server"); err != nil {
+		return fmt.Errorf("tracing: %w", err)
+	}
+
+	// Routes get their own mux, keeping the debug endpoints that register
+	// themselves on the default one off the public port. Each route's
+	// requests are traced under its pattern.
+	mux := http.NewServeMux()
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, otelhttp.NewHandler(h, pattern))
+	}
+
+	// Static files
+	static := http.FileServer(http.Dir("web/static"))
+	mux.Handle("/static/", http.StripPrefix("/static/", static))
+
+	// Routes
+	handle("/", handlers.Home)
+	handle("/check", handlers.RateLimit(handlers.CheckDomain))
+	handle("/check-bulk", handlers.RateLimit(handlers.CheckBulk))
+	handle("/scan-short", handlers.RateLimit(handlers.ScanShort))
+	handle("/scan-stream", handlers.AdminOnly(handlers.ScanStream))
+	handle("/check-multitld", handlers.RateLimit(handlers.CheckMultiTLD))
+	handle("/watch", handlers.AdminOnly(handlers.WatchDomain))
+	handle("/jobs/{id}", handlers.JobStatus)
+	handle("/admin", handlers.AdminOnly(handlers.AdminOverview))
+	handle("/admin/health", handlers.AdminOnly(handlers.AdminHealth))
+	handle("/admin/backup", handlers.AdminOnly(handlers.AdminBackup))
+
+	server := &http.Server{Addr: ":" + srv.Port, Handler: mux}
+	switch {
+	case len(srv.AutocertDomains) > 0:
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(srv.AutocertDomains...),
+			Cache:      autocert.DirCache(srv.AutocertCache),
+		}
+		server.TLSConfig = m.TLSConfig()
+		// Let's Encrypt checks port 80 for HTTP-01 challenges; the rest of
+		// plain HTTP is sent to HTTPS. Behind port forwarding that leaves 80
+		// to something else, TLS-ALPN-01 on 443 still works.
+		go func() {
+			err := http.ListenAndServe(":80", m.HTTPHandler(redirectHTTPS(srv.Port)))
+			slog.Warn("not answering HTTP-01 challenges", "err", err)
+		}()
+		slog.Info("server starting", "url", "https://"+net.JoinHostPort(srv.AutocertDomains[0], srv.Port), "autocert_cache", srv.AutocertCache)
+		return server.ListenAndServeTLS("", "")
+	case srv.TLSCert != "":
+		slog.Info("server starting", "url", "https://localhost:"+srv.Port)
+		return server.ListenAndServeTLS(srv.TLSCert, srv.TLSKey)
+	}
+	slog.Info("server starting", "url", "http://localhost:"+srv.Port)
+	return server.ListenAndServe()
+}
+
+// redirectHTTPS sends requests to the same URL over HTTPS on port
+func redirectHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/scan"
+)
+
+func runTUI(args []string) error {
+	fs := newFlagSet("tui")
+	configPath := addConfigFlag(fs)
+	lengths := fs.String("lengths", "", "comma-separated name lengths to scan, e.g. 1,2 (replaces configured scans)")
+	tlds := fs.String("tlds", "", "comma-separated TLDs to scan, or to check bare names under")
+	tldList := fs.String("tld-list", "", "check bare names under a named TLD list: premium, common or one from the config's tld_lists")
+	prefix := fs.String("prefix", "", "only names starting with this prefix")
+	charset := fs.String("charset", "", "characters to use: alnum, letters or digits")
+	out := fs.String("out", "", `file "s" saves findings to; json, ndjson, csv or table by extension (default: findings-<time>.json)`)
+	checkFlags := addCheckerFlags(fs)
+	names := parseArgs(fs, args)
+
+	// Log lines would tear through the screen
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	c, err := newChecker(cfg, checkFlags)
+	if err != nil {
+		return err
+	}
+
+	// Names given are checked as with check; otherwise the scans are
+	var domains scan.Domains
+	if len(names) > 0 {
+		list, err := expandNames(cfg, names, *tlds, *tldList)
+		if err != nil {
+			return err
+		}
+		domains = scan.List(list)
+	} else {
+		scope, err := parseScope(*lengths, *tlds, *prefix, *charset)
+		if err != nil {
+			return err
+		}
+		cfg.ApplyScope(scope)
+		var all scan.Concat
+		for _, spec := range cfg.Scans {
+			d, _ := specDomains(cfg, spec)
+			all = append(all, d)
+		}
+		domains = all
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := &tuiModel{
+		total:   domains.Len(),
+		tlds:    make(map[string]*tldCount),
+		started: time.Now(),
+		gate:    &gate{resume: make(chan struct{})},
+		cancel:  cancel,
+		out:     *out,
+	}
+	p := tea.NewProgram(m)
+	go func() {
+		err := c.Stream(ctx, m.gate.feed(ctx, domains), func(r models.DomainResult) error {
+			p.Send(resultMsg(r))
+			return nil
+		})
+		p.Send(doneMsg{err})
+	}()
+	_, err = p.Run()
+	return err
+}
+
+// tuiFeedLines is how many available domains the feed keeps on screen at
+// most; a short terminal shows fewer
+const tuiFeedLines = 15
+
+// tuiModel is the state of the tui command's screen
+type tuiModel struct {
+	total, checked, available, errors int
+	tlds                              map[string]*tldCount
+	tldOrder                          []string
+	found                             []models.DomainResult // available and premium, in order found
+
+	started time.Time
+	elapsed time.Duration // frozen once done
+	done    bool
+	err     error
+	status  string
+	width   int
+	height  int
+
+	gate   *gate
+	cancel context.CancelFunc
+	out    string
+}
+
+// tldCount are one TLD's counters
+type tldCount struct {
+	checked, available int
+}
+
+type (
+	resultMsg models.DomainResult
+	doneMsg   struct{ err error }
+	tickMsg   time.Time
+)
+
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// Init implements tea.Model
+func (m *tuiModel) Init() tea.Cmd {
+	return tick()
+}
+
+// Update implements tea.Model
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			m.cancel()
+			return m, tea.Quit
+		case "p", "space":
+			if m.done {
+				break
+			}
+			if m.gate.toggle() {
+				m.status = "paused; checks in flight finish, p resumes"
+			} else {
+				m.status = ""
+			}
+		case "s":
+			m.status = m.save()
+		}
+
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case resultMsg:
+		m.add(models.DomainResult(msg))
+
+	case doneMsg:
+		m.done, m.elapsed = true, time.Since(m.started)
+		if msg.err != nil && msg.err != context.Canceled {
+			m.err = msg.err
+		}
+		m.status = "done; s saves the findings, q quits"
+
+	case tickMsg:
+		if !m.done {
+			return m, tick()
+		}
+	}
+	return m, nil
+}
+
+// add counts a result
+func (m *tuiModel) add(r models.DomainResult) {
+	tld := r.Domain[strings.LastIndex(r.Domain, ".")+1:]
+	t, ok := m.tlds[tld]
+	if !ok {
+		t = &tldCount{}
+		m.tlds[tld] = t
+		m.tldOrder = append(m.tldOrder, tld)
+	}
+	m.checked++
+	t.checked++
+	if r.Error != "" || r.Status == models.StatusError {
+		m.errors++
+	}
+	if r.Status == models.StatusAvailable || r.Status == models.StatusPremium {
+		if r.Status == models.StatusAvailable {
+			m.available++
+			t.available++
+		}
+		m.found = append(m.found, r)
+	}
+}
+
+// save writes the findings so far, returning what it did
+func (m *tuiModel) save() string {
+	path := m.out
+	if path == "" {
+		path = "findings-" + m.started.Format("20060102-150405") + ".json"
+	}
+	if err := export.WriteFile(path, "", m.found); err != nil {
+		return "saving failed: " + err.Error()
+	}
+	return fmt.Sprintf("saved %d findings to %s", len(m.found), path)
+}
+
+// View implements tea.Model
+func (m *tuiModel) View() tea.View {
+	var b strings.Builder
+	elapsed := m.elapsed
+	if !m.done {
+		elapsed = time.Since(m.started)
+	}
+
+	state := "checking"
+	switch {
+	case m.done:
+		state = "done"
+	case m.gate.isPaused():
+		state = "paused"
+	}
+	fmt.Fprintf(&b, "domainhunter · %s · %s", state, elapsed.Round(time.Second))
+	if secs := elapsed.Seconds(); secs >= 1 {
+		fmt.Fprintf(&b, " · %.0f/s", float64(m.checked)/secs)
+	}
+	b.WriteString("\n\n")
+
+	width := max(min(m.width, 100)-20, 10)
+	filled := 0
+	if m.total > 0 {
+		filled = width * m.checked / m.total
+	}
+	fmt.Fprintf(&b, "[%s%s] %d/%d\n", strings.Repeat("█", filled), strings.Repeat("░", width-filled), m.checked, m.total)
+	fmt.Fprintf(&b, "available %d · errors %d\n\n", m.available, m.errors)
+
+	// TLDs in the order the check reached them
+	b.WriteString("TLD       CHECKED  AVAILABLE\n")
+	for _, tld := range m.tldOrder[:min(len(m.tldOrder), 8)] {
+		t := m.tlds[tld]
+		fmt.Fprintf(&b, ".%-8s %7d  %9d\n", tld, t.checked, t.available)
+	}
+	if n := len(m.tldOrder) - 8; n > 0 {
+		fmt.Fprintf(&b, "… and %d more\n", n)
+	}
+
+	b.WriteString("\nFound\n")
+	lines := tuiFeedLines
+	if m.height > 0 {
+		lines = min(lines, max(m.height-22, 3))
+	}
+	feed := m.found[max(len(m.found)-lines, 0):]
+	if len(feed) == 0 {
+		b.WriteString("  nothing yet\n")
+	}
+	for _, r := range slices.Backward(feed) {
+		line := "  " + r.Domain
+		if r.Status == models.StatusPremium {
+			line += fmt.Sprintf("  premium $%.2f", r.Price)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.err != nil {
+		b.WriteString("error: " + m.err.Error() + "\n")
+	}
+	if m.status != "" {
+		b.WriteString(m.status + "\n")
+	}
+	b.WriteString("p pause · s save · q quit\n")
+	v := tea.NewView(b.String())
+	v.AltScreen = true
+	return v
+}
+
+// gate holds back the domains fed to a check while paused
+type gate struct {
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{} // closed when unpaused
+}
+
+// toggle pauses or resumes, reporting whether it is now paused
+func (g *gate) toggle() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.paused = !g.paused
+	if g.paused {
+		g.resume = make(chan struct{})
+	} else {
+		close(g.resume)
+	}
+	return g.paused
+}
+
+func (g *gate) isPaused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// feed yields domains, waiting before each while paused
+func (g *gate) feed(ctx context.Context, domains scan.Domains) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		const chunk = 1000
+		for from := 0; from < domains.Len(); from += chunk {
+			for _, d := range domains.Slice(from, min(from+chunk, domains.Len())) {
+				g.mu.Lock()
+				paused, resume := g.paused, g.resume
+				g.mu.Unlock()
+				if paused {
+					select {
+					case <-resume:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if !yield(d) {
+					return
+				}
+			}
+		}
+	}
+}
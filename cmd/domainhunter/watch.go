@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/drop"
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/render"
+)
+
+func runWatch(args []string) error {
+	fs := newFlagSet("watch")
+	every := fs.Duration("every", 0, "how often to re-check the domains, e.g. 10m or 168h (default: the server's WATCH_INTERVAL)")
+	list := fs.Bool("list", false, "list the watched domains")
+	remove := fs.Bool("clear", false, "stop watching the domains")
+	format := fs.String("format", "table", "json, ndjson, csv or table for --list")
+	addColorFlag(fs)
+	db := addDBFlags(fs, "database file")
+	checkFlags := addCheckerFlags(fs)
+	domains := parseArgs(fs, args)
+
+	if *list {
+		if !render.Valid(*format) {
+			return fmt.Errorf("unknown format %q", *format)
+		}
+		return listWatches(db, *format)
+	}
+	if len(domains) == 0 {
+		return errors.New("usage: domainhunter watch [--every DURATION | --clear] <domain>... or domainhunter watch --list")
+	}
+	if *remove {
+		return unwatch(db, domains)
+	}
+	if *every != 0 && *every < time.Minute {
+		return errors.New("--every must be at least 1m")
+	}
+
+	// The re-check schedule comes from each domain's WHOIS expiry, as when
+	// watching from the web UI
+	cfg, err := config.Load(*db.config)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	c, err := newChecker(cfg, checkFlags)
+	if err != nil {
+		return err
+	}
+	return editWatches(db, domains, func(w *models.WatchedDomain, found bool) (string, bool) {
+		now := time.Now()
+		if found {
+			if *every == 0 || *every == w.CheckInterval {
+				return "already watched", false
+			}
+			w.SetCheckInterval(*every, now)
+			return "re-checked every " + every.String(), true
+		}
+		w.CheckInterval = *every
+		if rec, err := c.Lookup(context.Background(), w.Domain); err != nil {
+			slog.Warn("watch whois lookup", "domain", w.Domain, "err", err)
+			w.NextCheckAt = now
+		} else {
+			drop.Plan(w, rec, now)
+		}
+		return "watched, next checked " + w.NextCheckAt.Format(time.DateTime), true
+	})
+}
+
+// watchTable prints the watch list
+var watchTable = render.Table[models.WatchedDomain]{
+	Columns: []string{"DOMAIN", "STATUS", "EXPIRES", "NEXT CHECK"},
+	Row: func(w models.WatchedDomain) []string {
+		expires := "-"
+		if !w.ExpiresAt.IsZero() {
+			expires = w.ExpiresAt.Format(time.DateOnly)
+		}
+		return []string{w.Domain, export.StatusCell(w.Status), expires, w.NextCheckAt.Format(time.DateTime)}
+	},
+	CSVColumns: []string{"domain", "status", "expires_at", "next_check_at"},
+	CSVRow: func(w models.WatchedDomain) []string {
+		return []string{w.Domain, string(w.Status), rfc3339(w.ExpiresAt), rfc3339(w.NextCheckAt)}
+	},
+	Colors: func(w models.WatchedDomain) []render.Color {
+		return []render.Color{render.Default, export.StatusColor(w.Status), render.Default, render.Dim}
+	},
+}
+
+// listWatches prints the watch list in format
+func listWatches(db dbFlags, format string) error {
+	store, err := db.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	watches, err := store.ListWatches(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(watches) == 0 && format == "table" {
+		fmt.Println("No domains watched yet")
+		return nil
+	}
+	return watchTable.Write(os.Stdout, format, watches)
+}
+
+// rfc3339 formats t for machines, empty if it is zero
+func rfc3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// unwatch removes domains from the watch list
+func unwatch(db dbFlags, domains []string) error {
+	store, err := db.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return err
+	}
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		msg := "not watched"
+		for _, w := range watches {
+			if w.Domain == domain {
+				if err := store.DeleteWatch(ctx, w.ID); err != nil {
+					return err
+				}
+				msg = "no longer watched"
+			}
+		}
+		fmt.Printf("%s: %s\n", domain, msg)
+	}
+	return nil
+}
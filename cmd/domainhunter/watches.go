@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// editWatches applies edit to the watch of each domain, creating a taken
+// watch for domains that have none. edit reports what it did and whether
+// to save; found tells it whether the watch already existed.
+func editWatches(db dbFlags, domains []string, edit func(w *models.WatchedDomain, found bool) (string, bool)) error {
+	store, err := db.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		i := slices.IndexFunc(watches, func(w models.WatchedDomain) bool { return w.Domain == domain })
+
+		w := models.WatchedDomain{Domain: domain, Status: models.StatusTaken}
+		if i >= 0 {
+			w = watches[i]
+		}
+		msg, save := edit(&w, i >= 0)
+		if save {
+			if i >= 0 {
+				err = store.UpdateWatch(ctx, &w)
+			} else {
+				err = store.AddWatch(ctx, &w)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Printf("%s: %s\n", domain, msg)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/render"
+)
+
+func runWhois(args []string) error {
+	fs := newFlagSet("whois")
+	configPath := addConfigFlag(fs)
+	format := fs.String("format", "text", "text or json")
+	addColorFlag(fs)
+	checkFlags := addCheckerFlags(fs)
+	logs := addLogFlags(fs)
+	args = parseArgs(fs, args)
+
+	if err := logs.setup(os.Stderr); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q (want text or json)", *format)
+	}
+	if len(args) == 0 {
+		return errors.New("usage: domainhunter whois [flags] <domain>...")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	c, err := newChecker(cfg, checkFlags)
+	if err != nil {
+		return err
+	}
+	domains, err := expandNames(cfg, args, "", "")
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	failed := 0
+	start := time.Now()
+	defer func() { logServers(ctx, c, time.Since(start)) }()
+	for i, domain := range domains {
+		server, resp, err := c.RawWhois(ctx, domain)
+		if err != nil {
+			if ctx.Err() != nil || len(domains) == 1 {
+				return err
+			}
+			// Carry on with the rest
+			fmt.Fprintf(os.Stderr, "%s: %v\n", domain, err)
+			failed++
+			continue
+		}
+		w := whoisDump{
+			Domain:  domain,
+			Server:  server,
+			Verdict: checker.ReadWhois(resp),
+			Record:  checker.ParseWhois(resp),
+			Raw:     resp,
+		}
+		if *format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(w)
+		} else {
+			if i > 0 {
+				fmt.Println()
+			}
+			err = w.print(os.Stdout)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lookups failed", failed, len(domains))
+	}
+	return nil
+}
+
+// whoisDump is what the whois command shows of a domain: the response,
+// what the checker parses from it and the verdict it reaches
+type whoisDump struct {
+	Domain  string             `json:"domain"`
+	Server  string             `json:"server"`
+	Verdict checker.Verdict    `json:"verdict"`
+	Record  models.WhoisRecord `json:"record"`
+	Raw     string             `json:"raw"`
+}
+
+// print writes the dump for people: parsed fields first, then the raw
+// response
+func (d whoisDump) print(w io.Writer) error {
+	status := string(d.Verdict.Status)
+	if d.Verdict.Status == models.StatusPremium {
+		status += " (taken unless a registrar quotes a price)"
+	}
+	if render.Colorful(w) {
+		status = render.Paint(status, export.StatusColor(d.Verdict.Status))
+	}
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%-13s %s\n", name, value)
+		}
+	}
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.DateOnly)
+	}
+
+	field("Domain", d.Domain)
+	field("Server", d.Server)
+	field("Verdict", status)
+	field("Rule", d.Verdict.Rule)
+	field("Matched", d.Verdict.Line)
+	field("Registrar", d.Record.Registrar)
+	field("Statuses", strings.Join(d.Record.Statuses, ", "))
+	field("Name servers", strings.Join(d.Record.NameServers, ", "))
+	field("Created", date(d.Record.Created))
+	field("Updated", date(d.Record.Updated))
+	field("Expires", date(d.Record.Expires))
+	field("Drop phase", d.Record.DropPhase())
+
+	_, err := fmt.Fprintf(w, "\n--- raw response ---\n%s\n", strings.TrimRight(d.Raw, "\r\n"))
+	return err
+}
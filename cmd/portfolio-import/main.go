@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/portfolio"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+func main() {
+	from := flag.String("from", "", "import from a registrar account (namecheap, porkbun or cloudflare) instead of a CSV")
+	configPath := flag.String("config", "", "YAML config file to take the database from; DB_PATH overrides it")
+	flag.Usage = func() {
+		fmt.Println("Usage: portfolio-import <domains.csv>")
+		fmt.Println("       portfolio-import --from <registrar>")
+	}
+	flag.Parse()
+	if (*from == "") == (flag.NArg() != 1) {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	source := flag.Arg(0)
+	var domains []models.WatchedDomain
+	var err error
+	if *from != "" {
+		source = *from
+		domains, err = fetch(ctx, *from)
+	} else {
+		domains, err = readCSV(source)
+	}
+	if err != nil {
+		fmt.Printf("Error reading %s: %v\n", source, err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	store, err := storage.Open(cfg.Database)
+	if err != nil {
+		fmt.Printf("Error opening database: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	sum, err := portfolio.Import(ctx, store, domains)
+	if err != nil {
+		fmt.Printf("Error importing: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d domains (%d new, %d updated)\n", len(domains), sum.Added, sum.Updated)
+}
+
+func readCSV(path string) ([]models.WatchedDomain, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return portfolio.ParseCSV(f)
+}
+
+// fetch lists the domains in the named registrar account, set up from the
+// same environment as auto-buy
+func fetch(ctx context.Context, name string) ([]models.WatchedDomain, error) {
+	sources := config.DefaultAutoBuy().PortfolioSources()
+	s, ok := sources[strings.ToLower(name)]
+	if !ok {
+		var names []string
+		for n := range sources {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("no %s account configured (configured: %s)", name, strings.Join(names, ", "))
+	}
+	return s.Domains(ctx)
+}
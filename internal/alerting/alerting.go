@@ -0,0 +1,167 @@
+// Package alerting keeps repeat alerts quiet. It remembers what was last
+// sent for each domain so an unchanged state isn't sent again, and lets
+// domains be snoozed or their alerts acknowledged.
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/notify"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// KindAvailable is the kind recorded for a report's available domains
+const KindAvailable = "available"
+
+// actions are the alert kinds reporting something done on the domain's
+// behalf, which are sent even while it's snoozed
+var actions = map[string]bool{notify.AlertBackorder: true, notify.AlertAutoBuy: true}
+
+// Sent is the last alert of one kind sent for a domain
+type Sent struct {
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sent_at"`
+	// Acked is set once the alert is acknowledged; it then stays quiet
+	// until the state changes
+	Acked bool `json:"acked,omitempty"`
+}
+
+// State is what alerting remembers about a domain
+type State struct {
+	SnoozedUntil time.Time       `json:"snoozed_until,omitzero"`
+	Sent         map[string]Sent `json:"sent,omitempty"`
+}
+
+// Gate filters reports before they're sent. Store holds the state, under
+// one "alerts/<domain>" setting per domain.
+type Gate struct {
+	Store storage.Store
+	// Remind is how long before an unacknowledged alert whose state hasn't
+	// changed is sent again; zero never repeats it
+	Remind time.Duration
+}
+
+// Filter returns r without the alerts and available domains that are
+// snoozed, acknowledged or were sent already, and records the rest as sent
+func (g Gate) Filter(ctx context.Context, r notify.Report) (notify.Report, error) {
+	now := time.Now()
+	states := make(map[string]*State)
+	pass := func(domain, kind, msg string) (bool, error) {
+		s, ok := states[domain]
+		if !ok {
+			st, err := g.State(ctx, domain)
+			if err != nil {
+				return false, err
+			}
+			s, states[domain] = &st, &st
+		}
+		if now.Before(s.SnoozedUntil) && !actions[kind] {
+			return false, nil
+		}
+		if last, ok := s.Sent[kind]; ok && last.Message == msg && (last.Acked || g.Remind == 0 || now.Sub(last.SentAt) < g.Remind) {
+			return false, nil
+		}
+		if s.Sent == nil {
+			s.Sent = make(map[string]Sent)
+		}
+		s.Sent[kind] = Sent{Message: msg, SentAt: now}
+		return true, nil
+	}
+
+	out := r
+	out.Domains, out.Alerts = nil, nil
+	for _, d := range r.Domains {
+		ok, err := pass(d.Domain, KindAvailable, string(d.Status))
+		if err != nil {
+			return r, err
+		}
+		if ok {
+			out.Domains = append(out.Domains, d)
+		}
+	}
+	for _, a := range r.Alerts {
+		ok, err := pass(a.Domain, a.Kind, a.Message)
+		if err != nil {
+			return r, err
+		}
+		if ok {
+			out.Alerts = append(out.Alerts, a)
+		}
+	}
+
+	for domain, s := range states {
+		if err := g.save(ctx, domain, *s); err != nil {
+			return r, err
+		}
+	}
+	return out, nil
+}
+
+// State returns what is remembered about domain
+func (g Gate) State(ctx context.Context, domain string) (State, error) {
+	var s State
+	v, err := g.Store.GetSetting(ctx, key(domain))
+	if errors.Is(err, storage.ErrNotFound) || v == "" {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal([]byte(v), &s)
+	return s, err
+}
+
+// Snooze silences domain's alerts until until, apart from backorders and
+// auto-buys placed for it; a zero time lifts a snooze
+func (g Gate) Snooze(ctx context.Context, domain string, until time.Time) error {
+	return g.edit(ctx, domain, func(s *State) { s.SnoozedUntil = until })
+}
+
+// Ack acknowledges the alerts sent for domain, so they aren't repeated
+// until its state changes. It returns how many were acknowledged.
+func (g Gate) Ack(ctx context.Context, domain string) (int, error) {
+	n := 0
+	err := g.edit(ctx, domain, func(s *State) {
+		for kind, sent := range s.Sent {
+			if !sent.Acked {
+				sent.Acked = true
+				s.Sent[kind] = sent
+				n++
+			}
+		}
+	})
+	return n, err
+}
+
+// Resolve forgets the alert of kind sent for domain, so the state is
+// alerted afresh if it comes back, e.g. a domain dropping a second time
+func (g Gate) Resolve(ctx context.Context, domain, kind string) error {
+	s, err := g.State(ctx, domain)
+	if _, ok := s.Sent[kind]; err != nil || !ok {
+		return err
+	}
+	delete(s.Sent, kind)
+	return g.save(ctx, domain, s)
+}
+
+func (g Gate) edit(ctx context.Context, domain string, edit func(s *State)) error {
+	s, err := g.State(ctx, domain)
+	if err != nil {
+		return err
+	}
+	edit(&s)
+	return g.save(ctx, domain, s)
+}
+
+func (g Gate) save(ctx context.Context, domain string, s State) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return g.Store.SetSetting(ctx, key(domain), string(data))
+}
+
+func key(domain string) string { return "alerts/" + domain }
@@ -0,0 +1,33 @@
+package autobuy
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// Audit appends attempts to a JSON Lines file
+type Audit struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Record appends a to the log
+func (l *Audit) Record(a Attempt) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
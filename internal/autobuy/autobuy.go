@@ -0,0 +1,120 @@
+// Package autobuy registers domains through registrar APIs the moment a
+// watched one becomes available, within a price ceiling, and keeps an
+// audit log of every attempt.
+package autobuy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Registrar names
+const (
+	Porkbun   = "porkbun"
+	Namecheap = "namecheap"
+)
+
+// Names are the supported registrars
+var Names = []string{Porkbun, Namecheap}
+
+// Registrar can quote and register domains
+type Registrar interface {
+	Name() string
+	// Quote returns the one-year registration price in USD
+	Quote(ctx context.Context, domain string) (float64, error)
+	// Register buys domain for one year at the quoted price
+	Register(ctx context.Context, domain string, price float64) (orderID string, err error)
+}
+
+// Outcomes of an attempt
+const (
+	OutcomeRegistered  = "registered"
+	OutcomeDryRun      = "dry-run"
+	OutcomeOverCeiling = "over-ceiling"
+	OutcomeRefused     = "refused"
+	OutcomeFailed      = "failed"
+)
+
+// ErrRefused matches errors where the registrar answered and won't sell the
+// domain, as opposed to failing to answer
+var ErrRefused = errors.New("registrar refused")
+
+// refusal is a registrar's refusal, matching ErrRefused
+type refusal string
+
+func (e refusal) Error() string      { return string(e) }
+func (refusal) Is(target error) bool { return target == ErrRefused }
+
+// Attempt is one auto-buy attempt as recorded in the audit log
+type Attempt struct {
+	Time      time.Time `json:"time"`
+	Domain    string    `json:"domain"`
+	Registrar string    `json:"registrar"`
+	MaxPrice  float64   `json:"max_price"`
+	Price     float64   `json:"price,omitempty"`
+	DryRun    bool      `json:"dry_run,omitempty"`
+	Outcome   string    `json:"outcome"`
+	OrderID   string    `json:"order_id,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Buy quotes domain and registers it if the price is within maxPrice. A
+// dry run stops after the quote. The attempt is returned whatever the
+// outcome, for the caller to audit; a refusal is OutcomeRefused, any other
+// error OutcomeFailed.
+func Buy(ctx context.Context, r Registrar, domain string, maxPrice float64, dryRun bool) Attempt {
+	a := Attempt{Time: time.Now(), Domain: domain, Registrar: r.Name(), MaxPrice: maxPrice, DryRun: dryRun}
+
+	price, err := r.Quote(ctx, domain)
+	if err != nil {
+		a.Outcome, a.Error = failure(err), err.Error()
+		return a
+	}
+	a.Price = price
+	switch {
+	case price > maxPrice:
+		a.Outcome = OutcomeOverCeiling
+	case dryRun:
+		a.Outcome = OutcomeDryRun
+	default:
+		if a.OrderID, err = r.Register(ctx, domain, price); err != nil {
+			a.Outcome, a.Error = failure(err), err.Error()
+		} else {
+			a.Outcome = OutcomeRegistered
+		}
+	}
+	return a
+}
+
+func failure(err error) string {
+	if errors.Is(err, ErrRefused) {
+		return OutcomeRefused
+	}
+	return OutcomeFailed
+}
+
+// Final reports whether the attempt settled the matter: the domain was
+// registered, quoted or refused. A failed one is worth trying again.
+func (a Attempt) Final() bool {
+	return a.Outcome != OutcomeFailed
+}
+
+// Describe summarises an attempt for an alert
+func (a Attempt) Describe() string {
+	switch a.Outcome {
+	case OutcomeRegistered:
+		return fmt.Sprintf("registered with %s for $%.2f", a.Registrar, a.Price)
+	case OutcomeDryRun:
+		return fmt.Sprintf("dry run: would register with %s for $%.2f", a.Registrar, a.Price)
+	case OutcomeOverCeiling:
+		return fmt.Sprintf("not registered: %s charges $%.2f, over the $%.2f ceiling", a.Registrar, a.Price, a.MaxPrice)
+	case OutcomeRefused:
+		return "not registered: " + a.Error
+	}
+	return fmt.Sprintf("registration with %s failed: %s", a.Registrar, a.Error)
+}
+
+var client = &http.Client{Timeout: 30 * time.Second}
@@ -0,0 +1,245 @@
+package autobuy
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+const namecheapAPI = "https://api.namecheap.com/xml.response"
+
+// Contact is the registrant, used for every contact role
+type Contact struct {
+	FirstName     string
+	LastName      string
+	Address1      string
+	City          string
+	StateProvince string
+	PostalCode    string
+	Country       string // ISO code, e.g. US
+	Phone         string // +NNN.NNNNNNNNNN
+	EmailAddress  string
+}
+
+// NamecheapRegistrar uses Namecheap's XML API. ClientIP must be whitelisted
+// in the account's API settings.
+type NamecheapRegistrar struct {
+	APIUser  string
+	APIKey   string
+	ClientIP string
+	Contact  Contact
+}
+
+// Name implements Registrar
+func (r *NamecheapRegistrar) Name() string { return Namecheap }
+
+// namecheapCheck is a domains.check result
+type namecheapCheck struct {
+	Available    bool    `xml:"Available,attr"`
+	Premium      bool    `xml:"IsPremiumName,attr"`
+	PremiumPrice float64 `xml:"PremiumRegistrationPrice,attr"`
+}
+
+func (r *NamecheapRegistrar) check(ctx context.Context, domain string) (namecheapCheck, error) {
+	var body struct {
+		Result namecheapCheck `xml:"CommandResponse>DomainCheckResult"`
+	}
+	err := r.call(ctx, "namecheap.domains.check", url.Values{"DomainList": {domain}}, &body)
+	return body.Result, err
+}
+
+// Quote implements Registrar: the premium price when the name is premium,
+// else the account's one-year price for the TLD
+func (r *NamecheapRegistrar) Quote(ctx context.Context, domain string) (float64, error) {
+	check, err := r.check(ctx, domain)
+	if err != nil {
+		return 0, err
+	}
+	if !check.Available {
+		return 0, refusal("namecheap: not available")
+	}
+	if check.Premium {
+		return check.PremiumPrice, nil
+	}
+
+	prices, err := r.Prices(ctx, domain[strings.LastIndex(domain, ".")+1:])
+	if err != nil {
+		return 0, err
+	}
+	price, ok := prices["register"]
+	if !ok {
+		return 0, refusal("namecheap: no one-year price")
+	}
+	return price, nil
+}
+
+// PremiumPrice returns domain's price if Namecheap sells it as a premium
+// name
+func (r *NamecheapRegistrar) PremiumPrice(ctx context.Context, domain string) (float64, bool, error) {
+	check, err := r.check(ctx, domain)
+	if err != nil {
+		return 0, false, err
+	}
+	return check.PremiumPrice, check.Available && check.Premium, nil
+}
+
+// Prices returns the account's one-year prices for tld by action:
+// "register", "renew" and "transfer"
+func (r *NamecheapRegistrar) Prices(ctx context.Context, tld string) (map[string]float64, error) {
+	var pricing struct {
+		Categories []struct {
+			Name   string `xml:"Name,attr"`
+			Prices []struct {
+				Duration string `xml:"Duration,attr"`
+				Price    string `xml:"Price,attr"`
+			} `xml:"Product>Price"`
+		} `xml:"CommandResponse>UserGetPricingResult>ProductType>ProductCategory"`
+	}
+	params := url.Values{
+		"ProductType": {"DOMAIN"},
+		"ProductName": {tld},
+	}
+	if err := r.call(ctx, "namecheap.users.getPricing", params, &pricing); err != nil {
+		return nil, err
+	}
+	prices := make(map[string]float64)
+	for _, c := range pricing.Categories {
+		for _, p := range c.Prices {
+			if p.Duration != "1" {
+				continue
+			}
+			price, err := strconv.ParseFloat(p.Price, 64)
+			if err != nil {
+				return nil, fmt.Errorf("namecheap: %s price: %w", c.Name, err)
+			}
+			prices[strings.ToLower(c.Name)] = price
+		}
+	}
+	return prices, nil
+}
+
+// Domains returns the account's domains as owned watches, with their
+// expiry and auto-renew setting
+func (r *NamecheapRegistrar) Domains(ctx context.Context) ([]models.WatchedDomain, error) {
+	var domains []models.WatchedDomain
+	for page := 1; ; page++ {
+		var list struct {
+			Domains []struct {
+				Name      string `xml:"Name,attr"`
+				Expires   string `xml:"Expires,attr"`
+				AutoRenew bool   `xml:"AutoRenew,attr"`
+			} `xml:"CommandResponse>DomainGetListResult>Domain"`
+			Total int `xml:"CommandResponse>Paging>TotalItems"`
+		}
+		params := url.Values{"Page": {strconv.Itoa(page)}, "PageSize": {"100"}}
+		if err := r.call(ctx, "namecheap.domains.getList", params, &list); err != nil {
+			return domains, err
+		}
+		for _, d := range list.Domains {
+			w := models.WatchedDomain{
+				Domain:    strings.ToLower(d.Name),
+				Status:    models.StatusTaken,
+				Owned:     true,
+				Registrar: "Namecheap",
+				AutoRenew: &d.AutoRenew,
+			}
+			if d.Expires != "" {
+				expires, err := time.Parse("01/02/2006", d.Expires)
+				if err != nil {
+					return domains, fmt.Errorf("namecheap: %s expiry: %w", d.Name, err)
+				}
+				w.ExpiresAt = expires
+			}
+			domains = append(domains, w)
+		}
+		if len(list.Domains) == 0 || page*100 >= list.Total {
+			return domains, nil
+		}
+	}
+}
+
+// Register implements Registrar. Premium names must be confirmed with
+// their price.
+func (r *NamecheapRegistrar) Register(ctx context.Context, domain string, price float64) (string, error) {
+	params := url.Values{"DomainName": {domain}, "Years": {"1"}}
+	for _, role := range []string{"Registrant", "Tech", "Admin", "AuxBilling"} {
+		c := r.Contact
+		params.Set(role+"FirstName", c.FirstName)
+		params.Set(role+"LastName", c.LastName)
+		params.Set(role+"Address1", c.Address1)
+		params.Set(role+"City", c.City)
+		params.Set(role+"StateProvince", c.StateProvince)
+		params.Set(role+"PostalCode", c.PostalCode)
+		params.Set(role+"Country", c.Country)
+		params.Set(role+"Phone", c.Phone)
+		params.Set(role+"EmailAddress", c.EmailAddress)
+	}
+	check, err := r.check(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	if check.Premium {
+		params.Set("PremiumPrice", strconv.FormatFloat(price, 'f', 2, 64))
+	}
+
+	var created struct {
+		Result struct {
+			Registered bool   `xml:"Registered,attr"`
+			OrderID    string `xml:"OrderID,attr"`
+		} `xml:"CommandResponse>DomainCreateResult"`
+	}
+	if err := r.call(ctx, "namecheap.domains.create", params, &created); err != nil {
+		return "", err
+	}
+	if !created.Result.Registered {
+		return "", refusal("namecheap: not registered")
+	}
+	return created.Result.OrderID, nil
+}
+
+func (r *NamecheapRegistrar) call(ctx context.Context, command string, params url.Values, out any) error {
+	params.Set("ApiUser", r.APIUser)
+	params.Set("ApiKey", r.APIKey)
+	params.Set("UserName", r.APIUser)
+	params.Set("ClientIp", r.ClientIP)
+	params.Set("Command", command)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", namecheapAPI, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("namecheap returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var status struct {
+		Status string   `xml:"Status,attr"`
+		Errors []string `xml:"Errors>Error"`
+	}
+	if err := xml.Unmarshal(data, &status); err != nil {
+		return err
+	}
+	if status.Status != "OK" {
+		return fmt.Errorf("namecheap: %s", strings.Join(status.Errors, "; "))
+	}
+	return xml.Unmarshal(data, out)
+}
@@ -0,0 +1,158 @@
+package autobuy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+const porkbunAPI = "https://api.porkbun.com/api/json/v3"
+
+// PorkbunRegistrar uses Porkbun's JSON API. The account pays from its
+// credit balance.
+type PorkbunRegistrar struct {
+	APIKey    string
+	SecretKey string
+}
+
+// Name implements Registrar
+func (r *PorkbunRegistrar) Name() string { return Porkbun }
+
+// Quote implements Registrar
+func (r *PorkbunRegistrar) Quote(ctx context.Context, domain string) (float64, error) {
+	var body struct {
+		Response struct {
+			Avail string `json:"avail"`
+			Price string `json:"price"`
+		} `json:"response"`
+	}
+	if err := r.call(ctx, "/domain/checkDomain/"+domain, nil, &body); err != nil {
+		return 0, err
+	}
+	if body.Response.Avail != "yes" {
+		return 0, refusal("porkbun: not available")
+	}
+	return strconv.ParseFloat(body.Response.Price, 64)
+}
+
+// PremiumPrice returns domain's price if Porkbun sells it as a premium name
+func (r *PorkbunRegistrar) PremiumPrice(ctx context.Context, domain string) (float64, bool, error) {
+	var body struct {
+		Response struct {
+			Avail   string `json:"avail"`
+			Price   string `json:"price"`
+			Premium string `json:"premium"`
+		} `json:"response"`
+	}
+	if err := r.call(ctx, "/domain/checkDomain/"+domain, nil, &body); err != nil {
+		return 0, false, err
+	}
+	if body.Response.Avail != "yes" || body.Response.Premium != "yes" {
+		return 0, false, nil
+	}
+	price, err := strconv.ParseFloat(body.Response.Price, 64)
+	return price, err == nil, err
+}
+
+// porkbunPage is how many domains Porkbun lists per call
+const porkbunPage = 1000
+
+// Domains returns the account's domains as owned watches, with their
+// expiry and auto-renew setting
+func (r *PorkbunRegistrar) Domains(ctx context.Context) ([]models.WatchedDomain, error) {
+	var domains []models.WatchedDomain
+	for start := 0; ; start += porkbunPage {
+		var body struct {
+			Domains []struct {
+				Domain     string `json:"domain"`
+				ExpireDate string `json:"expireDate"`
+				// AutoRenew comes as 1 or "1"
+				AutoRenew json.RawMessage `json:"autoRenew"`
+			} `json:"domains"`
+		}
+		if err := r.call(ctx, "/domain/listAll", map[string]any{"start": strconv.Itoa(start)}, &body); err != nil {
+			return domains, err
+		}
+		for _, d := range body.Domains {
+			autoRenew := strings.Trim(string(d.AutoRenew), `"`) == "1"
+			w := models.WatchedDomain{
+				Domain:    strings.ToLower(d.Domain),
+				Status:    models.StatusTaken,
+				Owned:     true,
+				Registrar: "Porkbun",
+				AutoRenew: &autoRenew,
+			}
+			if d.ExpireDate != "" {
+				expires, err := time.Parse(time.DateTime, d.ExpireDate)
+				if err != nil {
+					return domains, fmt.Errorf("porkbun: %s expiry: %w", d.Domain, err)
+				}
+				w.ExpiresAt = expires
+			}
+			domains = append(domains, w)
+		}
+		if len(body.Domains) < porkbunPage {
+			return domains, nil
+		}
+	}
+}
+
+// Register implements Registrar. Porkbun takes the expected cost in cents
+// and refuses the order if the price changed.
+func (r *PorkbunRegistrar) Register(ctx context.Context, domain string, price float64) (string, error) {
+	var body struct {
+		OrderID json.Number `json:"orderId"`
+	}
+	params := map[string]any{
+		"cost":         int(math.Round(price * 100)),
+		"agreeToTerms": "yes",
+	}
+	if err := r.call(ctx, "/domain/create/"+domain, params, &body); err != nil {
+		return "", err
+	}
+	return body.OrderID.String(), nil
+}
+
+func (r *PorkbunRegistrar) call(ctx context.Context, path string, params map[string]any, out any) error {
+	payload := map[string]any{"apikey": r.APIKey, "secretapikey": r.SecretKey}
+	for k, v := range params {
+		payload[k] = v
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", porkbunAPI+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("porkbun returned status %d", resp.StatusCode)
+	}
+	var status struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	json.Unmarshal(raw, &status)
+	if status.Status != "SUCCESS" {
+		return fmt.Errorf("porkbun: %s", status.Message)
+	}
+	return json.Unmarshal(raw, out)
+}
@@ -0,0 +1,52 @@
+// Package backorder places drop-catch orders with backorder services, so a
+// watched domain in pending delete is caught the moment it drops.
+package backorder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Provider names
+const (
+	Dynadot   = "dynadot"
+	DropCatch = "dropcatch"
+)
+
+// Names are the supported providers. SnapNames isn't one: it has no public
+// API to place orders through.
+var Names = []string{Dynadot, DropCatch}
+
+// Provider is a backorder service
+type Provider interface {
+	Name() string
+	// Place backorders domain, committing at most maxPrice (USD)
+	Place(ctx context.Context, domain string, maxPrice float64) (Order, error)
+}
+
+// Order is a placed backorder. Amount is what it commits, in USD: the fixed
+// fee or the maximum bid.
+type Order struct {
+	ID     string
+	Amount float64
+}
+
+// PriceError means a provider's fixed price is over the domain's cap
+type PriceError struct {
+	Provider string
+	Price    float64
+	Max      float64
+}
+
+func (e *PriceError) Error() string {
+	return fmt.Sprintf("%s charges $%.2f, over the $%.2f cap", e.Provider, e.Price, e.Max)
+}
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// statusError reports an unexpected HTTP status from a provider
+func statusError(provider string, resp *http.Response) error {
+	return fmt.Errorf("%s returned status %d", provider, resp.StatusCode)
+}
@@ -0,0 +1,77 @@
+package backorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const dropCatchAPI = "https://api.dropcatch.com"
+
+// DropCatchProvider places bids through the DropCatch API, authenticating
+// with client credentials for each order. The bid is the domain's cap.
+type DropCatchProvider struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// Name implements Provider
+func (p *DropCatchProvider) Name() string { return DropCatch }
+
+// Place implements Provider
+func (p *DropCatchProvider) Place(ctx context.Context, domain string, maxPrice float64) (Order, error) {
+	var auth struct {
+		Token string `json:"token"`
+	}
+	err := p.post(ctx, "/Authorize", "", map[string]string{
+		"clientId":     p.ClientID,
+		"clientSecret": p.ClientSecret,
+	}, &auth)
+	if err != nil {
+		return Order{}, err
+	}
+
+	var placed struct {
+		Items []struct {
+			ID json.Number `json:"id"`
+		} `json:"items"`
+	}
+	err = p.post(ctx, "/v2/backorders", auth.Token, []map[string]any{
+		{"domainName": domain, "maxBid": maxPrice},
+	}, &placed)
+	if err != nil {
+		return Order{}, err
+	}
+
+	order := Order{ID: domain, Amount: maxPrice}
+	if len(placed.Items) > 0 && placed.Items[0].ID != "" {
+		order.ID = placed.Items[0].ID.String()
+	}
+	return order, nil
+}
+
+func (p *DropCatchProvider) post(ctx context.Context, path, token string, payload, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", dropCatchAPI+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return statusError(DropCatch, resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
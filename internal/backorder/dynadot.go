@@ -0,0 +1,63 @@
+package backorder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+const dynadotAPI = "https://api.dynadot.com/api3.json"
+
+// DynadotProvider places backorder requests through Dynadot's API3. Dynadot
+// charges a fixed Price per backorder; zero means unknown, in which case
+// the domain's cap is assumed to be committed.
+type DynadotProvider struct {
+	APIKey string
+	Price  float64
+}
+
+// Name implements Provider
+func (p *DynadotProvider) Name() string { return Dynadot }
+
+// Place implements Provider
+func (p *DynadotProvider) Place(ctx context.Context, domain string, maxPrice float64) (Order, error) {
+	if p.Price > maxPrice {
+		return Order{}, &PriceError{Provider: Dynadot, Price: p.Price, Max: maxPrice}
+	}
+
+	q := url.Values{"key": {p.APIKey}, "command": {"add_backorder_request"}, "domain": {domain}}
+	req, err := http.NewRequestWithContext(ctx, "GET", dynadotAPI+"?"+q.Encode(), nil)
+	if err != nil {
+		return Order{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Order{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return Order{}, statusError(Dynadot, resp)
+	}
+
+	var body struct {
+		Response struct {
+			ResponseCode json.Number
+			Status       string
+			Error        string
+		} `json:"AddBackorderRequestResponse"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Order{}, err
+	}
+	if body.Response.ResponseCode != "0" {
+		return Order{}, errors.New("dynadot: " + body.Response.Error)
+	}
+
+	amount := p.Price
+	if amount == 0 {
+		amount = maxPrice
+	}
+	return Order{ID: domain, Amount: amount}, nil
+}
@@ -0,0 +1,172 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// Archive layout
+const (
+	dbEntry     = "domainhunter.db"
+	configDir   = "config/"
+	archiveMode = 0o600
+)
+
+// Write streams a gzipped tar containing a snapshot of store and a copy
+// of each config file
+func Write(w io.Writer, store storage.Store, configFiles []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	// tar needs the size up front, so buffer the snapshot
+	var db bytes.Buffer
+	if err := store.Snapshot(&db); err != nil {
+		return fmt.Errorf("snapshot database: %w", err)
+	}
+	if err := writeEntry(tw, dbEntry, db.Bytes()); err != nil {
+		return err
+	}
+
+	for _, path := range configFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read config: %w", err)
+		}
+		if err := writeEntry(tw, configDir+filepath.Base(path), data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    archiveMode,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// Verify reads an archive made by Write through to the end, returning an
+// error if it is truncated or corrupt or has no database
+func Verify(r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	hasDB := false
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(io.Discard, tr); err != nil {
+			return err
+		}
+		hasDB = hasDB || hdr.Name == dbEntry
+	}
+	// Reading to the end checks the gzip checksum too
+	if _, err := io.Copy(io.Discard, gz); err != nil {
+		return err
+	}
+	if !hasDB {
+		return errors.New("archive contains no database")
+	}
+	return nil
+}
+
+// Restore unpacks an archive made by Write. The database is written to
+// dbPath and config files into cfgDir. Existing files are only replaced
+// when overwrite is set; each file is written to a temp file and renamed so
+// a failed restore never leaves a truncated database behind.
+func Restore(r io.Reader, dbPath, cfgDir string, overwrite bool) ([]string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	var restored []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return restored, err
+		}
+
+		var dest string
+		switch {
+		case hdr.Name == dbEntry:
+			dest = dbPath
+		case strings.HasPrefix(hdr.Name, configDir):
+			// Base() keeps entries from escaping cfgDir
+			dest = filepath.Join(cfgDir, filepath.Base(hdr.Name))
+		default:
+			continue
+		}
+
+		if err := writeFile(dest, tr, overwrite); err != nil {
+			return restored, err
+		}
+		restored = append(restored, dest)
+	}
+
+	if len(restored) == 0 {
+		return nil, errors.New("archive contains no database or config files")
+	}
+	return restored, nil
+}
+
+func writeFile(dest string, r io.Reader, overwrite bool) error {
+	if _, err := os.Stat(dest); err == nil && !overwrite {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", dest)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(archiveMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Get when the key is not cached or has expired
+var ErrMiss = errors.New("cache: miss")
+
+// Cache is a short-lived key/value store for recent check results and
+// rate limiter counters. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored under key, or ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for the given TTL
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Incr increments the counter under key and returns the new value.
+	// The TTL is applied only when the counter is created, so it acts as
+	// a fixed window.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Close releases any underlying connections
+	Close() error
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// minSweep is how many entries the in-memory cache holds before writes
+// start sweeping out expired ones
+const minSweep = 1024
+
+// Memory is an in-process Cache, suitable for single-instance deployments.
+// Keys that are never read again, such as past rate-limit windows, are
+// swept out by writes once the map doubles in size since the last sweep.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	sweepAt int
+}
+
+// NewMemory creates an empty in-memory cache
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry), sweepAt: minSweep}
+}
+
+// Get returns the cached value for key
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.lookup(key)
+	if !ok {
+		return nil, ErrMiss
+	}
+	return e.value, nil
+}
+
+// Set stores value under key for ttl
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = memoryEntry{value: value, expiresAt: time.Now().Add(ttl)}
+	m.maybeSweep()
+	return nil
+}
+
+// Incr increments a fixed-window counter
+func (m *Memory) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var n int64
+	e, ok := m.lookup(key)
+	if ok {
+		n, _ = strconv.ParseInt(string(e.value), 10, 64)
+	} else {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	n++
+	e.value = []byte(strconv.FormatInt(n, 10))
+	m.entries[key] = e
+	m.maybeSweep()
+	return n, nil
+}
+
+// Close is a no-op for the in-memory cache
+func (m *Memory) Close() error {
+	return nil
+}
+
+// lookup returns a live entry, evicting it if expired. Caller holds mu.
+func (m *Memory) lookup(key string) (memoryEntry, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return e, true
+}
+
+// maybeSweep evicts every expired entry once the map reaches sweepAt, then
+// waits for it to double before the next sweep, so sweeps stay cheap
+// overall. Caller holds mu.
+func (m *Memory) maybeSweep() {
+	if len(m.entries) < m.sweepAt {
+		return
+	}
+	now := time.Now()
+	for key, e := range m.entries {
+		if now.After(e.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	m.sweepAt = max(minSweep, 2*len(m.entries))
+}
@@ -0,0 +1,144 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Circuit breaking: once breakerTrip queries in a row to a WHOIS server
+// time out or are refused, its circuit opens and its domains are skipped
+// for breakerCooldown. Then one probe query goes through: an answer closes
+// the circuit, another failure reopens it for twice as long, up to
+// maxBreakerCooldown.
+const (
+	breakerTrip        = 5
+	breakerCooldown    = 30 * time.Second
+	maxBreakerCooldown = 5 * time.Minute
+)
+
+// errCircuitOpen means a domain was skipped because its WHOIS server's
+// circuit is open
+var errCircuitOpen = errors.New("whois server down, circuit open")
+
+// breaker is the circuit breaker for one WHOIS server
+type breaker struct {
+	name string
+
+	mu        sync.Mutex
+	failures  int           // failed queries in a row
+	openUntil time.Time     // zero while closed
+	cooldown  time.Duration // of the current opening
+	opens     int           // times opened, to spot a reopening
+	probing   bool          // a probe query is in flight
+	changed   chan struct{} // closed on every change of state
+}
+
+// enter reports whether a query may go to the server, returning
+// errCircuitOpen if not. While the circuit is half-open, the query let
+// through is the probe, and probe is set. With wait set, an open circuit
+// and a probe in flight are waited out, giving up only if the circuit
+// opens again meanwhile.
+func (b *breaker) enter(ctx context.Context, wait bool) (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	opens := b.opens
+	for {
+		switch {
+		case b.openUntil.IsZero():
+			return false, nil
+		case b.opens != opens:
+			return false, errCircuitOpen
+		case !b.probing && !time.Now().Before(b.openUntil):
+			b.probing = true
+			return true, nil
+		case !wait:
+			return false, errCircuitOpen
+		}
+
+		if b.changed == nil {
+			b.changed = make(chan struct{})
+		}
+		changed := b.changed
+		var halfOpen <-chan time.Time
+		var t *time.Timer
+		if !b.probing {
+			t = time.NewTimer(time.Until(b.openUntil))
+			halfOpen = t.C
+		}
+		b.mu.Unlock()
+		select {
+		case <-changed:
+		case <-halfOpen:
+		case <-ctx.Done():
+		}
+		if t != nil {
+			t.Stop()
+		}
+		b.mu.Lock()
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+	}
+}
+
+// record notes how a query went: an answer closes the circuit, and a
+// failure counts towards opening it, or reopens it after a probe
+func (b *breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	halfOpen := b.probing
+	b.probing = false
+	switch {
+	case !failed:
+		b.failures = 0
+		if !b.openUntil.IsZero() {
+			b.openUntil, b.cooldown = time.Time{}, 0
+			slog.Info("whois server answering again, circuit closed", "server", b.name)
+		}
+	case halfOpen:
+		b.open(min(2*b.cooldown, maxBreakerCooldown))
+	default:
+		if b.failures++; b.failures >= breakerTrip && b.openUntil.IsZero() {
+			b.open(breakerCooldown)
+		}
+	}
+	b.notify()
+}
+
+// settle frees the probe slot of a probe that ended without an answer
+// either way, such as one whose context was cancelled
+func (b *breaker) settle() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.probing {
+		b.probing = false
+		b.notify()
+	}
+}
+
+// open opens the circuit for cooldown; call with b.mu held
+func (b *breaker) open(cooldown time.Duration) {
+	b.cooldown = cooldown
+	b.openUntil = time.Now().Add(cooldown)
+	b.opens++
+	slog.Warn("whois server not answering, circuit open", "server", b.name, "cooldown", cooldown)
+}
+
+// notify wakes anyone waiting in enter; call with b.mu held
+func (b *breaker) notify() {
+	if b.changed != nil {
+		close(b.changed)
+		b.changed = nil
+	}
+}
+
+// state returns when the circuit lets the next probe through, or zero if
+// it is closed
+func (b *breaker) state() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.openUntil
+}
@@ -0,0 +1,159 @@
+package checker
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// MaxCandidateLength is the longest name Candidates generates
+const MaxCandidateLength = 5
+
+// Candidates are the domains of a given name length built from Chars after
+// Prefix, across TLDs, in the same order as GenerateShortDomainsForTLDs.
+// Domains are generated as they're asked for, so even a 5-character scan
+// never sits in memory whole.
+type Candidates struct {
+	Length int
+	Prefix string
+	Chars  string
+	TLDs   []string
+}
+
+// Len returns how many domains there are
+func (c Candidates) Len() int {
+	free := c.Length - len(c.Prefix)
+	if c.Length < 1 || c.Length > MaxCandidateLength || free < 0 || (free > 0 && c.Chars == "") {
+		return 0
+	}
+	n := len(c.TLDs)
+	for range free {
+		n *= len(c.Chars)
+	}
+	return n
+}
+
+// At returns the i'th domain
+func (c Candidates) At(i int) string {
+	name, tld := i/len(c.TLDs), c.TLDs[i%len(c.TLDs)]
+
+	free := c.Length - len(c.Prefix)
+	buf := make([]byte, free)
+	for j := free - 1; j >= 0; j-- {
+		buf[j] = c.Chars[name%len(c.Chars)]
+		name /= len(c.Chars)
+	}
+
+	var b strings.Builder
+	b.Grow(c.Length + 1 + len(tld))
+	b.WriteString(c.Prefix)
+	b.Write(buf)
+	b.WriteByte('.')
+	b.WriteString(tld)
+	return b.String()
+}
+
+// Slice returns the domains from index from up to, not including, to
+func (c Candidates) Slice(from, to int) []string {
+	domains := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		domains = append(domains, c.At(i))
+	}
+	return domains
+}
+
+// All yields the domains in order, one at a time
+func (c Candidates) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for i := range c.Len() {
+			if !yield(c.At(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Fingerprint identifies the candidates by what they're generated from:
+// length, prefix, characters and TLDs in order
+func (c Candidates) Fingerprint() string {
+	h := sha256.Sum256(fmt.Appendf(nil, "%d\n%s\n%s\n%s", c.Length, c.Prefix, c.Chars, strings.Join(c.TLDs, ",")))
+	return hex.EncodeToString(h[:])
+}
+
+// Contains reports whether domain is one of the candidates
+func (c Candidates) Contains(domain string) bool {
+	name, tld, ok := strings.Cut(domain, ".")
+	if !ok || len(name) != c.Length || !strings.HasPrefix(name, c.Prefix) || !slices.Contains(c.TLDs, tld) {
+		return false
+	}
+	for _, r := range name[len(c.Prefix):] {
+		if !strings.ContainsRune(c.Chars, r) {
+			return false
+		}
+	}
+	return c.Len() > 0
+}
+
+// ByPriority groups tlds into tiers by priority: 1 first, then 2 and so on,
+// with TLDs that have no priority last. TLDs within a tier keep their order.
+func ByPriority(tlds []string, priority map[string]int) [][]string {
+	byPriority := make(map[int][]string)
+	for _, tld := range tlds {
+		byPriority[priority[tld]] = append(byPriority[priority[tld]], tld)
+	}
+
+	// Priority 1 goes first; unset (0) goes last
+	priorities := slices.Collect(maps.Keys(byPriority))
+	slices.SortFunc(priorities, func(a, b int) int {
+		if a == 0 || b == 0 {
+			return cmp.Compare(b, a)
+		}
+		return cmp.Compare(a, b)
+	})
+	tiers := make([][]string, len(priorities))
+	for i, p := range priorities {
+		tiers[i] = byPriority[p]
+	}
+	return tiers
+}
+
+// Tiered chains candidates, one per priority tier, so the first tiers are
+// checked before the rest
+type Tiered []Candidates
+
+// NewTiered returns the candidates of a given length and prefix over tlds,
+// higher-priority TLDs first
+func NewTiered(length int, prefix, chars string, tlds []string, priority map[string]int) Tiered {
+	var t Tiered
+	for _, tier := range ByPriority(tlds, priority) {
+		t = append(t, Candidates{Length: length, Prefix: prefix, Chars: chars, TLDs: tier})
+	}
+	return t
+}
+
+// Len returns how many domains there are
+func (t Tiered) Len() int {
+	n := 0
+	for _, c := range t {
+		n += c.Len()
+	}
+	return n
+}
+
+// All yields the domains tier by tier
+func (t Tiered) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, c := range t {
+			for d := range c.All() {
+				if !yield(d) {
+					return
+				}
+			}
+		}
+	}
+}
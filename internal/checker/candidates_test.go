@@ -0,0 +1,29 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/berckan/domainhunter/internal/checker"
+)
+
+// BenchmarkGenerateSlice builds every 3-character premium-TLD domain as a
+// slice
+func BenchmarkGenerateSlice(b *testing.B) {
+	b.ReportAllocs()
+	n := 0
+	for b.Loop() {
+		n = len(checker.GenerateShortDomainsForTLDs(3, "", checker.Charsets["alnum"], checker.PremiumTLDs))
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/domain")
+}
+
+// BenchmarkGenerateCandidates walks the same domains lazily
+func BenchmarkGenerateCandidates(b *testing.B) {
+	b.ReportAllocs()
+	c := checker.Candidates{Length: 3, Chars: checker.Charsets["alnum"], TLDs: checker.PremiumTLDs}
+	for b.Loop() {
+		for range c.All() {
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*c.Len()), "ns/domain")
+}
@@ -1,34 +1,266 @@
 package checker
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net"
+	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/berckan/domainhunter/internal/models"
 	"github.com/likexian/whois"
+	"github.com/miekg/dns"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 )
 
+// defaultDNSServer is the resolver DNS checks query unless set otherwise
+const defaultDNSServer = "8.8.8.8:53"
+
 // Checker handles domain availability checks
 type Checker struct {
-	resolver *net.Resolver
-	timeout  time.Duration
+	resolver  *net.Resolver
+	timeout   time.Duration
+	dialer    *whoisDialer
+	telemetry *Telemetry
+
+	dns              dnsClients
+	negCache         *negativeCache
+	dnsServer        string // resolver address, host:port
+	dnsConcurrency   int
+	maxConcurrency   int           // caps dnsConcurrency and whoisConcurrency
+	dnsInterval      time.Duration // minimum spacing between DNS queries
+	dnsGate          *serverGate   // adapts DNS concurrency to the resolver
+	dnsTimeout       time.Duration // per domain in the DNS phase, retries included
+	whoisConcurrency int
+	whoisTimeout     time.Duration // per WHOIS query, from dialing on
+	whoisInterval    time.Duration // minimum spacing per WHOIS server
+	dialInterval     time.Duration // minimum spacing between WHOIS dials per server
+	preDial          bool          // keep a WHOIS connection dialed ahead
+	sourceAddr       net.IP        // local address for WHOIS connections
+	proxies          []*url.URL    // WHOIS proxies
+	proxyRotate      int           // WHOIS connections per proxy
+
+	serversMu sync.Mutex
+	servers   map[string]string // TLD -> WHOIS server ("" = none)
+	gates     map[string]*serverGate
+	breakers  map[string]*breaker
+	wildcards map[string]*wildcardProbe // by TLD
+
+	premiumPricers []PremiumPricer
+
+	pace         *pacer       // spaces all queries, for slow scans
+	whoisQueries atomic.Int64 // sent, for budgets
+
+	statsMu     sync.Mutex
+	workerStats map[string][]WorkerStats // by phase
+}
+
+// PremiumPricer quotes registry premium names, typically through a
+// registrar's API
+type PremiumPricer interface {
+	// PremiumPrice returns domain's first-year price if it is premium
+	PremiumPrice(ctx context.Context, domain string) (price float64, premium bool, err error)
+}
+
+// Option configures a Checker
+type Option func(*Checker)
+
+// WithDNSConcurrency sets how many DNS lookups run at once in hybrid checks
+func WithDNSConcurrency(n int) Option {
+	return func(c *Checker) {
+		if n > 0 {
+			c.dnsConcurrency = n
+		}
+	}
+}
+
+// WithMaxConcurrency caps the parallel DNS lookups and the parallel WHOIS
+// queries to each server, whatever other options set them to
+func WithMaxConcurrency(n int) Option {
+	return func(c *Checker) {
+		c.maxConcurrency = max(n, 0)
+	}
+}
+
+// WithDNSQPS caps the fast DNS phase's query rate, spacing queries evenly
+func WithDNSQPS(qps float64) Option {
+	return func(c *Checker) {
+		if qps > 0 {
+			c.dnsInterval = time.Duration(float64(time.Second) / qps)
+		}
+	}
+}
+
+// WithDNSServer sends DNS checks to the resolver at addr (host:port)
+// instead of 8.8.8.8
+func WithDNSServer(addr string) Option {
+	return func(c *Checker) {
+		if addr != "" {
+			c.dnsServer = addr
+		}
+	}
+}
+
+// WithDNSRetries sets how many times a DNS query that timed out is sent
+// again before the domain is given up on; 0 sends it once and a negative
+// n keeps the default
+func WithDNSRetries(n int) Option {
+	return func(c *Checker) {
+		if n >= 0 {
+			c.dns.retries = n
+		}
+	}
+}
+
+// WithDNSTimeout caps how long the DNS phase spends on one domain, retries
+// included, before counting it taken with an error
+func WithDNSTimeout(d time.Duration) Option {
+	return func(c *Checker) {
+		if d > 0 {
+			c.dnsTimeout = d
+		}
+	}
+}
+
+// WithWHOISConcurrency sets how many WHOIS queries run at once to each
+// registry server. Per-server limits from telemetry still apply on top.
+func WithWHOISConcurrency(n int) Option {
+	return func(c *Checker) {
+		if n > 0 {
+			c.whoisConcurrency = n
+		}
+	}
+}
+
+// WithWHOISQPS caps the query rate to each WHOIS server, spacing queries
+// evenly. Zero leaves the rate to the concurrency limits.
+func WithWHOISQPS(qps float64) Option {
+	return func(c *Checker) {
+		if qps > 0 {
+			c.whoisInterval = time.Duration(float64(time.Second) / qps)
+		}
+	}
+}
+
+// WithWHOISTimeout caps how long a WHOIS query may take, from dialing the
+// server to the end of its answer
+func WithWHOISTimeout(d time.Duration) Option {
+	return func(c *Checker) {
+		if d > 0 {
+			c.whoisTimeout = d
+		}
+	}
+}
+
+// WithWHOISDialInterval spaces out new connections to each WHOIS server by
+// at least d, so a burst of queries doesn't hit it with a burst of SYNs
+func WithWHOISDialInterval(d time.Duration) Option {
+	return func(c *Checker) {
+		if d > 0 {
+			c.dialInterval = d
+		}
+	}
+}
+
+// WithWHOISPreDial keeps a connection to each WHOIS server dialed ahead
+// while queries to it keep coming, so the next query skips the handshake.
+// Each spare connection waits its turn like a query, under WithPace and
+// WithQPS.
+func WithWHOISPreDial(on bool) Option {
+	return func(c *Checker) {
+		c.preDial = on
+	}
+}
+
+// WithSourceAddr makes WHOIS connections from the given local IP, for
+// hosts with several addresses. Nil leaves the choice to the OS.
+func WithSourceAddr(ip net.IP) Option {
+	return func(c *Checker) {
+		c.sourceAddr = ip
+	}
+}
+
+// WithPremiumPricers looks up the price of names WHOIS shows as premium,
+// asking each pricer in turn. Without one, or if none quotes a price,
+// premium names count as taken.
+func WithPremiumPricers(p ...PremiumPricer) Option {
+	return func(c *Checker) {
+		c.premiumPricers = append(c.premiumPricers, p...)
+	}
 }
 
 // New creates a new domain checker
-func New() *Checker {
-	return &Checker{
-		resolver: &net.Resolver{
-			PreferGo: true,
-			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-				d := net.Dialer{Timeout: 5 * time.Second}
-				return d.DialContext(ctx, network, "8.8.8.8:53")
-			},
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		timeout:   10 * time.Second,
+		telemetry: NewTelemetry(),
+		servers:   make(map[string]string),
+		gates:     make(map[string]*serverGate),
+		breakers:  make(map[string]*breaker),
+		wildcards: make(map[string]*wildcardProbe),
+
+		workerStats: make(map[string][]WorkerStats),
+
+		dns:              newDNSClients(),
+		negCache:         newNegativeCache(),
+		pace:             &pacer{},
+		dnsServer:        defaultDNSServer,
+		dnsConcurrency:   50,
+		dnsInterval:      time.Second / 1000, // public resolvers rate-limit around 1500 QPS
+		dnsTimeout:       10 * time.Second,
+		whoisConcurrency: 5,
+		whoisTimeout:     30 * time.Second,
+		dialInterval:     100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxConcurrency > 0 {
+		c.dnsConcurrency = min(c.dnsConcurrency, c.maxConcurrency)
+		c.whoisConcurrency = min(c.whoisConcurrency, c.maxConcurrency)
+	}
+	c.resolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			d := net.Dialer{Timeout: 5 * time.Second}
+			return d.DialContext(ctx, network, c.dnsServer)
 		},
-		timeout: 10 * time.Second,
 	}
+	c.dialer = newWHOISDialer(min(c.timeout, c.whoisTimeout), c.dialInterval, c.sourceAddr, c.proxies, c.proxyRotate)
+	if c.preDial {
+		c.dialer.preDial = c.pace.wait
+	}
+	c.dnsGate = newServerGate(c.dnsServer, c.dnsConcurrency)
+	return c
+}
+
+// whoisClient returns a WHOIS client whose connections are closed once ctx
+// is done
+func (c *Checker) whoisClient(ctx context.Context) *whois.Client {
+	return whois.NewClient().SetDisableStats(true).SetDialer(boundDialer{ctx, c.dialer}).SetTimeout(c.whoisTimeout)
+}
+
+// DNSCacheStats returns how the cache of NXDOMAIN answers has been used
+func (c *Checker) DNSCacheStats() CacheStats {
+	return c.negCache.snapshot()
+}
+
+// Telemetry returns the per-server WHOIS telemetry
+func (c *Checker) Telemetry() *Telemetry {
+	return c.telemetry
+}
+
+// WHOISQueries returns how many WHOIS queries the checker has sent
+func (c *Checker) WHOISQueries() int64 {
+	return c.whoisQueries.Load()
 }
 
 // Patterns that indicate domain IS registered (taken) - check these FIRST
@@ -72,103 +304,232 @@ var availablePatterns = []string{
 	"no matching record",
 }
 
-// Check verifies if a single domain is available using WHOIS
-func (c *Checker) Check(domain string) models.DomainResult {
+// Check verifies if a single domain is available using WHOIS. Once ctx is
+// done the query is abandoned and the result carries ctx's error. If the
+// domain's WHOIS server is down and its circuit open, or answers with a
+// throttling notice, the domain comes back StatusUnknown.
+func (c *Checker) Check(ctx context.Context, domain string) models.DomainResult {
+	return c.check(ctx, domain, false)
+}
+
+// recheck is Check that waits out an open circuit
+func (c *Checker) recheck(ctx context.Context, domain string) models.DomainResult {
+	return c.check(ctx, domain, true)
+}
+
+func (c *Checker) check(ctx context.Context, domain string, wait bool) models.DomainResult {
+	ctx, span := tracer.Start(ctx, "checker.whois", trace.WithAttributes(attribute.String("domain", domain)))
+	result := c.checkWHOISResult(ctx, domain, wait)
+	endCheckSpan(span, result)
+	return result
+}
+
+// checkWHOISResult queries WHOIS for domain and reads its availability
+// from the response
+func (c *Checker) checkWHOISResult(ctx context.Context, domain string, wait bool) models.DomainResult {
 	result := models.DomainResult{
 		Domain:    domain,
+		Method:    models.MethodWHOIS,
 		CheckedAt: time.Now(),
 	}
 
 	// Try WHOIS lookup
-	whoisResult, err := whois.Whois(domain)
+	whoisResult, err := c.queryWhois(ctx, domain, wait)
+	if errors.Is(err, errCircuitOpen) || errors.Is(err, errRateLimited) {
+		// Skipped or throttled, so not known either way; a scan checks
+		// these again at the end
+		result.Status = models.StatusUnknown
+		result.Error = err.Error()
+		return result
+	}
 	if err != nil {
 		// WHOIS failed - mark as taken (conservative approach)
 		result.Status = models.StatusTaken
+		result.Error = err.Error()
 		return result
 	}
 
-	whoisLower := strings.ToLower(whoisResult)
+	verdict := ReadWhois(whoisResult)
+	result.Status = verdict.Status
+	switch verdict.Status {
+	case models.StatusTaken:
+		// Noting whether it is being deleted
+		result.Phase = ParseWhois(whoisResult).DropPhase()
+	case models.StatusPremium:
+		// Not truly available, unless a registrar will sell it at its
+		// premium price
+		result.Status = models.StatusTaken
+		if price, ok := c.premiumPrice(ctx, domain); ok {
+			result.Status = models.StatusPremium
+			result.Price = price
+		}
+	}
+	return result
+}
+
+// Verdict is what a WHOIS response says about a domain's availability, and
+// which rule said it
+type Verdict struct {
+	Status models.DomainStatus `json:"status"`
+	// Rule names the rule that decided, e.g. taken pattern "registrar:"
+	Rule string `json:"rule"`
+	// Line is the response line the rule matched, if one did
+	Line string `json:"line,omitempty"`
+}
+
+// ReadWhois reads a domain's availability from its WHOIS response. The
+// rules are tried in order: taken patterns first, since they are the more
+// reliable, then premium and reserved notices, then available patterns;
+// a response none of them match is taken, to be safe. Premium names come
+// out StatusPremium, which checks only keep if a pricer quotes them.
+func ReadWhois(resp string) Verdict {
+	lower := strings.ToLower(resp)
 
-	// FIRST: Check if domain is taken (more reliable)
 	for _, pattern := range takenPatterns {
-		if strings.Contains(whoisLower, pattern) {
-			result.Status = models.StatusTaken
-			return result
+		if strings.Contains(lower, pattern) {
+			return Verdict{models.StatusTaken, fmt.Sprintf("taken pattern %q", pattern), matchedLine(resp, pattern)}
 		}
 	}
 
-	// SECOND: Check for premium/platinum reserved domains (NOT truly available)
-	if (strings.Contains(whoisLower, "premium") || strings.Contains(whoisLower, "platinum")) &&
-		(strings.Contains(whoisLower, "purchase") || strings.Contains(whoisLower, "contact") ||
-			strings.Contains(whoisLower, "offer") || strings.Contains(whoisLower, "reserved")) {
-		result.Status = models.StatusTaken
-		return result
+	if (strings.Contains(lower, "premium") || strings.Contains(lower, "platinum")) &&
+		(strings.Contains(lower, "purchase") || strings.Contains(lower, "contact") ||
+			strings.Contains(lower, "offer") || strings.Contains(lower, "reserved")) {
+		return Verdict{models.StatusPremium, "premium notice", cmp.Or(matchedLine(resp, "premium"), matchedLine(resp, "platinum"))}
 	}
-	if strings.Contains(whoisLower, "this name is reserved") {
-		result.Status = models.StatusTaken
-		return result
+	if strings.Contains(lower, "this name is reserved") {
+		return Verdict{models.StatusTaken, "reserved notice", matchedLine(resp, "this name is reserved")}
 	}
 
-	// THEN: Check if explicitly marked as available
 	for _, pattern := range availablePatterns {
-		if strings.Contains(whoisLower, pattern) {
-			result.Status = models.StatusAvailable
-			return result
+		if strings.Contains(lower, pattern) {
+			return Verdict{models.StatusAvailable, fmt.Sprintf("available pattern %q", pattern), matchedLine(resp, pattern)}
 		}
 	}
 
-	// If unclear, assume taken (conservative)
-	result.Status = models.StatusTaken
-	return result
+	return Verdict{models.StatusTaken, "no pattern matched; assumed taken", ""}
 }
 
-// checkDNS is the fallback DNS-based check
-func (c *Checker) checkDNS(domain string) models.DomainResult {
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
-	defer cancel()
+// matchedLine returns the first line of resp containing pattern, ignoring
+// case, trimmed
+func matchedLine(resp, pattern string) string {
+	for _, line := range strings.Split(resp, "\n") {
+		if strings.Contains(strings.ToLower(line), pattern) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
+// premiumPrice asks the pricers for domain's premium price, returning the
+// first quote
+func (c *Checker) premiumPrice(ctx context.Context, domain string) (float64, bool) {
+	for _, p := range c.premiumPricers {
+		ctx, cancel := context.WithTimeout(ctx, c.timeout)
+		price, premium, err := p.PremiumPrice(ctx, domain)
+		cancel()
+		if err == nil && premium && price > 0 {
+			return price, true
+		}
+	}
+	return 0, false
+}
 
+// checkDNS is the fast DNS-based check: a name without a delegation
+// (NXDOMAIN) is likely available
+func (c *Checker) checkDNS(ctx context.Context, domain string) models.DomainResult {
+	ctx, span := tracer.Start(ctx, "checker.dns", trace.WithAttributes(attribute.String("domain", domain)))
 	result := models.DomainResult{
 		Domain:    domain,
+		Method:    models.MethodDNS,
 		CheckedAt: time.Now(),
 	}
 
-	_, err := c.resolver.LookupHost(ctx, domain)
-	if err != nil {
-		if dnsErr, ok := err.(*net.DNSError); ok {
-			if dnsErr.IsNotFound {
-				result.Status = models.StatusAvailable
-				return result
-			}
-		}
-		// Unknown DNS errors → assume taken (conservative)
+	if c.negCache.nxdomain(domain) {
+		result.Status = models.StatusAvailable
+		span.SetAttributes(attribute.Bool("dns.cached", true))
+		endCheckSpan(span, result)
+		return result
+	}
+	// A slow scan's pace can be far longer than the query timeout
+	if err := c.pace.wait(ctx); err != nil {
 		result.Status = models.StatusTaken
+		result.Error = err.Error()
+		endCheckSpan(span, result)
 		return result
 	}
-
-	result.Status = models.StatusTaken
+	ctx, cancel := context.WithTimeout(ctx, c.dnsTimeout)
+	defer cancel()
+	rcode, negTTL, err := c.queryDNS(ctx, domain)
+
+	// Timeouts and REFUSED are how a resolver sheds load
+	c.dnsGate.observe(errors.Is(err, errDNSTimeout) || rcode == dns.RcodeRefused, err == nil && (rcode == dns.RcodeSuccess || rcode == dns.RcodeNameError))
+	if err == nil {
+		result.DNSRcode = dns.RcodeToString[rcode]
+	} else if errors.Is(err, errDNSTimeout) {
+		result.DNSRcode = models.RcodeTimeout
+	}
+	switch {
+	case err != nil:
+		// Unknown DNS errors → assume taken (conservative)
+		result.Status = models.StatusTaken
+		result.Error = err.Error()
+	case rcode == dns.RcodeNameError:
+		result.Status = models.StatusAvailable
+		c.negCache.add(domain, negTTL)
+	case rcode == dns.RcodeSuccess:
+		result.Status = models.StatusTaken
+	default:
+		// SERVFAIL is often a registered domain with broken DNS, but can be
+		// the resolver's own trouble; bulk checks retry it
+		result.Status = models.StatusTaken
+		result.Error = result.DNSRcode
+	}
+	endCheckSpan(span, result)
 	return result
 }
 
-// CheckBulk checks multiple domains with limited concurrency (WHOIS rate limiting)
-func (c *Checker) CheckBulk(domains []string) []models.DomainResult {
+// queryDNS sends domain's NS query through the DNS gate
+func (c *Checker) queryDNS(ctx context.Context, domain string) (int, time.Duration, error) {
+	if err := c.dnsGate.acquire(ctx); err != nil {
+		return 0, 0, err
+	}
+	defer c.dnsGate.release()
+	if err := c.dnsGate.pace(ctx, c.dnsInterval); err != nil {
+		return 0, 0, err
+	}
+	return c.dns.queryNS(ctx, c.dnsServer, domain)
+}
+
+// CheckBulk checks multiple domains by WHOIS, with a fixed pool of workers
+// per registry server, few enough to avoid WHOIS rate limiting. Results are
+// in domain order; if ctx is done first, the domains not reached are left
+// StatusUnchecked and ctx.Err() is returned.
+func (c *Checker) CheckBulk(ctx context.Context, domains []string) ([]models.DomainResult, error) {
+	ctx, span := tracer.Start(ctx, "checker.CheckBulk", trace.WithAttributes(attribute.Int("domains", len(domains))))
 	results := make([]models.DomainResult, len(domains))
-	var wg sync.WaitGroup
+	err := c.checkWHOIS(ctx, domains, indexes(len(domains)), results, c.Check)
+	endSpan(span, err)
+	return results, err
+}
 
-	// Limit concurrency to 5 to avoid WHOIS rate limiting
-	semaphore := make(chan struct{}, 5)
+// Recheck is CheckBulk for domains an earlier check skipped as
+// StatusUnknown: it first waits for their servers' circuits to let a probe
+// through, and skips them again only if the probe fails
+func (c *Checker) Recheck(ctx context.Context, domains []string) ([]models.DomainResult, error) {
+	ctx, span := tracer.Start(ctx, "checker.Recheck", trace.WithAttributes(attribute.Int("domains", len(domains))))
+	results := make([]models.DomainResult, len(domains))
+	err := c.checkWHOIS(ctx, domains, indexes(len(domains)), results, c.recheck)
+	endSpan(span, err)
+	return results, err
+}
 
-	for i, domain := range domains {
-		wg.Add(1)
-		go func(idx int, d string) {
-			defer wg.Done()
-			semaphore <- struct{}{}        // acquire
-			results[idx] = c.Check(d)
-			<-semaphore                    // release
-		}(i, domain)
+// indexes returns 0 through n-1
+func indexes(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
 	}
-
-	wg.Wait()
-	return results
+	return idx
 }
 
 // PremiumTLDs is a curated list of valuable TLDs for short domain scanning
@@ -251,11 +612,22 @@ func GenerateShortDomains(length int, tld string) []string {
 
 // GenerateShortDomainsMultiTLD generates short domains across multiple TLDs
 func GenerateShortDomainsMultiTLD(length int, prefix string) []string {
+	return GenerateShortDomainsForTLDs(length, prefix, Charsets["alnum"], PremiumTLDs)
+}
+
+// Charsets are the named character sets short names can be built from
+var Charsets = map[string]string{
+	"alnum":   "abcdefghijklmnopqrstuvwxyz0123456789",
+	"letters": "abcdefghijklmnopqrstuvwxyz",
+	"digits":  "0123456789",
+}
+
+// GenerateShortDomainsForTLDs generates short domains from chars across the given TLDs
+func GenerateShortDomainsForTLDs(length int, prefix, chars string, tlds []string) []string {
 	if length < 1 || length > 3 {
 		return nil
 	}
 
-	chars := "abcdefghijklmnopqrstuvwxyz0123456789"
 	var names []string
 
 	// Generate names based on length and prefix
@@ -287,10 +659,10 @@ func GenerateShortDomainsMultiTLD(length int, prefix string) []string {
 		}
 	}
 
-	// Generate domains across all premium TLDs
+	// Generate domains across all requested TLDs
 	var domains []string
 	for _, name := range names {
-		for _, tld := range PremiumTLDs {
+		for _, tld := range tlds {
 			domains = append(domains, name+"."+tld)
 		}
 	}
@@ -298,46 +670,86 @@ func GenerateShortDomainsMultiTLD(length int, prefix string) []string {
 	return domains
 }
 
-// CheckBulkHybrid uses DNS first (fast), then WHOIS to confirm candidates
-func (c *Checker) CheckBulkHybrid(domains []string) []models.DomainResult {
-	// Phase 1: Fast DNS check (high concurrency)
-	dnsResults := make([]models.DomainResult, len(domains))
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, 50) // High concurrency for DNS
-
-	for i, domain := range domains {
-		wg.Add(1)
-		go func(idx int, d string) {
-			defer wg.Done()
-			semaphore <- struct{}{}
-			dnsResults[idx] = c.checkDNS(d)
-			<-semaphore
-		}(i, domain)
-	}
-	wg.Wait()
-
-	// Phase 2: WHOIS confirmation for DNS "available" results
-	var candidates []int
-	for i, r := range dnsResults {
-		if r.Status == models.StatusAvailable {
-			candidates = append(candidates, i)
+// CheckBulkHybrid uses DNS first (fast), then WHOIS to confirm candidates.
+// The phases overlap: each candidate goes to its WHOIS server's workers as
+// soon as DNS finds it, rather than once every DNS check is done. Each
+// phase has its own worker pools; see CheckBulk for cancellation.
+func (c *Checker) CheckBulkHybrid(ctx context.Context, domains []string) ([]models.DomainResult, error) {
+	ctx, span := tracer.Start(ctx, "checker.CheckBulkHybrid", trace.WithAttributes(attribute.Int("domains", len(domains))))
+
+	// Wildcard TLDs resolve every name, so theirs skip straight to WHOIS
+	wild := c.wildcardTLDs(ctx, domains)
+	var dnsIdx, wildIdx []int
+	for i := range domains {
+		if wild[i] {
+			wildIdx = append(wildIdx, i)
+		} else {
+			dnsIdx = append(dnsIdx, i)
 		}
 	}
 
-	// Confirm with WHOIS (limited concurrency)
-	whoisSem := make(chan struct{}, 5)
-	var wg2 sync.WaitGroup
-
-	for _, idx := range candidates {
-		wg2.Add(1)
-		go func(i int) {
-			defer wg2.Done()
-			whoisSem <- struct{}{}
-			dnsResults[i] = c.Check(domains[i]) // Full WHOIS check
-			<-whoisSem
-		}(idx)
+	results := make([]models.DomainResult, len(domains))
+	unchecked(domains, indexes(len(domains)), results)
+	g, gctx := errgroup.WithContext(ctx)
+	confirm := &confirmQueues{c: c, g: g, ctx: gctx, domains: domains, results: results, queues: make(map[string]*spillQueue)}
+
+	// WHOIS replaces the DNS result of a candidate, so its DNS answer is
+	// put back afterwards
+	rcodes := make([]string, len(domains))
+	var mu sync.Mutex
+	var servfail []int
+	candidate := func(retry bool) func(int) error {
+		return func(i int) error {
+			r := results[i]
+			switch {
+			case r.DNSRcode == models.RcodeServFail && !retry:
+				// A SERVFAIL can be the resolver's passing trouble, so
+				// those are asked again once the rest of the phase is done
+				mu.Lock()
+				servfail = append(servfail, i)
+				mu.Unlock()
+				return nil
+			case r.Status != models.StatusAvailable && r.DNSRcode != models.RcodeServFail:
+				return nil
+			}
+			// Available, or a name DNS still couldn't answer for
+			rcodes[i] = r.DNSRcode
+			return confirm.send(i)
+		}
 	}
-	wg2.Wait()
 
-	return dnsResults
+	g.Go(func() error {
+		defer confirm.close()
+		var feed errgroup.Group
+		feed.Go(func() error {
+			for _, i := range wildIdx {
+				if err := confirm.send(i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		feed.Go(func() error {
+			if err := c.pool(gctx, PhaseDNS, c.dnsConcurrency, domains, dnsIdx, results, c.checkDNS, candidate(false)); err != nil {
+				return err
+			}
+			if len(servfail) == 0 {
+				return nil
+			}
+			span.SetAttributes(attribute.Int("servfail", len(servfail)))
+			slog.DebugContext(ctx, "asking again after SERVFAIL", "domains", len(servfail))
+			return c.pool(gctx, PhaseDNSRetry, c.dnsConcurrency, domains, servfail, results, c.checkDNS, candidate(true))
+		})
+		return feed.Wait()
+	})
+	err := g.Wait()
+
+	for i, rcode := range rcodes {
+		if rcode != "" {
+			results[i].DNSRcode = rcode
+		}
+	}
+	span.SetAttributes(attribute.Int("candidates", confirm.sent))
+	endSpan(span, err)
+	return results, err
 }
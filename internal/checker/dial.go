@@ -0,0 +1,210 @@
+package checker
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// warmTTL is how long a pre-dialed WHOIS connection is kept for the next
+// query; registries drop connections that stay silent much longer
+const warmTTL = 3 * time.Second
+
+// whoisDialer opens WHOIS connections. WHOIS servers close the connection
+// after each answer, so instead of reusing connections it spaces out new
+// ones to each server. With preDial set it also keeps one dialed ahead
+// while queries keep coming, which takes the handshake off the next
+// query's critical path. With proxies, connections go through them in
+// turn.
+type whoisDialer struct {
+	dialer   net.Dialer
+	interval time.Duration // minimum spacing between dials to one server
+	// preDial waits for a spare connection's turn, as the checker's pacer
+	// does for a query; nil dials no spares
+	preDial func(ctx context.Context) error
+
+	proxies []proxy.Dialer
+	rotate  int // connections per proxy before moving on; 0 = first only
+
+	mu    sync.Mutex
+	hosts map[string]*dialHost // by address
+	dials int
+}
+
+// dialHost is the dial state for one WHOIS server
+type dialHost struct {
+	next     time.Time // earliest start of the next dial
+	warm     net.Conn
+	warmedAt time.Time
+	warming  bool
+}
+
+func newWHOISDialer(timeout, interval time.Duration, source net.IP, proxies []*url.URL, rotate int) *whoisDialer {
+	d := &whoisDialer{
+		dialer:   net.Dialer{Timeout: timeout},
+		interval: interval,
+		rotate:   rotate,
+		hosts:    make(map[string]*dialHost),
+	}
+	if source != nil {
+		d.dialer.LocalAddr = &net.TCPAddr{IP: source}
+	}
+	for _, u := range proxies {
+		p, err := proxyDialer(u, &d.dialer)
+		if err != nil {
+			slog.Warn("whois proxy", "err", err)
+			continue
+		}
+		d.proxies = append(d.proxies, p)
+	}
+	return d
+}
+
+// DialContext hands out the warm connection to address if there is a fresh
+// one, or dials a new one once the server's dial spacing allows, then
+// starts warming a connection for the next query if preDial is set. The
+// connection is closed when ctx is done, cutting short a query in flight.
+func (d *whoisDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	h, ok := d.hosts[address]
+	if !ok {
+		h = &dialHost{}
+		d.hosts[address] = h
+	}
+	conn, stale := h.take()
+	d.mu.Unlock()
+	if stale != nil {
+		stale.Close()
+	}
+
+	if conn == nil {
+		var err error
+		if conn, err = d.dial(ctx, h, network, address); err != nil {
+			return nil, err
+		}
+	}
+	if d.preDial != nil {
+		d.warm(ctx, h, network, address)
+	}
+	return &ctxConn{Conn: conn, stop: context.AfterFunc(ctx, func() { conn.Close() })}, nil
+}
+
+// ctxConn is a connection closed early when its context is done
+type ctxConn struct {
+	net.Conn
+	stop func() bool
+}
+
+func (c *ctxConn) Close() error {
+	c.stop()
+	return c.Conn.Close()
+}
+
+// boundDialer dials through a whoisDialer with a fixed context, for the
+// WHOIS client, which only knows Dial
+type boundDialer struct {
+	ctx context.Context
+	d   *whoisDialer
+}
+
+// Dial implements proxy.Dialer
+func (b boundDialer) Dial(network, address string) (net.Conn, error) {
+	return b.d.DialContext(b.ctx, network, address)
+}
+
+// take returns h's warm connection if it is still fresh, or else the stale
+// one to close
+func (h *dialHost) take() (fresh, stale net.Conn) {
+	conn := h.warm
+	h.warm = nil
+	if conn != nil && time.Since(h.warmedAt) > warmTTL {
+		return nil, conn
+	}
+	return conn, nil
+}
+
+// dial waits for h's next dial slot and dials
+func (d *whoisDialer) dial(ctx context.Context, h *dialHost, network, address string) (net.Conn, error) {
+	if d.interval > 0 {
+		d.mu.Lock()
+		slot := time.Now()
+		if h.next.After(slot) {
+			slot = h.next
+		}
+		h.next = slot.Add(d.interval)
+		d.mu.Unlock()
+		if err := sleep(ctx, time.Until(slot)); err != nil {
+			return nil, err
+		}
+	}
+	p := d.via()
+	if cd, ok := p.(proxy.ContextDialer); ok {
+		return cd.DialContext(ctx, network, address)
+	}
+	return p.Dial(network, address)
+}
+
+// via returns the proxy for the next connection, or the direct dialer
+func (d *whoisDialer) via() proxy.Dialer {
+	if len(d.proxies) == 0 {
+		return &d.dialer
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	i := 0
+	if d.rotate > 0 {
+		i = d.dials / d.rotate % len(d.proxies)
+	}
+	d.dials++
+	return d.proxies[i]
+}
+
+// warm dials a spare connection to address in the background unless one is
+// already there or on its way, and closes it if no query claims it in time.
+// It waits its turn with preDial and gives up when ctx, the query's that
+// asked for it, is done.
+func (d *whoisDialer) warm(ctx context.Context, h *dialHost, network, address string) {
+	d.mu.Lock()
+	if h.warm != nil || h.warming {
+		d.mu.Unlock()
+		return
+	}
+	h.warming = true
+	d.mu.Unlock()
+
+	go func() {
+		var conn net.Conn
+		err := d.preDial(ctx)
+		if err == nil {
+			conn, err = d.dial(ctx, h, network, address)
+		}
+		d.mu.Lock()
+		h.warming = false
+		if err != nil || h.warm != nil {
+			d.mu.Unlock()
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+		h.warm, h.warmedAt = conn, time.Now()
+		d.mu.Unlock()
+
+		time.AfterFunc(warmTTL, func() {
+			d.mu.Lock()
+			idle := h.warm == conn
+			if idle {
+				h.warm = nil
+			}
+			d.mu.Unlock()
+			if idle {
+				conn.Close()
+			}
+		})
+	}()
+}
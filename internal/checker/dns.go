@@ -0,0 +1,155 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/miekg/dns"
+)
+
+// Snapshot resolves domain's NS, A and MX records. Record types that
+// don't exist come back empty; any other failure is an error, so a flaky
+// resolver isn't mistaken for a change.
+func (c *Checker) Snapshot(ctx context.Context, domain string) (models.DNSSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	snap := models.DNSSnapshot{CheckedAt: time.Now()}
+
+	ns, err := c.resolver.LookupNS(ctx, domain)
+	if err != nil && !notFound(err) {
+		return snap, err
+	}
+	for _, n := range ns {
+		snap.NS = append(snap.NS, normalizeHost(n.Host))
+	}
+
+	ips, err := c.resolver.LookupIP(ctx, "ip4", domain)
+	if err != nil && !notFound(err) {
+		return snap, err
+	}
+	for _, ip := range ips {
+		snap.A = append(snap.A, ip.String())
+	}
+
+	mx, err := c.resolver.LookupMX(ctx, domain)
+	if err != nil && !notFound(err) {
+		return snap, err
+	}
+	for _, m := range mx {
+		snap.MX = append(snap.MX, normalizeHost(m.Host))
+	}
+
+	slices.Sort(snap.NS)
+	slices.Sort(snap.A)
+	slices.Sort(snap.MX)
+	return snap, nil
+}
+
+// DNSAnswer is the DNS phase's resolver's answer to one query
+type DNSAnswer struct {
+	Type  string `json:"type"`
+	Rcode string `json:"rcode,omitempty"`
+	// Records are the answers' data, e.g. "10 mx.example.com." for MX,
+	// prefixed with their type when it isn't the one asked for, as with
+	// CNAMEs
+	Records []string `json:"records,omitempty"`
+	// Authority is the SOA a negative answer came with, if any
+	Authority []string `json:"authority,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Query asks the DNS phase's resolver for domain's records of type qtype,
+// e.g. "MX", the way the DNS phase asks for NS records
+func (c *Checker) Query(ctx context.Context, domain, qtype string) DNSAnswer {
+	ans := DNSAnswer{Type: strings.ToUpper(qtype)}
+	t, ok := dns.StringToType[ans.Type]
+	if !ok {
+		ans.Error = "unknown record type"
+		return ans
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.dnsTimeout)
+	defer cancel()
+	resp, err := c.dns.query(ctx, c.dnsServer, domain, t)
+	if err != nil {
+		ans.Error = err.Error()
+		return ans
+	}
+	ans.Rcode = dns.RcodeToString[resp.Rcode]
+	for _, rr := range resp.Answer {
+		ans.Records = append(ans.Records, rdata(rr, t))
+	}
+	if len(resp.Answer) == 0 {
+		for _, rr := range resp.Ns {
+			if rr.Header().Rrtype == dns.TypeSOA {
+				ans.Authority = append(ans.Authority, rdata(rr, t))
+			}
+		}
+	}
+	return ans
+}
+
+// rdata returns rr's data, prefixed with its type unless that's qtype
+func rdata(rr dns.RR, qtype uint16) string {
+	data := strings.TrimSpace(strings.TrimPrefix(rr.String(), rr.Header().String()))
+	if rr.Header().Rrtype != qtype {
+		data = dns.TypeToString[rr.Header().Rrtype] + " " + data
+	}
+	return data
+}
+
+// DNSVerdict is how the DNS phase of a hybrid check treats a domain
+type DNSVerdict struct {
+	// Server is the resolver the phase asks
+	Server string `json:"server"`
+	// Wildcard is set if the domain's TLD resolves every name, in which case
+	// the phase skips it and WHOIS decides
+	Wildcard bool `json:"wildcard"`
+	// Rcode answers the phase's NS query, or is TIMEOUT
+	Rcode  string              `json:"rcode,omitempty"`
+	Status models.DomainStatus `json:"status"`
+	// Outcome says what the check does next
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ExplainDNS runs the DNS phase for domain alone and says what a hybrid
+// check would make of it
+func (c *Checker) ExplainDNS(ctx context.Context, domain string) DNSVerdict {
+	v := DNSVerdict{Server: c.dnsServer}
+	if c.wildcard(ctx, domain) {
+		v.Wildcard = true
+		v.Status = models.StatusUnchecked
+		v.Outcome = "the TLD has wildcard DNS, so WHOIS decides"
+		return v
+	}
+
+	r := c.checkDNS(ctx, domain)
+	v.Rcode, v.Status, v.Error = r.DNSRcode, r.Status, r.Error
+	switch {
+	case r.DNSRcode == models.RcodeServFail:
+		v.Outcome = "SERVFAIL is asked again after the rest, then WHOIS decides"
+	case r.Status == models.StatusAvailable:
+		v.Outcome = "NXDOMAIN means not delegated; WHOIS confirms it's available"
+	case r.Error != "":
+		v.Outcome = "the query failed or was refused, so it's counted taken to be safe"
+	default:
+		v.Outcome = "the name is delegated, so it's taken; WHOIS isn't asked"
+	}
+	return v
+}
+
+// notFound reports whether err means the records don't exist
+func notFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
@@ -0,0 +1,61 @@
+package checker_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/config"
+	"github.com/miekg/dns"
+)
+
+// BenchmarkDNSPhase runs a hybrid check with each profile's settings over
+// domains a local resolver reports as taken, so no WHOIS is involved and
+// the numbers reflect the checker rather than the network
+func BenchmarkDNSPhase(b *testing.B) {
+	server := startResolver(b)
+	domains := checker.Candidates{Length: 4, Chars: checker.Charsets["alnum"], TLDs: []string{"com"}}.Slice(0, 1000)
+	for _, profile := range []string{"gentle", "normal", "aggressive"} {
+		b.Run(profile, func(b *testing.B) {
+			b.ReportAllocs()
+			cc := config.Concurrency{Profile: profile}
+			c := checker.New(append(cc.Options(), checker.WithDNSServer(server))...)
+			for b.Loop() {
+				if _, err := c.CheckBulkHybrid(context.Background(), domains); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.N*len(domains))/b.Elapsed().Seconds(), "domains/s")
+		})
+	}
+}
+
+// startResolver serves NOERROR on a local UDP port until the benchmark
+// ends, returning its address. Wildcard probes, the names starting "dh-",
+// get NXDOMAIN, so the TLD's names stay in the DNS phase.
+func startResolver(tb testing.TB) string {
+	tb.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	started := make(chan struct{})
+	server := &dns.Server{
+		PacketConn:        conn,
+		NotifyStartedFunc: func() { close(started) },
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			m := new(dns.Msg)
+			m.SetReply(req)
+			if len(req.Question) > 0 && strings.HasPrefix(req.Question[0].Name, "dh-") {
+				m.Rcode = dns.RcodeNameError
+			}
+			w.WriteMsg(m)
+		}),
+	}
+	go server.ActivateAndServe()
+	<-started
+	tb.Cleanup(func() { server.Shutdown() })
+	return conn.LocalAddr().String()
+}
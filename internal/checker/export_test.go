@@ -0,0 +1,9 @@
+package checker
+
+// SetWhoisServer points c's WHOIS queries for tld at server, which may
+// have a port, as IANA would have
+func SetWhoisServer(c *Checker, tld, server string) {
+	c.serversMu.Lock()
+	defer c.serversMu.Unlock()
+	c.servers[tld] = server
+}
@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/logging"
+	"github.com/miekg/dns"
+)
+
+// Fast DNS phase tuning: each UDP attempt gets dnsQueryTimeout, and by
+// default a query that times out is sent again up to dnsRetries times
+const (
+	dnsQueryTimeout = 2 * time.Second
+	dnsRetries      = 2
+)
+
+// errDNSTimeout means every attempt at a query timed out
+var errDNSTimeout = errors.New("dns query timed out")
+
+// dnsClients send the fast phase's queries: UDP, and TCP for answers too
+// big for UDP
+type dnsClients struct {
+	udp     *dns.Client
+	tcp     *dns.Client
+	retries int // resends after a timeout
+}
+
+func newDNSClients() dnsClients {
+	return dnsClients{
+		udp:     &dns.Client{Net: "udp", Timeout: dnsQueryTimeout},
+		tcp:     &dns.Client{Net: "tcp", Timeout: dnsQueryTimeout},
+		retries: dnsRetries,
+	}
+}
+
+// queryNS asks server for domain's NS records and returns the response
+// code. NXDOMAIN means the name isn't delegated; NOERROR means it exists,
+// with or without NS records. For NXDOMAIN, negTTL is how long the answer
+// may be cached, from the zone's SOA.
+func (d dnsClients) queryNS(ctx context.Context, server, domain string) (rcode int, negTTL time.Duration, err error) {
+	resp, err := d.query(ctx, server, domain, dns.TypeNS)
+	if err != nil {
+		return 0, 0, err
+	}
+	return resp.Rcode, negativeTTL(resp), nil
+}
+
+// query asks server for domain's records of type qtype, over TCP if the
+// answer is too big for UDP, resending after a timeout
+func (d dnsClients) query(ctx context.Context, server, domain string, qtype uint16) (resp *dns.Msg, err error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), qtype)
+	m.SetEdns0(1232, false)
+
+	for attempt := range d.retries + 1 {
+		if attempt > 0 {
+			slog.Log(ctx, logging.LevelTrace, "dns query timed out, resending", "domain", domain, "type", dns.TypeToString[qtype], "server", server, "attempt", attempt+1)
+		}
+		resp, _, err = d.udp.ExchangeContext(ctx, m, server)
+		if err == nil && resp.Truncated {
+			resp, _, err = d.tcp.ExchangeContext(ctx, m, server)
+		}
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", errDNSTimeout, err)
+}
+
+// negativeTTL is how long an NXDOMAIN answer may be cached: the lesser of
+// its SOA record's TTL and the SOA minimum (RFC 2308). Without a SOA it
+// isn't cached.
+func negativeTTL(resp *dns.Msg) time.Duration {
+	if resp.Rcode != dns.RcodeNameError {
+		return 0
+	}
+	for _, rr := range resp.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return time.Duration(min(soa.Hdr.Ttl, soa.Minttl)) * time.Second
+		}
+	}
+	return 0
+}
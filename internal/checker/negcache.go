@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"sync"
+	"time"
+)
+
+// Negative DNS cache bounds: answers are kept no longer than
+// maxNegativeTTL whatever the zone says, and at most maxNegativeEntries
+// names are held
+const (
+	maxNegativeTTL     = time.Hour
+	maxNegativeEntries = 1 << 20
+)
+
+// CacheStats counts how the negative DNS cache has been used
+type CacheStats struct {
+	Entries int   // names currently cached
+	Hits    int64 // lookups answered from the cache
+	Misses  int64 // lookups that went to the resolver
+}
+
+// negativeCache remembers NXDOMAIN answers for as long as the zone's SOA
+// allows, so a name seen again within that time, by an overlapping scan
+// or a recheck, isn't resolved again
+type negativeCache struct {
+	mu      sync.Mutex
+	expires map[string]time.Time // by domain
+	stats   CacheStats
+}
+
+func newNegativeCache() *negativeCache {
+	return &negativeCache{expires: make(map[string]time.Time)}
+}
+
+// nxdomain reports whether domain is cached as not existing, counting a
+// hit or a miss
+func (n *negativeCache) nxdomain(domain string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	exp, ok := n.expires[domain]
+	if ok && time.Now().After(exp) {
+		delete(n.expires, domain)
+		ok = false
+	}
+	if ok {
+		n.stats.Hits++
+	} else {
+		n.stats.Misses++
+	}
+	return ok
+}
+
+// add caches domain as not existing for ttl. When the cache is full,
+// expired entries are dropped first, and if it is still full the answer
+// isn't cached.
+func (n *negativeCache) add(domain string, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.expires) >= maxNegativeEntries {
+		now := time.Now()
+		for d, exp := range n.expires {
+			if now.After(exp) {
+				delete(n.expires, d)
+			}
+		}
+		if len(n.expires) >= maxNegativeEntries {
+			return
+		}
+	}
+	n.expires[domain] = time.Now().Add(min(ttl, maxNegativeTTL))
+}
+
+// snapshot returns the cache's counts
+func (n *negativeCache) snapshot() CacheStats {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	s := n.stats
+	s.Entries = len(n.expires)
+	return s
+}
@@ -0,0 +1,88 @@
+package checker
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// pacer spaces every DNS and WHOIS query of a checker, whatever the
+// server, randomizing each gap by up to ±jitter of the interval so a long
+// scan has no telltale rhythm
+type pacer struct {
+	mu       sync.Mutex
+	interval time.Duration // zero leaves queries unpaced
+	floor    time.Duration // least gap whatever the interval, from WithQPS
+	jitter   float64
+	next     time.Time
+}
+
+// wait blocks until the next query's slot
+func (p *pacer) wait(ctx context.Context) error {
+	p.mu.Lock()
+	if p.interval <= 0 && p.floor <= 0 {
+		p.mu.Unlock()
+		return ctx.Err()
+	}
+	slot := time.Now()
+	if p.next.After(slot) {
+		slot = p.next
+	}
+	gap := p.interval
+	if p.jitter > 0 {
+		gap = time.Duration(float64(gap) * (1 + p.jitter*(2*rand.Float64()-1)))
+	}
+	p.next = slot.Add(max(gap, p.floor))
+	p.mu.Unlock()
+	return sleep(ctx, time.Until(slot))
+}
+
+// set changes the interval, from the next slot on
+func (p *pacer) set(interval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interval = interval
+	if interval <= 0 && p.floor <= 0 {
+		p.next = time.Time{}
+	}
+}
+
+// get returns the interval
+func (p *pacer) get() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.interval
+}
+
+// WithPace spaces every DNS and WHOIS query by interval, across all
+// servers, each gap randomized by up to ±jitter (0 to 1) of it. It is for
+// slow scans that shouldn't stand out in registry logs; SetPace adjusts the
+// interval as a scan goes.
+func WithPace(interval time.Duration, jitter float64) Option {
+	return func(c *Checker) {
+		c.pace.interval = max(interval, 0)
+		c.pace.jitter = min(max(jitter, 0), 1)
+	}
+}
+
+// WithQPS caps every DNS and WHOIS query together, across all servers, at
+// qps per second; a pace from WithPace or SetPace can only slow them more.
+// Zero leaves them uncapped.
+func WithQPS(qps float64) Option {
+	return func(c *Checker) {
+		if qps > 0 {
+			c.pace.floor = time.Duration(float64(time.Second) / qps)
+		}
+	}
+}
+
+// SetPace changes the interval WithPace set; zero stops pacing
+func (c *Checker) SetPace(interval time.Duration) {
+	c.pace.set(interval)
+}
+
+// Pace returns the interval queries are spaced by, zero if unpaced
+func (c *Checker) Pace() time.Duration {
+	return c.pace.get()
+}
@@ -0,0 +1,135 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+	"sync"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"golang.org/x/sync/errgroup"
+)
+
+// confirmQueues is the WHOIS side of CheckBulkHybrid: it routes candidates,
+// by index, to a queue per registry server as the DNS phase finds them,
+// each drained by its own pool of workers, started as servers come up. The
+// queues never fill, so a slow registry never holds up the DNS workers or
+// the other servers' candidates; a bulk check's domains bound them. It is
+// whoisQueues for a bulk check, with worker stats.
+type confirmQueues struct {
+	c       *Checker
+	g       *errgroup.Group
+	ctx     context.Context
+	domains []string
+	results []models.DomainResult
+
+	mu     sync.Mutex
+	queues map[string]*spillQueue
+	sent   int
+}
+
+// send queues the candidate at i for its server's workers without
+// waiting. Its result is unchecked until they get to it.
+func (q *confirmQueues) send(i int) error {
+	server, _ := q.c.whoisServer(q.ctx, q.domains[i])
+	server = cmp.Or(server, "none")
+
+	q.mu.Lock()
+	queue, ok := q.queues[server]
+	if !ok {
+		queue = newSpillQueue()
+		q.queues[server] = queue
+		q.g.Go(func() error {
+			queue.feed(q.ctx)
+			return nil
+		})
+		q.g.Go(func() error {
+			return q.c.work(q.ctx, PhaseWHOIS+":"+server, q.c.whoisConcurrency, 0, q.domains, queue.out, q.results, q.c.Check, nil)
+		})
+	}
+	q.sent++
+	q.mu.Unlock()
+
+	unchecked(q.domains, []int{i}, q.results)
+	queue.push(i)
+	return q.ctx.Err()
+}
+
+// close closes every queue once nothing more will be sent; their workers
+// finish what's queued
+func (q *confirmQueues) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, queue := range q.queues {
+		queue.close()
+	}
+}
+
+// spillQueue is a queue of candidate indexes that never blocks its
+// sender: what the workers aren't ready for waits in pending, and feed
+// hands it on to them through out
+type spillQueue struct {
+	out   chan int
+	ready chan struct{} // signals feed that pending grew or the queue closed
+
+	mu      sync.Mutex
+	pending []int
+	closed  bool
+}
+
+func newSpillQueue() *spillQueue {
+	return &spillQueue{out: make(chan int), ready: make(chan struct{}, 1)}
+}
+
+// push queues i
+func (s *spillQueue) push(i int) {
+	s.mu.Lock()
+	s.pending = append(s.pending, i)
+	s.mu.Unlock()
+	s.signal()
+}
+
+// close marks the end of the queue; out closes once pending is drained
+func (s *spillQueue) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *spillQueue) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// feed hands pending indexes to out in order until the queue is closed and
+// drained or ctx is done, then closes out
+func (s *spillQueue) feed(ctx context.Context) {
+	defer close(s.out)
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return
+			}
+			select {
+			case <-s.ready:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		i := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.out <- i:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
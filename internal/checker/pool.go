@@ -0,0 +1,140 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+// Bulk check phases, as named in WorkerStats. WHOIS workers are per
+// registry server, so their phase is PhaseWHOIS + ":" + the server.
+// PhaseDNSRetry asks again for the names that got a SERVFAIL.
+const (
+	PhaseDNS      = "dns"
+	PhaseDNSRetry = "dns-retry"
+	PhaseWHOIS    = "whois"
+)
+
+// WorkerStats counts what one bulk-check worker has done since the checker
+// was created. Workers are numbered within their phase, and worker n of
+// every bulk check adds to the same entry.
+type WorkerStats struct {
+	Phase   string        `json:"phase"`
+	Worker  int           `json:"worker"`
+	Checked int           `json:"checked"`
+	Errors  int           `json:"errors"`
+	Busy    time.Duration `json:"busy"`
+}
+
+// WorkerStats returns every worker's stats, by phase and worker number
+func (c *Checker) WorkerStats() []WorkerStats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	var stats []WorkerStats
+	for _, ws := range c.workerStats {
+		stats = append(stats, ws...)
+	}
+	slices.SortFunc(stats, func(a, b WorkerStats) int {
+		return cmp.Or(cmp.Compare(a.Phase, b.Phase), cmp.Compare(a.Worker, b.Worker))
+	})
+	return stats
+}
+
+// pool checks the domains at idx with a fixed number of workers fed from a
+// bounded queue, storing each result in results at the same index, then
+// calling then, if set, with the index. check gets the pool's context, so
+// once ctx is done queries in flight are cut short and no more are
+// started; the domains left over keep a StatusUnchecked result and
+// ctx.Err() is returned, as it is for an error from then.
+func (c *Checker) pool(ctx context.Context, phase string, workers int, domains []string, idx []int, results []models.DomainResult, check func(context.Context, string) models.DomainResult, then func(int) error) error {
+	unchecked(domains, idx, results)
+	workers = max(1, min(workers, len(idx)))
+
+	g, ctx := errgroup.WithContext(ctx)
+	queue := make(chan int, workers)
+	g.Go(func() error {
+		defer close(queue)
+		for _, i := range idx {
+			select {
+			case queue <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	g.Go(func() error {
+		return c.work(ctx, phase, workers, len(idx), domains, queue, results, check, then)
+	})
+	return g.Wait()
+}
+
+// work is pool's workers, checking the indexes from queue until it is
+// closed. size is how many it will get, if known, for tracing.
+func (c *Checker) work(ctx context.Context, phase string, workers, size int, domains []string, queue <-chan int, results []models.DomainResult, check func(context.Context, string) models.DomainResult, then func(int) error) error {
+	ctx, span := tracer.Start(ctx, "checker.phase", trace.WithAttributes(attribute.String("phase", phase), attribute.Int("domains", size)))
+	g, ctx := errgroup.WithContext(ctx)
+	stats := make([]WorkerStats, workers)
+	for w := range workers {
+		g.Go(func() error {
+			s := &stats[w]
+			for i := range queue {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				start := time.Now()
+				res := check(ctx, domains[i])
+				s.Busy += time.Since(start)
+				if err := ctx.Err(); err != nil {
+					// Cut short; keep the unchecked result
+					return err
+				}
+				s.Checked++
+				if res.Error != "" {
+					s.Errors++
+				}
+				results[i] = res
+				if then != nil {
+					if err := then(i); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+	c.addWorkerStats(phase, stats)
+	endSpan(span, err)
+	return err
+}
+
+// unchecked fills in a StatusUnchecked result for the domains at idx, to
+// be replaced as they're checked
+func unchecked(domains []string, idx []int, results []models.DomainResult) {
+	for _, i := range idx {
+		results[i] = models.DomainResult{Domain: domains[i], Status: models.StatusUnchecked, CheckedAt: time.Now()}
+	}
+}
+
+// addWorkerStats adds one bulk check's worker counts to the totals
+func (c *Checker) addWorkerStats(phase string, stats []WorkerStats) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	total := c.workerStats[phase]
+	for len(total) < len(stats) {
+		total = append(total, WorkerStats{Phase: phase, Worker: len(total) + 1})
+	}
+	for i, s := range stats {
+		total[i].Checked += s.Checked
+		total[i].Errors += s.Errors
+		total[i].Busy += s.Busy
+	}
+	c.workerStats[phase] = total
+}
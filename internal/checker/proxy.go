@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// WithWHOISProxies sends WHOIS queries through the given SOCKS5 or HTTP
+// proxies, moving on to the next one every rotateEvery connections. Zero
+// keeps to the first proxy.
+func WithWHOISProxies(rotateEvery int, proxies ...*url.URL) Option {
+	return func(c *Checker) {
+		c.proxies = proxies
+		c.proxyRotate = max(rotateEvery, 0)
+	}
+}
+
+// ParseProxy parses a WHOIS proxy URL: socks5://, socks5h:// or http://,
+// with a port and optional user:password
+func ParseProxy(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h", "http":
+	default:
+		return nil, fmt.Errorf("proxy %s: scheme must be socks5, socks5h or http", u.Redacted())
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return nil, fmt.Errorf("proxy %s: needs a host and port", u.Redacted())
+	}
+	return u, nil
+}
+
+// proxyDialer returns a dialer that connects through u, reaching the proxy
+// itself with forward
+func proxyDialer(u *url.URL, forward *net.Dialer) (proxy.Dialer, error) {
+	if u.Scheme != "http" {
+		return proxy.FromURL(u, forward)
+	}
+	p := httpProxy{url: u, forward: forward}
+	if u.User != nil {
+		pass, _ := u.User.Password()
+		p.auth = base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + pass))
+	}
+	return p, nil
+}
+
+// httpProxy tunnels connections through an HTTP proxy with CONNECT
+type httpProxy struct {
+	url     *url.URL
+	auth    string // basic credentials, base64
+	forward *net.Dialer
+}
+
+// Dial implements proxy.Dialer
+func (p httpProxy) Dial(network, address string) (net.Conn, error) {
+	return p.DialContext(context.Background(), network, address)
+}
+
+// DialContext implements proxy.ContextDialer
+func (p httpProxy) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := p.forward.DialContext(ctx, network, p.url.Host)
+	if err != nil {
+		return nil, err
+	}
+	if p.forward.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(p.forward.Timeout))
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if p.auth != "" {
+		req.Header.Set("Proxy-Authorization", "Basic "+p.auth)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", p.url.Redacted(), err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", p.url.Redacted(), err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %s", p.url.Redacted(), resp.Status)
+	}
+
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
@@ -0,0 +1,109 @@
+package checker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// WHOIS field names, lowercased, as different registries spell them
+var (
+	registrarKeys  = []string{"registrar", "registrar name", "sponsoring registrar"}
+	statusKeys     = []string{"domain status", "status", "state"}
+	nameServerKeys = []string{"name server", "nameserver", "name servers", "nserver"}
+	createdKeys    = []string{"creation date", "created", "created on", "registered", "registration time", "registered on"}
+	updatedKeys    = []string{"updated date", "last updated", "changed", "last-update", "last modified", "modified"}
+	expiresKeys    = []string{"registry expiry date", "registrar registration expiration date", "expiration date", "expiry date", "expires", "expires on", "paid-till", "expire date", "renewal date"}
+)
+
+// whoisDateLayouts are tried in order when parsing WHOIS dates
+var whoisDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02-Jan-2006",
+	"2006.01.02",
+	"02.01.2006",
+	"2006/01/02",
+	"January 2 2006",
+}
+
+// Lookup queries WHOIS for domain and parses the response
+func (c *Checker) Lookup(ctx context.Context, domain string) (models.WhoisRecord, error) {
+	resp, err := c.queryWhois(ctx, domain, false)
+	if err != nil {
+		return models.WhoisRecord{}, err
+	}
+	return ParseWhois(resp), nil
+}
+
+// RawWhois queries WHOIS for domain, returning the server that answered
+// and its response as is
+func (c *Checker) RawWhois(ctx context.Context, domain string) (server, resp string, err error) {
+	if server, err = c.whoisServer(ctx, domain); err != nil {
+		return "", "", err
+	}
+	resp, err = c.queryWhois(ctx, domain, true)
+	return server, resp, err
+}
+
+// ParseWhois extracts registrar, statuses, name servers and dates from a
+// WHOIS response. Unknown fields are ignored; the first value wins for
+// single-valued fields, since registries list their own data first.
+func ParseWhois(resp string) models.WhoisRecord {
+	var rec models.WhoisRecord
+	for _, line := range strings.Split(resp, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case slices.Contains(registrarKeys, key) && rec.Registrar == "":
+			rec.Registrar = value
+		case slices.Contains(statusKeys, key):
+			// "clientHold https://icann.org/epp#clientHold"
+			rec.Statuses = appendNew(rec.Statuses, strings.Fields(value)[0])
+		case slices.Contains(nameServerKeys, key):
+			ns := strings.TrimSuffix(strings.ToLower(strings.Fields(value)[0]), ".")
+			rec.NameServers = appendNew(rec.NameServers, ns)
+		case slices.Contains(createdKeys, key) && rec.Created.IsZero():
+			rec.Created = parseWhoisDate(value)
+		case slices.Contains(updatedKeys, key) && rec.Updated.IsZero():
+			rec.Updated = parseWhoisDate(value)
+		case slices.Contains(expiresKeys, key) && rec.Expires.IsZero():
+			rec.Expires = parseWhoisDate(value)
+		}
+	}
+	return rec
+}
+
+// appendNew appends v unless list already has it
+func appendNew(list []string, v string) []string {
+	if slices.Contains(list, v) {
+		return list
+	}
+	return append(list, v)
+}
+
+// parseWhoisDate parses value, or its first word, with the known layouts;
+// the zero time if none fit
+func parseWhoisDate(value string) time.Time {
+	for _, v := range []string{value, strings.Fields(value)[0]} {
+		for _, layout := range whoisDateLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,164 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"sync"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"golang.org/x/sync/errgroup"
+)
+
+// Sink receives each result of a streamed check as soon as it's known.
+// Calls come from one goroutine at a time; an error stops the check.
+type Sink func(models.DomainResult) error
+
+// Stream runs the hybrid DNS+WHOIS check over domains as they're produced,
+// handing results to sink in the order they complete. DNS workers pull from
+// the generator and pass likely-available domains on to per-server WHOIS
+// workers, with every hop bounded, so neither the candidate list nor the
+// results are ever held whole: a slow sink or registry holds up the
+// generator instead. It returns when every domain has been checked, or
+// with the first error from ctx or sink.
+func (c *Checker) Stream(ctx context.Context, domains iter.Seq[string], sink Sink) error {
+	ctx, span := tracer.Start(ctx, "checker.Stream")
+	g, ctx := errgroup.WithContext(ctx)
+
+	queue := make(chan string, c.dnsConcurrency)
+	g.Go(func() error {
+		defer close(queue)
+		for d := range domains {
+			select {
+			case queue <- d:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	out := make(chan models.DomainResult, c.dnsConcurrency)
+	emit := func(r models.DomainResult) error {
+		select {
+		case out <- r:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	confirm := &whoisQueues{c: c, g: g, ctx: ctx, emit: emit, queues: make(map[string]chan string)}
+	var dns sync.WaitGroup
+	for range c.dnsConcurrency {
+		dns.Add(1)
+		g.Go(func() error {
+			defer dns.Done()
+			for d := range queue {
+				// Wildcard TLDs resolve every name, so theirs skip DNS
+				if c.wildcard(ctx, d) {
+					if err := confirm.send(d); err != nil {
+						return err
+					}
+					continue
+				}
+				res := c.checkDNS(ctx, d)
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				// There's no end of phase to retry a SERVFAIL at, so WHOIS
+				// decides it
+				if res.Status != models.StatusAvailable && res.DNSRcode != models.RcodeServFail {
+					if err := emit(res); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := confirm.send(d); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	// out closes once the DNS workers are done and the WHOIS queues drain
+	go func() {
+		dns.Wait()
+		confirm.close()
+		confirm.wg.Wait()
+		close(out)
+	}()
+
+	g.Go(func() error {
+		for r := range out {
+			if err := sink(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	err := g.Wait()
+	endSpan(span, err)
+	return err
+}
+
+// whoisQueues routes domains to a bounded queue per registry WHOIS server,
+// each with its own workers, started as servers come up
+type whoisQueues struct {
+	c    *Checker
+	g    *errgroup.Group
+	ctx  context.Context
+	emit func(models.DomainResult) error
+
+	mu     sync.Mutex
+	queues map[string]chan string
+	wg     sync.WaitGroup
+}
+
+// send queues domain for its server's workers, waiting while the queue is
+// full
+func (q *whoisQueues) send(domain string) error {
+	server, _ := q.c.whoisServer(q.ctx, domain)
+	server = cmp.Or(server, "none")
+
+	q.mu.Lock()
+	queue, ok := q.queues[server]
+	if !ok {
+		queue = make(chan string, q.c.whoisConcurrency)
+		q.queues[server] = queue
+		for range q.c.whoisConcurrency {
+			q.wg.Add(1)
+			q.g.Go(func() error {
+				defer q.wg.Done()
+				for d := range queue {
+					res := q.c.Check(q.ctx, d)
+					if err := q.ctx.Err(); err != nil {
+						return err
+					}
+					if err := q.emit(res); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+	}
+	q.mu.Unlock()
+
+	select {
+	case queue <- domain:
+		return nil
+	case <-q.ctx.Done():
+		return q.ctx.Err()
+	}
+}
+
+// close closes every queue once nothing more will be sent
+func (q *whoisQueues) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, queue := range q.queues {
+		close(queue)
+	}
+}
@@ -0,0 +1,211 @@
+package checker
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Outcome classifies a single WHOIS query
+type Outcome int
+
+const (
+	OutcomeOK Outcome = iota
+	OutcomeError
+	OutcomeTimeout
+	OutcomeRefused
+	OutcomeRateLimited
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeOK:
+		return "ok"
+	case OutcomeTimeout:
+		return "timeout"
+	case OutcomeRefused:
+		return "refused"
+	case OutcomeRateLimited:
+		return "rate_limited"
+	}
+	return "error"
+}
+
+// Health summarizes a server's recent behaviour
+type Health string
+
+const (
+	HealthGood     Health = "healthy"
+	HealthDegraded Health = "degraded"
+	HealthBad      Health = "struggling"
+)
+
+// ewmaAlpha weights new samples in the moving averages
+const ewmaAlpha = 0.1
+
+// rateLimitCooldown is how long a server stays throttled after a limit signal
+const rateLimitCooldown = 5 * time.Minute
+
+// Responses that mean the registry is throttling or banning us. They're
+// only looked for in short responses that aren't records, since a record
+// or its legal footer can say "exceeded" or "access denied" in passing.
+var rateLimitPatterns = []string{
+	"exceeded",
+	"quota",
+	"too many",
+	"rate limit",
+	"try again later",
+	"temporarily blocked",
+	"access denied",
+}
+
+// Telemetry tracks latency and failures per WHOIS server
+type Telemetry struct {
+	mu      sync.Mutex
+	servers map[string]*models.ServerStats
+}
+
+// NewTelemetry creates an empty telemetry tracker
+func NewTelemetry() *Telemetry {
+	return &Telemetry{servers: make(map[string]*models.ServerStats)}
+}
+
+// Load seeds the tracker with previously persisted stats
+func (t *Telemetry) Load(stats []models.ServerStats) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range stats {
+		t.servers[s.Server] = &s
+	}
+}
+
+// Record adds one query result for server
+func (t *Telemetry) Record(server string, latency time.Duration, outcome Outcome, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.servers[server]
+	if !ok {
+		s = &models.ServerStats{Server: server, AvgLatency: float64(latency.Milliseconds())}
+		t.servers[server] = s
+	}
+
+	now := time.Now()
+	s.Queries++
+	s.UpdatedAt = now
+	s.AvgLatency += ewmaAlpha * (float64(latency.Milliseconds()) - s.AvgLatency)
+
+	failed := 0.0
+	if outcome != OutcomeOK {
+		failed = 1
+		s.Errors++
+		s.LastErrorAt = now
+		if err != nil {
+			s.LastError = err.Error()
+		}
+	}
+	s.ErrorRate += ewmaAlpha * (failed - s.ErrorRate)
+
+	switch outcome {
+	case OutcomeTimeout:
+		s.Timeouts++
+	case OutcomeRefused:
+		s.Refused++
+	case OutcomeRateLimited:
+		s.RateLimited++
+		s.LastLimitAt = now
+		if err == nil {
+			s.LastError = "rate limited"
+		}
+	}
+}
+
+// Snapshot returns a copy of all server stats, sorted by server name
+func (t *Telemetry) Snapshot() []models.ServerStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]models.ServerStats, 0, len(t.servers))
+	for _, s := range t.servers {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Server < stats[j].Server })
+	return stats
+}
+
+// Health returns the current health of server
+func (t *Telemetry) Health(server string) Health {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.servers[server]
+	if !ok {
+		return HealthGood
+	}
+	return HealthOf(*s)
+}
+
+// HealthOf derives a health level from persisted stats
+func HealthOf(s models.ServerStats) Health {
+	switch {
+	case time.Since(s.LastLimitAt) < rateLimitCooldown || s.ErrorRate >= 0.5:
+		return HealthBad
+	case s.ErrorRate >= 0.2:
+		return HealthDegraded
+	default:
+		return HealthGood
+	}
+}
+
+// rateLimitMaxLen is the longest response that can be a throttling notice;
+// those are a line or two, records run far longer
+const rateLimitMaxLen = 512
+
+// classify maps a WHOIS error and response to an Outcome
+func classify(response string, err error) Outcome {
+	if rateLimited(response) {
+		return OutcomeRateLimited
+	}
+	if err == nil {
+		return OutcomeOK
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return OutcomeTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return OutcomeRefused
+	}
+	return OutcomeError
+}
+
+// rateLimited reports whether response is a throttling notice: short,
+// matching a rate-limit pattern and none of the patterns ReadWhois reads
+// records by
+func rateLimited(response string) bool {
+	if len(response) > rateLimitMaxLen {
+		return false
+	}
+	lower := strings.ToLower(response)
+	if !containsAny(lower, rateLimitPatterns) {
+		return false
+	}
+	return !containsAny(lower, takenPatterns) && !containsAny(lower, availablePatterns)
+}
+
+// containsAny reports whether s contains any of patterns
+func containsAny(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// ProbeTLS connects to domain on port 443 and returns the certificate it
+// serves. The chain isn't verified: an expired or misissued certificate is
+// exactly what the probe is for.
+func (c *Checker) ProbeTLS(ctx context.Context, domain string) (models.CertInfo, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: c.timeout},
+		Config:    &tls.Config{ServerName: domain, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(domain, "443"))
+	if err != nil {
+		return models.CertInfo{}, err
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return models.CertInfo{}, errors.New("no certificate presented")
+	}
+	leaf := certs[0]
+	issuer := leaf.Issuer.CommonName
+	if len(leaf.Issuer.Organization) > 0 {
+		issuer = leaf.Issuer.Organization[0]
+	}
+	return models.CertInfo{Issuer: issuer, NotAfter: leaf.NotAfter, CheckedAt: time.Now()}, nil
+}
@@ -0,0 +1,31 @@
+package checker
+
+import (
+	"github.com/berckan/domainhunter/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer traces bulk checks, their phases and each domain's lookups. It is
+// a no-op until a tracer provider is installed.
+var tracer = otel.Tracer("github.com/berckan/domainhunter/internal/checker")
+
+// endSpan marks span failed if err is set, and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// endCheckSpan records a domain check's outcome on span and ends it
+func endCheckSpan(span trace.Span, r models.DomainResult) {
+	span.SetAttributes(attribute.String("domain.status", string(r.Status)))
+	if r.Error != "" {
+		span.SetStatus(codes.Error, r.Error)
+	}
+	span.End()
+}
@@ -0,0 +1,377 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/logging"
+	"github.com/berckan/domainhunter/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+// ianaServer answers TLD -> WHOIS server lookups
+const ianaServer = "whois.iana.org"
+
+// errNoWhoisServer means IANA lists no WHOIS server for the TLD
+var errNoWhoisServer = errors.New("no whois server for tld")
+
+// errRateLimited means the WHOIS server answered with a throttling notice
+// rather than the domain's record
+var errRateLimited = errors.New("whois server is rate limiting")
+
+// whoisServer returns the registry WHOIS server for domain's TLD, asking
+// IANA once per TLD and caching the answer
+func (c *Checker) whoisServer(ctx context.Context, domain string) (string, error) {
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+
+	c.serversMu.Lock()
+	server, ok := c.servers[tld]
+	c.serversMu.Unlock()
+	if ok {
+		if server == "" {
+			return "", errNoWhoisServer
+		}
+		return server, nil
+	}
+
+	start := time.Now()
+	resp, err := c.whoisClient(ctx).Whois(tld)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	outcome, latency := classify(resp, err), time.Since(start)
+	slog.Log(ctx, logging.LevelTrace, "whois server lookup", "tld", tld, "server", ianaServer, "outcome", outcome.String(), "latency", latency.Round(time.Millisecond))
+	c.telemetry.Record(ianaServer, latency, outcome, err)
+	if err != nil {
+		// Don't cache transient IANA failures
+		return "", err
+	}
+	server = parseReferral(resp)
+
+	c.serversMu.Lock()
+	c.servers[tld] = server
+	c.serversMu.Unlock()
+
+	if server == "" {
+		return "", errNoWhoisServer
+	}
+	return server, nil
+}
+
+// parseReferral extracts the "whois:" server from an IANA TLD record
+func parseReferral(resp string) string {
+	for _, line := range strings.Split(resp, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(strings.ToLower(line), "whois:") {
+			return strings.TrimSpace(line[len("whois:"):])
+		}
+	}
+	return ""
+}
+
+// checkWHOIS checks the domains at idx by WHOIS, storing results at the
+// same index. Domains are grouped by registry WHOIS server and each group
+// gets its own worker pool, so a slow registry only holds up its own
+// domains. Domains whose server can't be found form one more group, where
+// check reports the failure.
+func (c *Checker) checkWHOIS(ctx context.Context, domains []string, idx []int, results []models.DomainResult, check func(context.Context, string) models.DomainResult) error {
+	tlds := make(map[string]string) // TLD -> a domain in it
+	for _, i := range idx {
+		tlds[domains[i][strings.LastIndex(domains[i], ".")+1:]] = domains[i]
+	}
+	var mu sync.Mutex
+	serverOf := make(map[string]string, len(tlds))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.whoisConcurrency)
+	for tld, domain := range tlds {
+		g.Go(func() error {
+			server, _ := c.whoisServer(gctx, domain)
+			mu.Lock()
+			serverOf[tld] = server
+			mu.Unlock()
+			return gctx.Err()
+		})
+	}
+	if err := g.Wait(); err != nil {
+		unchecked(domains, idx, results)
+		return err
+	}
+
+	groups := make(map[string][]int)
+	for _, i := range idx {
+		server := serverOf[domains[i][strings.LastIndex(domains[i], ".")+1:]]
+		groups[server] = append(groups[server], i)
+	}
+	g, gctx = errgroup.WithContext(ctx)
+	for server, group := range groups {
+		g.Go(func() error {
+			return c.pool(gctx, PhaseWHOIS+":"+cmp.Or(server, "none"), c.whoisConcurrency, domains, group, results, check, nil)
+		})
+	}
+	return g.Wait()
+}
+
+// queryWhois runs a WHOIS query against the TLD's server, gated by that
+// server's health, and records telemetry for it. If ctx is done first the
+// query's connection is closed and ctx.Err() returned. While the server's
+// circuit is open the query is skipped with errCircuitOpen, unless wait is
+// set and it can wait for the circuit to close. A throttling notice comes
+// back with errRateLimited.
+func (c *Checker) queryWhois(ctx context.Context, domain string, wait bool) (resp string, err error) {
+	server, err := c.whoisServer(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	// The span takes in time spent waiting on the server's circuit and
+	// gate, so a throttled registry shows up as well as a slow one
+	ctx, span := tracer.Start(ctx, "whois.query", trace.WithAttributes(attribute.String("whois.server", server)))
+	defer func() { endSpan(span, err) }()
+
+	circuit := c.breaker(server)
+	probe, err := circuit.enter(ctx, wait)
+	if err != nil {
+		if errors.Is(err, errCircuitOpen) {
+			slog.Log(ctx, logging.LevelTrace, "whois query skipped, circuit open", "domain", domain, "server", server)
+			return "", fmt.Errorf("%s: %w", server, err)
+		}
+		return "", err
+	}
+	if probe {
+		defer circuit.settle()
+	}
+
+	if err := c.pace.wait(ctx); err != nil {
+		return "", err
+	}
+	gate := c.gate(server)
+	if err := gate.acquire(ctx); err != nil {
+		return "", err
+	}
+	defer gate.release()
+	if err := gate.pace(ctx, c.whoisInterval); err != nil {
+		return "", err
+	}
+
+	c.whoisQueries.Add(1)
+	start := time.Now()
+	resp, err = c.whoisClient(ctx).Whois(domain, server)
+	if ctx.Err() != nil {
+		// Not the server's fault
+		return "", ctx.Err()
+	}
+	outcome, latency := classify(resp, err), time.Since(start)
+	span.SetAttributes(attribute.String("whois.outcome", outcome.String()))
+	slog.Log(ctx, logging.LevelTrace, "whois query", "domain", domain, "server", server, "outcome", outcome.String(), "latency", latency.Round(time.Millisecond))
+	c.telemetry.Record(server, latency, outcome, err)
+	gate.observe(outcome == OutcomeRateLimited || outcome == OutcomeRefused, outcome == OutcomeOK)
+	circuit.record(outcome == OutcomeTimeout || outcome == OutcomeRefused)
+
+	if outcome == OutcomeRateLimited && err == nil {
+		err = fmt.Errorf("%s: %w", server, errRateLimited)
+	}
+	return resp, err
+}
+
+// gate returns the concurrency gate for server, starting it at a limit
+// that suits the server's health
+func (c *Checker) gate(server string) *serverGate {
+	c.serversMu.Lock()
+	defer c.serversMu.Unlock()
+
+	g, ok := c.gates[server]
+	if !ok {
+		g = newServerGate(server, c.whoisConcurrency)
+		switch c.telemetry.Health(server) {
+		case HealthBad:
+			g.limit, g.interval = 1, minBackoffInterval
+		case HealthDegraded:
+			g.limit = max(1, g.max/2)
+		}
+		c.gates[server] = g
+	}
+	return g
+}
+
+// breaker returns the circuit breaker for server
+func (c *Checker) breaker(server string) *breaker {
+	c.serversMu.Lock()
+	defer c.serversMu.Unlock()
+
+	b, ok := c.breakers[server]
+	if !ok {
+		b = &breaker{name: server}
+		c.breakers[server] = b
+	}
+	return b
+}
+
+// Circuit returns when server's open circuit next lets a probe query
+// through, or zero if the circuit is closed
+func (c *Checker) Circuit(server string) time.Time {
+	c.serversMu.Lock()
+	b, ok := c.breakers[server]
+	c.serversMu.Unlock()
+	if !ok {
+		return time.Time{}
+	}
+	return b.state()
+}
+
+// Limit returns how many queries currently run at once to server and the
+// spacing added between them, after adapting to throttling
+func (c *Checker) Limit(server string) (int, time.Duration) {
+	c.serversMu.Lock()
+	g, ok := c.gates[server]
+	c.serversMu.Unlock()
+	if !ok {
+		return c.whoisConcurrency, 0
+	}
+	return g.state()
+}
+
+// Adaptive limits: a throttling signal halves a gate's concurrency and
+// doubles its spacing between queries, at most once per backoffSpacing;
+// every rampAfter clean responses in a row win back one slot and halve the
+// spacing again
+const (
+	backoffSpacing     = 5 * time.Second
+	rampAfter          = 20
+	minBackoffInterval = 250 * time.Millisecond
+	maxBackoffInterval = 10 * time.Second
+)
+
+// serverGate is a semaphore whose limit adapts to how the server responds,
+// with optional pacing between queries
+type serverGate struct {
+	name string
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	inUse int
+	next  time.Time // earliest start of the next paced query
+
+	limit    int           // current concurrency, 1 to max
+	max      int           // configured concurrency
+	interval time.Duration // spacing added after throttling
+	streak   int           // clean responses since the last change
+	backedAt time.Time     // last backoff
+}
+
+func newServerGate(name string, limit int) *serverGate {
+	g := &serverGate{name: name, limit: limit, max: limit}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+// acquire takes a slot, waiting while the gate is full, unless ctx is done
+// first
+func (g *serverGate) acquire(ctx context.Context) error {
+	// Waking waiters under the lock means none can miss it between checking
+	// ctx and waiting
+	stop := context.AfterFunc(ctx, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.cond.Broadcast()
+	})
+	defer stop()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.inUse >= g.limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		g.cond.Wait()
+	}
+	g.inUse++
+	return nil
+}
+
+// pace blocks until the next query slot, keeping starts at least interval
+// apart, or further while the server is backed off, unless ctx is done
+// first
+func (g *serverGate) pace(ctx context.Context, interval time.Duration) error {
+	g.mu.Lock()
+	interval = max(interval, g.interval)
+	if interval <= 0 {
+		g.mu.Unlock()
+		return ctx.Err()
+	}
+	slot := time.Now()
+	if g.next.After(slot) {
+		slot = g.next
+	}
+	g.next = slot.Add(interval)
+	g.mu.Unlock()
+	return sleep(ctx, time.Until(slot))
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *serverGate) release() {
+	g.mu.Lock()
+	g.inUse--
+	g.mu.Unlock()
+	g.cond.Broadcast()
+}
+
+// observe adapts the gate to a response: throttled backs off, clean counts
+// toward ramping back up, anything else leaves it be
+func (g *serverGate) observe(throttled, clean bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch {
+	case throttled:
+		g.streak = 0
+		if time.Since(g.backedAt) < backoffSpacing {
+			return
+		}
+		g.backedAt = time.Now()
+		g.limit = max(1, g.limit/2)
+		g.interval = min(maxBackoffInterval, max(minBackoffInterval, 2*g.interval))
+		slog.Warn("throttled, backing off", "server", g.name, "limit", g.limit, "interval", g.interval)
+	case clean:
+		if g.limit == g.max && g.interval == 0 {
+			return
+		}
+		if g.streak++; g.streak < rampAfter {
+			return
+		}
+		g.streak = 0
+		g.limit = min(g.max, g.limit+1)
+		if g.interval /= 2; g.interval < minBackoffInterval {
+			g.interval = 0
+		}
+		slog.Debug("answering cleanly, ramping back up", "server", g.name, "limit", g.limit, "interval", g.interval)
+		g.cond.Broadcast()
+	}
+}
+
+// state returns the gate's current concurrency and added spacing
+func (g *serverGate) state() (int, time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.limit, g.interval
+}
@@ -0,0 +1,106 @@
+package checker_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+func TestCheckRateLimited(t *testing.T) {
+	server := startWhois(t, "Query rate limit exceeded, try again later\r\n")
+	c := checker.New()
+	checker.SetWhoisServer(c, "test", server)
+
+	r := c.Check(context.Background(), "example.test")
+	if r.Status != models.StatusUnknown {
+		t.Errorf("status = %s, want %s", r.Status, models.StatusUnknown)
+	}
+	if !strings.Contains(r.Error, "rate limiting") {
+		t.Errorf("error = %q, want it to say the server is rate limiting", r.Error)
+	}
+}
+
+func TestCheckRecordMentioningLimits(t *testing.T) {
+	// A record's footer may use the rate-limit words in passing
+	record := "Domain Name: EXAMPLE.TEST\r\nRegistrar: Example Registrar\r\n" +
+		"Access denied to bulk queries that exceeded the quota.\r\n"
+	server := startWhois(t, record)
+	c := checker.New()
+	checker.SetWhoisServer(c, "test", server)
+
+	r := c.Check(context.Background(), "example.test")
+	if r.Status != models.StatusTaken || r.Error != "" {
+		t.Errorf("got %s with error %q, want taken without error", r.Status, r.Error)
+	}
+}
+
+// startWhois serves resp to every WHOIS query on a local port until the
+// test ends, returning its address
+func startWhois(t *testing.T, resp string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 512)
+				conn.Read(buf) // the query, one line
+				io.WriteString(conn, resp)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// BenchmarkReadWhois reaches a verdict on a typical registered-domain
+// response
+func BenchmarkReadWhois(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		checker.ReadWhois(sampleWhois)
+	}
+}
+
+// BenchmarkParseWhois parses the same response into a record
+func BenchmarkParseWhois(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		checker.ParseWhois(sampleWhois).DropPhase()
+	}
+}
+
+// sampleWhois is a registry response in the common ICANN layout
+const sampleWhois = `   Domain Name: EXAMPLE.COM
+   Registry Domain ID: 2336799_DOMAIN_COM-VRSN
+   Registrar WHOIS Server: whois.iana.org
+   Registrar URL: http://res-dom.iana.org
+   Updated Date: 2024-08-14T07:01:34Z
+   Creation Date: 1995-08-14T04:00:00Z
+   Registry Expiry Date: 2025-08-13T04:00:00Z
+   Registrar: RESERVED-Internet Assigned Numbers Authority
+   Registrar IANA ID: 376
+   Registrar Abuse Contact Email:
+   Registrar Abuse Contact Phone:
+   Domain Status: clientDeleteProhibited https://icann.org/epp#clientDeleteProhibited
+   Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited
+   Domain Status: clientUpdateProhibited https://icann.org/epp#clientUpdateProhibited
+   Name Server: A.IANA-SERVERS.NET
+   Name Server: B.IANA-SERVERS.NET
+   DNSSEC: signedDelegation
+   DNSSEC DS Data: 370 13 2 BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C
+   URL of the ICANN Whois Inaccuracy Complaint Form: https://www.icann.org/wicf/
+>>> Last update of whois database: 2024-09-23T11:02:38Z <<<
+`
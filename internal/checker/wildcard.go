@@ -0,0 +1,119 @@
+package checker
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+)
+
+// wildcardProbe is the answer, once known, to whether a TLD's zone has a
+// wildcard, so that names nobody registered still resolve
+type wildcardProbe struct {
+	done     chan struct{} // closed once probed
+	wildcard bool
+}
+
+// wildcard reports whether domain's TLD resolves names that don't exist,
+// which would make the DNS phase mark every candidate taken. The TLD is
+// probed with a random nonsense name the first time; a probe that fails is
+// tried again next time.
+func (c *Checker) wildcard(ctx context.Context, domain string) bool {
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+
+	c.serversMu.Lock()
+	p, ok := c.wildcards[tld]
+	if !ok {
+		p = &wildcardProbe{done: make(chan struct{})}
+		c.wildcards[tld] = p
+	}
+	c.serversMu.Unlock()
+	if ok {
+		select {
+		case <-p.done:
+			return p.wildcard
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	defer close(p.done)
+	qctx, cancel := context.WithTimeout(ctx, c.dnsTimeout)
+	defer cancel()
+	rcode, _, err := c.queryDNS(qctx, nonsenseLabel()+"."+tld)
+	if err != nil {
+		c.serversMu.Lock()
+		delete(c.wildcards, tld)
+		c.serversMu.Unlock()
+		return false
+	}
+	p.wildcard = rcode == dns.RcodeSuccess
+	if p.wildcard {
+		slog.InfoContext(ctx, "tld has wildcard dns, checking its names by whois", "tld", tld)
+	}
+	return p.wildcard
+}
+
+// wildcardTLDs probes the TLDs of domains at once, returning which of the
+// domains are under a wildcard TLD
+func (c *Checker) wildcardTLDs(ctx context.Context, domains []string) []bool {
+	first := make(map[string]string) // TLD -> a domain under it
+	for _, d := range domains {
+		tld := d[strings.LastIndex(d, ".")+1:]
+		if _, ok := first[tld]; !ok {
+			first[tld] = d
+		}
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wild := make(map[string]bool, len(first))
+	for tld, d := range first {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := c.wildcard(ctx, d)
+			mu.Lock()
+			wild[tld] = w
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	under := make([]bool, len(domains))
+	for i, d := range domains {
+		under[i] = wild[d[strings.LastIndex(d, ".")+1:]]
+	}
+	return under
+}
+
+// Wildcards returns the TLDs found to have wildcard DNS so far
+func (c *Checker) Wildcards() []string {
+	c.serversMu.Lock()
+	defer c.serversMu.Unlock()
+	var tlds []string
+	for tld, p := range c.wildcards {
+		select {
+		case <-p.done:
+			if p.wildcard {
+				tlds = append(tlds, tld)
+			}
+		default:
+		}
+	}
+	slices.Sort(tlds)
+	return tlds
+}
+
+// nonsenseLabel returns a random label no one would register
+func nonsenseLabel() string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, 24)
+	for i := range b {
+		b[i] = chars[rand.IntN(len(chars))]
+	}
+	return "dh-" + string(b)
+}
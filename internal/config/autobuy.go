@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+
+	"github.com/berckan/domainhunter/internal/autobuy"
+	"github.com/berckan/domainhunter/internal/checker"
+)
+
+// AutoBuy configures the registrar accounts watches can auto-register
+// through and where attempts are audited. Each watch sets its own
+// registrar, price ceiling and dry-run flag.
+type AutoBuy struct {
+	PorkbunAPIKey    string
+	PorkbunSecretKey string
+	NamecheapUser    string
+	NamecheapAPIKey  string
+	NamecheapIP      string
+	Contact          autobuy.Contact
+	AuditLog         string
+}
+
+// DefaultAutoBuy reads the auto-buy settings from the environment
+func DefaultAutoBuy() AutoBuy {
+	return AutoBuy{
+		PorkbunAPIKey:    os.Getenv("PORKBUN_API_KEY"),
+		PorkbunSecretKey: os.Getenv("PORKBUN_SECRET_KEY"),
+		NamecheapUser:    os.Getenv("NAMECHEAP_API_USER"),
+		NamecheapAPIKey:  os.Getenv("NAMECHEAP_API_KEY"),
+		NamecheapIP:      os.Getenv("NAMECHEAP_CLIENT_IP"),
+		Contact: autobuy.Contact{
+			FirstName:     os.Getenv("REGISTRANT_FIRST_NAME"),
+			LastName:      os.Getenv("REGISTRANT_LAST_NAME"),
+			Address1:      os.Getenv("REGISTRANT_ADDRESS"),
+			City:          os.Getenv("REGISTRANT_CITY"),
+			StateProvince: os.Getenv("REGISTRANT_STATE"),
+			PostalCode:    os.Getenv("REGISTRANT_POSTAL_CODE"),
+			Country:       os.Getenv("REGISTRANT_COUNTRY"),
+			Phone:         os.Getenv("REGISTRANT_PHONE"),
+			EmailAddress:  os.Getenv("REGISTRANT_EMAIL"),
+		},
+		AuditLog: envOr("AUTOBUY_AUDIT_LOG", "autobuy-audit.jsonl"),
+	}
+}
+
+// PremiumPricers returns the configured registrars for quoting premium
+// names, Porkbun first
+func (a AutoBuy) PremiumPricers() []checker.PremiumPricer {
+	rs := a.Registrars()
+	var ps []checker.PremiumPricer
+	for _, name := range []string{autobuy.Porkbun, autobuy.Namecheap} {
+		if p, ok := rs[name].(checker.PremiumPricer); ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// Registrars builds the configured registrars by name
+func (a AutoBuy) Registrars() map[string]autobuy.Registrar {
+	rs := make(map[string]autobuy.Registrar)
+	if a.PorkbunAPIKey != "" && a.PorkbunSecretKey != "" {
+		rs[autobuy.Porkbun] = &autobuy.PorkbunRegistrar{APIKey: a.PorkbunAPIKey, SecretKey: a.PorkbunSecretKey}
+	}
+	if a.NamecheapUser != "" && a.NamecheapAPIKey != "" {
+		rs[autobuy.Namecheap] = &autobuy.NamecheapRegistrar{
+			APIUser:  a.NamecheapUser,
+			APIKey:   a.NamecheapAPIKey,
+			ClientIP: a.NamecheapIP,
+			Contact:  a.Contact,
+		}
+	}
+	return rs
+}
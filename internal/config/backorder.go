@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/berckan/domainhunter/internal/backorder"
+)
+
+// Backorder configures the drop-catch services watches can backorder
+// through. Each watch sets its own provider and price cap; MonthlyBudget
+// caps the total committed per calendar month, zero meaning no limit.
+type Backorder struct {
+	DynadotAPIKey     string
+	DynadotPrice      float64
+	DropCatchClientID string
+	DropCatchSecret   string
+	MonthlyBudget     float64
+}
+
+// DefaultBackorder reads the backorder settings from the environment
+func DefaultBackorder() Backorder {
+	return Backorder{
+		DynadotAPIKey:     os.Getenv("DYNADOT_API_KEY"),
+		DynadotPrice:      envFloat("DYNADOT_BACKORDER_PRICE", 0),
+		DropCatchClientID: os.Getenv("DROPCATCH_CLIENT_ID"),
+		DropCatchSecret:   os.Getenv("DROPCATCH_CLIENT_SECRET"),
+		MonthlyBudget:     envFloat("BACKORDER_MONTHLY_BUDGET", 0),
+	}
+}
+
+// Providers builds the configured backorder providers by name
+func (b Backorder) Providers() map[string]backorder.Provider {
+	ps := make(map[string]backorder.Provider)
+	if b.DynadotAPIKey != "" {
+		ps[backorder.Dynadot] = &backorder.DynadotProvider{APIKey: b.DynadotAPIKey, Price: b.DynadotPrice}
+	}
+	if b.DropCatchClientID != "" && b.DropCatchSecret != "" {
+		ps[backorder.DropCatch] = &backorder.DropCatchProvider{ClientID: b.DropCatchClientID, ClientSecret: b.DropCatchSecret}
+	}
+	return ps
+}
+
+func envFloat(key string, fallback float64) float64 {
+	if f, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return f
+	}
+	return fallback
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"slices"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/enrich"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/social"
+)
+
+// Enrich configures what is looked up about findings before they're
+// reported. Every enrichment is optional and off by default.
+type Enrich struct {
+	// Trademarks screens names for exact-match trademarks at the USPTO,
+	// and at the EUIPO when its API credentials are set
+	Trademarks        bool   `yaml:"trademarks"`
+	EUIPOClientID     string `yaml:"euipo_client_id"`
+	EUIPOClientSecret string `yaml:"euipo_client_secret"`
+	// Wayback notes whether and when a domain had archived content
+	Wayback bool `yaml:"wayback"`
+	// Prior classifies domains as fresh or dropped from the Wayback
+	// Machine, plus passive DNS and WHOIS history when their keys are set
+	Prior                bool   `yaml:"prior"`
+	SecurityTrailsAPIKey string `yaml:"securitytrails_api_key"`
+	WhoisXMLAPIKey       string `yaml:"whoisxml_api_key"`
+	// Blocklists are the DNSBL zones findings are checked against; empty
+	// disables the check, [default] uses enrich.DefaultBlocklists
+	Blocklists []string `yaml:"blocklists"`
+	// SEO annotates findings with link metrics from each provider whose
+	// key is set, cached for SEOCacheTTL (default 30 days)
+	SEO         SEO           `yaml:"seo"`
+	SEOCacheTTL time.Duration `yaml:"seo_cache_ttl"`
+	// Appraisal estimates what available findings are worth, through
+	// GoDaddy when its API key is set and the built-in model otherwise
+	Appraisal        bool   `yaml:"appraisal"`
+	GoDaddyAPIKey    string `yaml:"godaddy_api_key"`
+	GoDaddyAPISecret string `yaml:"godaddy_api_secret"`
+	// MinAppraisal drops findings appraised below it, in US dollars
+	MinAppraisal float64 `yaml:"min_appraisal"`
+	// Handles checks whether names are free on GitHub, X and Instagram
+	Handles bool `yaml:"handles"`
+	// Renewals compares first-year and renewal prices across registrars
+	// and warns about renewal traps
+	Renewals bool `yaml:"renewals"`
+}
+
+// SEO holds the SEO providers' API credentials
+type SEO struct {
+	MozAccessID  string `yaml:"moz_access_id"`
+	MozSecretKey string `yaml:"moz_secret_key"`
+	AhrefsAPIKey string `yaml:"ahrefs_api_key"`
+	MajesticKey  string `yaml:"majestic_api_key"`
+}
+
+// providers builds the SEO providers that have credentials
+func (s SEO) providers() []enrich.SEOProvider {
+	var ps []enrich.SEOProvider
+	if s.MozAccessID != "" && s.MozSecretKey != "" {
+		ps = append(ps, enrich.Moz{AccessID: s.MozAccessID, SecretKey: s.MozSecretKey})
+	}
+	if s.AhrefsAPIKey != "" {
+		ps = append(ps, enrich.Ahrefs{APIKey: s.AhrefsAPIKey})
+	}
+	if s.MajesticKey != "" {
+		ps = append(ps, enrich.Majestic{APIKey: s.MajesticKey})
+	}
+	return ps
+}
+
+// DefaultEnrich returns the built-in enrichment settings: everything off,
+// SEO metrics cached for 30 days
+func DefaultEnrich() Enrich {
+	return Enrich{SEOCacheTTL: 30 * 24 * time.Hour}
+}
+
+// Enrichers builds the enabled enrichers. Paid lookups are cached in c.
+func (e Enrich) Enrichers(c enrich.Cache) []enrich.Enricher {
+	var es []enrich.Enricher
+	if e.Trademarks {
+		offices := []enrich.TrademarkOffice{enrich.USPTO{}}
+		if e.EUIPOClientID != "" && e.EUIPOClientSecret != "" {
+			offices = append(offices, &enrich.EUIPO{ClientID: e.EUIPOClientID, ClientSecret: e.EUIPOClientSecret})
+		}
+		es = append(es, &enrich.Trademarks{Offices: offices})
+	}
+	if e.Wayback {
+		es = append(es, enrich.Wayback{})
+	}
+	// After Wayback, whose history it reuses
+	if e.Prior {
+		sources := []enrich.PriorSource{enrich.WaybackSource{}}
+		if e.SecurityTrailsAPIKey != "" {
+			sources = append(sources, enrich.SecurityTrails{APIKey: e.SecurityTrailsAPIKey})
+		}
+		if e.WhoisXMLAPIKey != "" {
+			sources = append(sources, enrich.WhoisXMLHistory{APIKey: e.WhoisXMLAPIKey})
+		}
+		es = append(es, &enrich.Prior{Sources: sources})
+	}
+	// After Prior, so fresh domains are skipped
+	if len(e.Blocklists) > 0 {
+		zones := e.Blocklists
+		if slices.Equal(zones, []string{"default"}) {
+			zones = enrich.DefaultBlocklists
+		}
+		es = append(es, &enrich.DNSBL{Zones: zones})
+	}
+	// Without any keys there's simply no SEO data
+	if ps := e.SEO.providers(); len(ps) > 0 {
+		es = append(es, &enrich.SEO{Providers: ps, Cache: c, TTL: e.SEOCacheTTL})
+	}
+	// After SEO, whose authority the built-in model counts
+	if e.Appraisal {
+		var appraisers []enrich.Appraiser
+		if e.GoDaddyAPIKey != "" && e.GoDaddyAPISecret != "" {
+			appraisers = append(appraisers, enrich.GoDaddy{APIKey: e.GoDaddyAPIKey, APISecret: e.GoDaddyAPISecret})
+		}
+		es = append(es, &enrich.Appraisal{Appraisers: append(appraisers, enrich.Heuristic{})})
+	}
+	if e.Handles {
+		es = append(es, &enrich.Handles{Sites: social.Sites})
+	}
+	if e.Renewals {
+		es = append(es, &enrich.Renewals{Sources: DefaultAutoBuy().PriceSources()})
+	}
+	return es
+}
+
+// Appraised drops results appraised below MinAppraisal. Results without
+// an appraisal are kept.
+func (e Enrich) Appraised(results []models.DomainResult) []models.DomainResult {
+	if e.MinAppraisal <= 0 {
+		return results
+	}
+	var kept []models.DomainResult
+	for _, r := range results {
+		if a := r.Enrichment; a == nil || a.Appraisal == nil || a.Appraisal.Value >= e.MinAppraisal {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
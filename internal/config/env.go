@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envVar is an environment variable that overrides a config field
+type envVar struct {
+	name string
+	set  func(c *Scan, value string) error
+}
+
+// envVars are the environment variables ApplyEnv reads, in the order it
+// applies them
+var envVars = []envVar{
+	{"DB_PATH", setString(func(c *Scan) *string { return &c.Database })},
+	{"SCAN_SCHEDULE", setString(func(c *Scan) *string { return &c.Schedule })},
+	{"RECAP_WEEKDAY", setString(func(c *Scan) *string { return &c.RecapWeekday })},
+	{"DIGEST_WEEKDAY", setString(func(c *Scan) *string { return &c.DigestWeekday })},
+	{"TLD_PRIORITY", setTLDPriority},
+
+	// The profile and catch-all timeout replace what the file set, and
+	// come first for the variables below to override
+	{"CHECK_PROFILE", func(c *Scan, v string) error {
+		c.Concurrency.UseProfile(v)
+		return nil
+	}},
+	{"CHECK_TIMEOUT", func(c *Scan, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		c.Concurrency.UseTimeout(d)
+		return nil
+	}},
+	{"CHECK_DNS_CONCURRENCY", setInt(func(c *Scan) *int { return &c.Concurrency.DNS })},
+	{"CHECK_DNS_TIMEOUT", setDuration(func(c *Scan) *time.Duration { return &c.Concurrency.DNSTimeout })},
+	{"CHECK_WHOIS_CONCURRENCY", setInt(func(c *Scan) *int { return &c.Concurrency.WHOIS })},
+	{"CHECK_WHOIS_TIMEOUT", setDuration(func(c *Scan) *time.Duration { return &c.Concurrency.WHOISTimeout })},
+	{"WHOIS_PROXIES", setList(func(c *Scan) *[]string { return &c.Concurrency.WHOISProxies })},
+	{"WHOIS_PROXY_ROTATE", setInt(func(c *Scan) *int { return &c.Concurrency.WHOISProxyRotate })},
+	{"CHECK_QPS", setFloat(func(c *Scan) *float64 { return &c.Concurrency.QPS })},
+	{"CHECK_QPS_PER_SERVER", setFloat(func(c *Scan) *float64 { return &c.Concurrency.WHOISQPS })},
+	{"CHECK_MAX_CONCURRENCY", setInt(func(c *Scan) *int { return &c.Concurrency.MaxConcurrency })},
+
+	{"RESEND_API_KEY", setString(func(c *Scan) *string { return &c.Notify.Resend.APIKey })},
+	{"EMAIL_TO", func(c *Scan, v string) error {
+		c.Notify.Resend.To, c.Notify.SMTP.To = v, v
+		return nil
+	}},
+	{"SMTP_HOST", setString(func(c *Scan) *string { return &c.Notify.SMTP.Host })},
+	{"SMTP_PORT", setInt(func(c *Scan) *int { return &c.Notify.SMTP.Port })},
+	{"SMTP_USER", func(c *Scan, v string) error {
+		// The user sends as themselves unless SMTP_FROM or the file says
+		// otherwise
+		c.Notify.SMTP.Username = v
+		if c.Notify.SMTP.From == "" {
+			c.Notify.SMTP.From = v
+		}
+		return nil
+	}},
+	{"SMTP_PASS", setString(func(c *Scan) *string { return &c.Notify.SMTP.Password })},
+	{"SMTP_FROM", setString(func(c *Scan) *string { return &c.Notify.SMTP.From })},
+	{"SMTP_TLS", setString(func(c *Scan) *string { return &c.Notify.SMTP.TLS })},
+	{"SLACK_WEBHOOK_URL", setString(func(c *Scan) *string { return &c.Notify.Slack.WebhookURL })},
+	{"SLACK_BOT_TOKEN", setString(func(c *Scan) *string { return &c.Notify.Slack.Token })},
+	{"SLACK_CHANNEL", setString(func(c *Scan) *string { return &c.Notify.Slack.Channel })},
+	{"DISCORD_WEBHOOK_URL", setString(func(c *Scan) *string { return &c.Notify.Discord.WebhookURL })},
+	{"TELEGRAM_BOT_TOKEN", setString(func(c *Scan) *string { return &c.Notify.Telegram.Token })},
+	{"TELEGRAM_CHAT_ID", setString(func(c *Scan) *string { return &c.Notify.Telegram.ChatID })},
+	{"WEBHOOK_URL", setString(func(c *Scan) *string { return &c.Notify.Webhook.URL })},
+	{"WEBHOOK_SECRET", setString(func(c *Scan) *string { return &c.Notify.Webhook.Secret })},
+
+	{"EUIPO_CLIENT_ID", setString(func(c *Scan) *string { return &c.Enrich.EUIPOClientID })},
+	{"EUIPO_CLIENT_SECRET", setString(func(c *Scan) *string { return &c.Enrich.EUIPOClientSecret })},
+	{"SECURITYTRAILS_API_KEY", setString(func(c *Scan) *string { return &c.Enrich.SecurityTrailsAPIKey })},
+	{"WHOISXML_API_KEY", setString(func(c *Scan) *string { return &c.Enrich.WhoisXMLAPIKey })},
+	{"MOZ_ACCESS_ID", setString(func(c *Scan) *string { return &c.Enrich.SEO.MozAccessID })},
+	{"MOZ_SECRET_KEY", setString(func(c *Scan) *string { return &c.Enrich.SEO.MozSecretKey })},
+	{"AHREFS_API_KEY", setString(func(c *Scan) *string { return &c.Enrich.SEO.AhrefsAPIKey })},
+	{"MAJESTIC_API_KEY", setString(func(c *Scan) *string { return &c.Enrich.SEO.MajesticKey })},
+	{"GODADDY_API_KEY", setString(func(c *Scan) *string { return &c.Enrich.GoDaddyAPIKey })},
+	{"GODADDY_API_SECRET", setString(func(c *Scan) *string { return &c.Enrich.GoDaddyAPISecret })},
+
+	{"LAUNCH_CALENDAR", setString(func(c *Scan) *string { return &c.Launches.Calendar })},
+	{"LAUNCH_KEYWORDS", setList(func(c *Scan) *[]string { return &c.Launches.Keywords })},
+
+	{"PORT", setString(func(c *Scan) *string { return &c.Server.Port })},
+	{"ADMIN_TOKEN", setString(func(c *Scan) *string { return &c.Server.AdminToken })},
+	{"REDIS_URL", setString(func(c *Scan) *string { return &c.Server.RedisURL })},
+	{"DEBUG_ADDR", setString(func(c *Scan) *string { return &c.Server.DebugAddr })},
+	{"WATCH_INTERVAL", setDuration(func(c *Scan) *time.Duration { return &c.Server.WatchInterval })},
+	{"ALERT_REMIND", setDuration(func(c *Scan) *time.Duration { return &c.Server.AlertRemind })},
+	{"CT_KEYWORDS", setList(func(c *Scan) *[]string { return &c.Server.CTKeywords })},
+	{"TLS_CERT", setString(func(c *Scan) *string { return &c.Server.TLSCert })},
+	{"TLS_KEY", setString(func(c *Scan) *string { return &c.Server.TLSKey })},
+	{"AUTOCERT_DOMAINS", setList(func(c *Scan) *[]string { return &c.Server.AutocertDomains })},
+	{"AUTOCERT_CACHE", setString(func(c *Scan) *string { return &c.Server.AutocertCache })},
+	{"TRUSTED_PROXY", setList(func(c *Scan) *[]string { return &c.Server.TrustedProxy })},
+}
+
+// ApplyEnv overrides c with the environment variables that are set and
+// not empty. A value that doesn't parse is an error naming its variable.
+func (c *Scan) ApplyEnv() error {
+	for _, v := range envVars {
+		value := strings.TrimSpace(os.Getenv(v.name))
+		if value == "" {
+			continue
+		}
+		if err := v.set(c, value); err != nil {
+			return fmt.Errorf("%s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+func setString(field func(*Scan) *string) func(*Scan, string) error {
+	return func(c *Scan, v string) error {
+		*field(c) = v
+		return nil
+	}
+}
+
+func setInt(field func(*Scan) *int) func(*Scan, string) error {
+	return func(c *Scan, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", v)
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func setFloat(field func(*Scan) *float64) func(*Scan, string) error {
+	return func(c *Scan, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", v)
+		}
+		*field(c) = f
+		return nil
+	}
+}
+
+func setDuration(field func(*Scan) *time.Duration) func(*Scan, string) error {
+	return func(c *Scan, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+// setList sets a list from comma-separated items
+func setList(field func(*Scan) *[]string) func(*Scan, string) error {
+	return func(c *Scan, v string) error {
+		var list []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field(c) = list
+		return nil
+	}
+}
+
+// setTLDPriority sets TLD priorities from tld:priority pairs, e.g.
+// "com:1,io:1,ai:2", keeping the rest of each TLD's settings
+func setTLDPriority(c *Scan, v string) error {
+	for _, entry := range strings.Split(v, ",") {
+		tld, p, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		tld = strings.ToLower(strings.TrimPrefix(tld, "."))
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 || tld == "" {
+			return fmt.Errorf("%q is not tld:priority", entry)
+		}
+		if c.TLDSettings == nil {
+			c.TLDSettings = make(map[string]TLDSetting)
+		}
+		set := c.TLDSettings[tld]
+		set.Priority = n
+		c.TLDSettings[tld] = set
+	}
+	return nil
+}
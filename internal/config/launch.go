@@ -0,0 +1,26 @@
+package config
+
+import (
+	"errors"
+	"strings"
+)
+
+// Launches configures scanning new TLDs as they open
+type Launches struct {
+	// Calendar is a CSV file or URL of TLD launch dates; see launch.Parse
+	Calendar string `yaml:"calendar"`
+	// Keywords are the names checked on each TLD when it reaches general
+	// availability
+	Keywords []string `yaml:"keywords"`
+}
+
+// validate normalizes the keywords and checks a calendar has some
+func (l *Launches) validate() error {
+	for i, k := range l.Keywords {
+		l.Keywords[i] = strings.ToLower(strings.TrimSpace(k))
+	}
+	if l.Calendar != "" && len(l.Keywords) == 0 {
+		return errors.New("launches: a calendar needs keywords to scan")
+	}
+	return nil
+}
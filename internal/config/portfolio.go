@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+
+	"github.com/berckan/domainhunter/internal/portfolio"
+)
+
+// PortfolioSources returns the registrar accounts domains can be imported
+// from by name: the auto-buy accounts, plus Cloudflare Registrar when
+// CLOUDFLARE_API_TOKEN and CLOUDFLARE_ACCOUNT_ID are set
+func (a AutoBuy) PortfolioSources() map[string]portfolio.Source {
+	sources := make(map[string]portfolio.Source)
+	for name, r := range a.Registrars() {
+		if s, ok := r.(portfolio.Source); ok {
+			sources[name] = s
+		}
+	}
+	if token, account := os.Getenv("CLOUDFLARE_API_TOKEN"), os.Getenv("CLOUDFLARE_ACCOUNT_ID"); token != "" && account != "" {
+		cf := portfolio.Cloudflare{Token: token, AccountID: account}
+		sources[cf.Name()] = cf
+	}
+	return sources
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/autobuy"
+	"github.com/berckan/domainhunter/internal/pricing"
+)
+
+// PriceTLDs returns the TLDs whose registrar prices are tracked, from the
+// comma-separated PRICE_TLDS
+func PriceTLDs() []string {
+	var tlds []string
+	for _, t := range strings.Split(os.Getenv("PRICE_TLDS"), ",") {
+		if t = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(t)), "."); t != "" {
+			tlds = append(tlds, t)
+		}
+	}
+	return tlds
+}
+
+// PriceSources returns the registrars prices are fetched from: Porkbun's
+// public price list, plus Namecheap when its API is set up
+func (a AutoBuy) PriceSources() []pricing.Source {
+	sources := []pricing.Source{pricing.Porkbun{}}
+	if r, ok := a.Registrars()[autobuy.Namecheap].(*autobuy.NamecheapRegistrar); ok {
+		sources = append(sources, pricing.Namecheap{Registrar: r})
+	}
+	return sources
+}
@@ -0,0 +1,838 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+	"github.com/berckan/domainhunter/internal/schedule"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoOutput is returned by Validate when results would go nowhere
+var ErrNoOutput = errors.New("no notification channel or output file configured")
+
+// Scan is the configuration every command and the server share; see Load.
+// Values of the form ${VAR} are expanded from the environment, so secrets
+// can stay out of the file.
+type Scan struct {
+	Database      string                `yaml:"database"`
+	RecapWeekday  string                `yaml:"recap_weekday"`
+	DigestWeekday string                `yaml:"digest_weekday"` // send weekly digests instead of daily reports
+	Schedule      string                `yaml:"schedule"`       // cron expression for --daemon
+	TLDLists      map[string][]string   `yaml:"tld_lists"`
+	TLDSettings   map[string]TLDSetting `yaml:"tld_settings"`
+	Scans         []ScanSpec            `yaml:"scans"`
+	ChunkSize     int                   `yaml:"chunk_size"` // domains checked between checkpoints
+	Concurrency   Concurrency           `yaml:"concurrency"`
+	Budget        Budget                `yaml:"budget"`
+	Stealth       Stealth               `yaml:"stealth"`
+	Notify        Notify                `yaml:"notify"`
+	Enrich        Enrich                `yaml:"enrich"`
+	Launches      Launches              `yaml:"launches"`
+	Output        Output                `yaml:"output"`
+	Server        Server                `yaml:"server"`
+}
+
+// TLDSetting tunes how one TLD is scanned
+type TLDSetting struct {
+	// Enabled false skips the TLD in every scan
+	Enabled *bool `yaml:"enabled"`
+	// Priority orders TLDs: 1 is checked first, then 2, and so on; TLDs
+	// without a priority come last
+	Priority int `yaml:"priority"`
+}
+
+// ScanSpec describes one generated candidate set
+type ScanSpec struct {
+	// Name keys the scan's checkpoint; defaults to length+prefix
+	Name   string `yaml:"name"`
+	Length int    `yaml:"length"`
+	Prefix string `yaml:"prefix"`
+	// TLDs lists TLDs explicitly; otherwise TLDList names a list from
+	// tld_lists or a built-in one ("premium", "common")
+	TLDs    []string `yaml:"tlds"`
+	TLDList string   `yaml:"tld_list"`
+	// Charset is "alnum" (default), "letters" or "digits"
+	Charset string `yaml:"charset"`
+}
+
+// Chars returns the characters the spec's names are built from
+func (s ScanSpec) Chars() string {
+	if s.Charset == "" {
+		return checker.Charsets["alnum"]
+	}
+	return checker.Charsets[s.Charset]
+}
+
+// Concurrency tunes the checker; zero keeps the checker default. Each
+// layer of settings (file, environment, flags) expands its profile and
+// timeout into the fields as it's applied, so one given in a higher layer
+// replaces what lower ones set.
+type Concurrency struct {
+	// Profile is a preset from Profiles the other settings start from
+	Profile string `yaml:"profile"`
+
+	DNS   int `yaml:"dns"`
+	WHOIS int `yaml:"whois"`
+	// DNSTimeout caps the DNS phase's time on one domain, retries included
+	DNSTimeout time.Duration `yaml:"dns_timeout"`
+	// WHOISTimeout caps one WHOIS query, from dialing the server on
+	WHOISTimeout time.Duration `yaml:"whois_timeout"`
+	// DNSQPS caps queries per second in the DNS phase
+	DNSQPS float64 `yaml:"dns_qps"`
+	// DNSRetries is how often a timed-out DNS query is sent again; unset
+	// keeps the profile's, and 0 sends each query once
+	DNSRetries *int `yaml:"dns_retries"`
+	// WHOISQPS caps queries per second to each WHOIS server
+	WHOISQPS float64 `yaml:"whois_qps_per_server"`
+	// WHOISDialInterval spaces out new connections to each WHOIS server
+	WHOISDialInterval time.Duration `yaml:"whois_dial_interval"`
+	// WHOISPreDial keeps a connection to each WHOIS server dialed ahead
+	WHOISPreDial bool `yaml:"whois_pre_dial"`
+	// SourceAddr is the local IP WHOIS connections are made from
+	SourceAddr string `yaml:"source_addr"`
+	// WHOISProxies are socks5:// or http:// proxies WHOIS queries go
+	// through, each used for WHOISProxyRotate connections in turn
+	WHOISProxies     []string `yaml:"whois_proxies"`
+	WHOISProxyRotate int      `yaml:"whois_proxy_rotate"`
+
+	// QPS caps all DNS and WHOIS queries together per second, e.g. to keep
+	// to a registry's published limit
+	QPS float64 `yaml:"qps"`
+	// MaxConcurrency caps DNS and per-server WHOIS concurrency, whatever
+	// the profile or the settings above
+	MaxConcurrency int `yaml:"max_concurrency"`
+	// Timeout is the DNS and WHOIS timeout where dns_timeout or
+	// whois_timeout aren't set
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// TLDPriority returns the TLDs given a priority in tld_settings
+func (c *Scan) TLDPriority() map[string]int {
+	priority := make(map[string]int)
+	for tld, set := range c.TLDSettings {
+		if set.Priority > 0 {
+			priority[tld] = set.Priority
+		}
+	}
+	return priority
+}
+
+// Profiles are the named concurrency presets. normal is the checker's
+// defaults; gentle suits shared hosts and strict registries, aggressive a
+// dedicated box with its own resolver.
+var Profiles = map[string]Concurrency{
+	"gentle": {
+		DNS: 10, DNSQPS: 100, DNSRetries: ptr(3),
+		WHOIS: 1, WHOISQPS: 0.5, WHOISDialInterval: time.Second,
+	},
+	"normal": {
+		DNS: 50, DNSQPS: 1000, DNSRetries: ptr(2),
+		WHOIS: 5, WHOISDialInterval: 100 * time.Millisecond,
+	},
+	"aggressive": {
+		DNS: 200, DNSQPS: 1400, DNSRetries: ptr(1),
+		WHOIS: 10, WHOISDialInterval: 20 * time.Millisecond,
+	},
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// Validate checks the settings make sense
+func (cc Concurrency) Validate() error {
+	if _, ok := Profiles[cc.Profile]; cc.Profile != "" && !ok {
+		return fmt.Errorf("concurrency.profile: unknown profile %q (want gentle, normal or aggressive)", cc.Profile)
+	}
+	if cc.DNS < 0 || cc.DNSQPS < 0 || (cc.DNSRetries != nil && *cc.DNSRetries < 0) || cc.DNSTimeout < 0 || cc.WHOIS < 0 || cc.WHOISQPS < 0 || cc.WHOISTimeout < 0 || cc.WHOISDialInterval < 0 || cc.WHOISProxyRotate < 0 ||
+		cc.QPS < 0 || cc.MaxConcurrency < 0 || cc.Timeout < 0 {
+		return errors.New("concurrency: values must not be negative")
+	}
+	if a := cc.SourceAddr; a != "" && net.ParseIP(a) == nil {
+		return fmt.Errorf("concurrency.source_addr: %q is not an IP address", a)
+	}
+	for _, p := range cc.WHOISProxies {
+		if _, err := checker.ParseProxy(p); err != nil {
+			return fmt.Errorf("concurrency.whois_proxies: %w", err)
+		}
+	}
+	return nil
+}
+
+// UseProfile switches to the named profile, replacing every setting it
+// covers
+func (cc *Concurrency) UseProfile(name string) {
+	p := Profiles[name]
+	cc.Profile = name
+	cc.DNS, cc.DNSQPS, cc.DNSRetries = p.DNS, p.DNSQPS, nil
+	if p.DNSRetries != nil {
+		cc.DNSRetries = ptr(*p.DNSRetries)
+	}
+	cc.WHOIS, cc.WHOISQPS, cc.WHOISDialInterval = p.WHOIS, p.WHOISQPS, p.WHOISDialInterval
+}
+
+// UseTimeout sets both the DNS and the WHOIS timeout
+func (cc *Concurrency) UseTimeout(d time.Duration) {
+	cc.Timeout, cc.DNSTimeout, cc.WHOISTimeout = d, d, d
+}
+
+// expand fills the settings a config file leaves out from its profile and
+// timeout; the ones it gives override them
+func (cc *Concurrency) expand() {
+	p := Profiles[cc.Profile]
+	cc.DNS = cmp.Or(cc.DNS, p.DNS)
+	cc.DNSQPS = cmp.Or(cc.DNSQPS, p.DNSQPS)
+	if cc.DNSRetries == nil && p.DNSRetries != nil {
+		cc.DNSRetries = ptr(*p.DNSRetries)
+	}
+	cc.WHOIS = cmp.Or(cc.WHOIS, p.WHOIS)
+	cc.WHOISQPS = cmp.Or(cc.WHOISQPS, p.WHOISQPS)
+	cc.WHOISDialInterval = cmp.Or(cc.WHOISDialInterval, p.WHOISDialInterval)
+	cc.DNSTimeout = cmp.Or(cc.DNSTimeout, cc.Timeout)
+	cc.WHOISTimeout = cmp.Or(cc.WHOISTimeout, cc.Timeout)
+}
+
+// Options returns the checker options for the settings; call Validate
+// first
+func (cc Concurrency) Options() []checker.Option {
+	retries := -1
+	if cc.DNSRetries != nil {
+		retries = *cc.DNSRetries
+	}
+	var proxies []*url.URL
+	for _, p := range cc.WHOISProxies {
+		if u, err := checker.ParseProxy(p); err == nil {
+			proxies = append(proxies, u)
+		}
+	}
+	return []checker.Option{
+		checker.WithDNSConcurrency(cc.DNS),
+		checker.WithDNSQPS(cc.DNSQPS),
+		checker.WithDNSRetries(retries),
+		checker.WithDNSTimeout(cc.DNSTimeout),
+		checker.WithWHOISConcurrency(cc.WHOIS),
+		checker.WithWHOISTimeout(cc.WHOISTimeout),
+		checker.WithWHOISQPS(cc.WHOISQPS),
+		checker.WithWHOISDialInterval(cc.WHOISDialInterval),
+		checker.WithWHOISPreDial(cc.WHOISPreDial),
+		checker.WithSourceAddr(net.ParseIP(cc.SourceAddr)),
+		checker.WithWHOISProxies(cc.WHOISProxyRotate, proxies...),
+		checker.WithQPS(cc.QPS),
+		checker.WithMaxConcurrency(cc.MaxConcurrency),
+	}
+}
+
+// Notify configures notification channels; every enabled one is used
+type Notify struct {
+	// EmailTemplate is an html/template file for report emails; empty uses
+	// the built-in one
+	EmailTemplate string `yaml:"email_template"`
+	// Registrars are linked from each domain in report emails
+	Registrars []notify.Registrar `yaml:"registrars"`
+
+	Resend   Resend   `yaml:"resend"`
+	SMTP     SMTP     `yaml:"smtp"`
+	Slack    Slack    `yaml:"slack"`
+	Discord  Discord  `yaml:"discord"`
+	Telegram Telegram `yaml:"telegram"`
+	Webhook  Webhook  `yaml:"webhook"`
+	File     File     `yaml:"file"`
+
+	// Fallback chains notifiers by name, e.g. [resend, smtp, file]: each is
+	// only tried when the ones before it failed. Notifiers not listed still
+	// all get the report.
+	Fallback []string `yaml:"fallback"`
+
+	// TLDs, if set, limits reports to findings in these TLDs
+	TLDs []string `yaml:"tlds"`
+	// MinDomains holds a report back until it has at least this many
+	// findings; held findings stay unreported and count toward the next one
+	MinDomains int `yaml:"min_domains"`
+
+	// Escalate sends the new findings a rule matches right away through
+	// the rule's channels, skipping the quiet conditions and digest wait;
+	// the rest are reported as usual
+	Escalate []notify.Rule `yaml:"escalate"`
+
+	// Partial sends a report when a signal stops a run, with what it found
+	// before stopping; the run that carries on reports the rest
+	Partial bool `yaml:"partial"`
+}
+
+// Select returns the findings that pass the TLD list, and whether there
+// are enough of them to notify
+func (n Notify) Select(results []models.DomainResult) ([]models.DomainResult, bool) {
+	selected := results
+	if len(n.TLDs) > 0 {
+		selected = nil
+		for _, r := range results {
+			if slices.Contains(n.TLDs, r.Domain[strings.LastIndex(r.Domain, ".")+1:]) {
+				selected = append(selected, r)
+			}
+		}
+	}
+	return selected, len(selected) >= n.MinDomains
+}
+
+// Resend configures email delivery through the Resend API
+type Resend struct {
+	APIKey string `yaml:"api_key"`
+	From   string `yaml:"from"`
+	// To is a comma-separated list of addresses that get every finding
+	To string `yaml:"to"`
+	// Recipients adds addresses with their own TLD filters
+	Recipients []notify.Recipient `yaml:"recipients"`
+}
+
+// Enabled reports whether Resend has enough settings to send
+func (r Resend) Enabled() bool {
+	return r.APIKey != "" && len(notify.ParseRecipients(r.To, r.Recipients)) > 0
+}
+
+// SMTP configures email delivery through an SMTP server
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// TLS is "starttls" (default), "tls" or "none"
+	TLS        string             `yaml:"tls"`
+	From       string             `yaml:"from"`
+	To         string             `yaml:"to"`
+	Recipients []notify.Recipient `yaml:"recipients"`
+}
+
+// Enabled reports whether SMTP has enough settings to send
+func (s SMTP) Enabled() bool {
+	return s.Host != "" && len(notify.ParseRecipients(s.To, s.Recipients)) > 0
+}
+
+// Slack posts to a channel through an incoming webhook, or with a bot
+// token and channel via chat.postMessage
+type Slack struct {
+	WebhookURL string `yaml:"webhook_url"`
+	Token      string `yaml:"token"`
+	Channel    string `yaml:"channel"`
+}
+
+// Enabled reports whether Slack has enough settings to post
+func (s Slack) Enabled() bool {
+	return s.WebhookURL != "" || (s.Token != "" && s.Channel != "")
+}
+
+// Discord posts to a channel through a webhook
+type Discord struct {
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// Telegram sends messages to a chat through a bot
+type Telegram struct {
+	Token  string `yaml:"token"`
+	ChatID string `yaml:"chat_id"`
+}
+
+// Webhook POSTs results as JSON to URL, signed with Secret when set
+type Webhook struct {
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret"`
+}
+
+// File writes reports as JSON files into Dir
+type File struct {
+	Dir string `yaml:"dir"`
+}
+
+// DefaultNotify returns the built-in notification settings: no channels,
+// the default registrar links, Resend's test sender and SMTP on port 587
+// with STARTTLS
+func DefaultNotify() Notify {
+	return Notify{
+		Registrars: notify.DefaultRegistrars,
+		Resend:     Resend{From: "Domain Hunter <onboarding@resend.dev>"},
+		SMTP:       SMTP{Port: 587, TLS: notify.TLSStartTLS},
+	}
+}
+
+// Notifiers builds the enabled notification channels
+func (c *Scan) Notifiers() []notify.Notifier {
+	return c.Notify.Notifiers()
+}
+
+// Notifiers builds the enabled notification channels, with the fallback
+// chain, if any, first
+func (n Notify) Notifiers() []notify.Notifier {
+	ns := n.enabled()
+	if len(n.Fallback) == 0 {
+		return ns
+	}
+
+	// Pull the chained notifiers out into one that goes first
+	var chain notify.Fallback
+	for _, name := range n.Fallback {
+		for _, nt := range ns {
+			if nt.Name() == name {
+				chain = append(chain, nt)
+			}
+		}
+	}
+	rest := slices.DeleteFunc(ns, func(nt notify.Notifier) bool {
+		return slices.Contains(n.Fallback, nt.Name())
+	})
+	return append([]notify.Notifier{chain}, rest...)
+}
+
+// Channels returns the enabled notifiers with the given names, where email
+// stands for resend and smtp, outside any fallback chain
+func (n Notify) Channels(names []string) []notify.Notifier {
+	var ns []notify.Notifier
+	for _, nt := range n.enabled() {
+		name := nt.Name()
+		if slices.Contains(names, name) || (slices.Contains(names, "email") && (name == "resend" || name == "smtp")) {
+			ns = append(ns, nt)
+		}
+	}
+	return ns
+}
+
+// enabled builds each configured notifier on its own
+func (n Notify) enabled() []notify.Notifier {
+	var ns []notify.Notifier
+	if r := n.Resend; r.Enabled() {
+		resend := notify.NewResend(r.APIKey, r.From, notify.ParseRecipients(r.To, r.Recipients))
+		resend.Template = n.EmailTemplate
+		resend.Registrars = n.Registrars
+		ns = append(ns, resend)
+	}
+	if s := n.SMTP; s.Enabled() {
+		ns = append(ns, &notify.SMTP{
+			Host:       s.Host,
+			Port:       s.Port,
+			Username:   s.Username,
+			Password:   s.Password,
+			TLS:        s.TLS,
+			From:       s.From,
+			Recipients: notify.ParseRecipients(s.To, s.Recipients),
+			Template:   n.EmailTemplate,
+			Registrars: n.Registrars,
+		})
+	}
+	if s := n.Slack; s.Enabled() {
+		ns = append(ns, notify.NewSlack(s.WebhookURL, s.Token, s.Channel))
+	}
+	if d := n.Discord; d.WebhookURL != "" {
+		ns = append(ns, notify.NewDiscord(d.WebhookURL))
+	}
+	if t := n.Telegram; t.Token != "" && t.ChatID != "" {
+		ns = append(ns, notify.NewTelegram(t.Token, t.ChatID))
+	}
+	if w := n.Webhook; w.URL != "" {
+		ns = append(ns, notify.NewWebhook(w.URL, w.Secret))
+	}
+	if n.File.Dir != "" {
+		ns = append(ns, &notify.File{Dir: n.File.Dir})
+	}
+	return ns
+}
+
+// Output configures where results are written besides notifications
+type Output struct {
+	// File receives the available findings
+	File string `yaml:"file"`
+	// Format is json, ndjson, csv or table; empty infers it from the
+	// file extension
+	Format string `yaml:"format"`
+	// Results receives every checked domain as NDJSON, appended as the
+	// scan goes; "-" is stdout
+	Results string `yaml:"results"`
+	// Unchecked receives, one per line, the domains a run stopped by its
+	// budget left for the next run
+	Unchecked string `yaml:"unchecked"`
+}
+
+// Budget caps each run's work. A run that spends it stops between chunks,
+// keeping its checkpoints, and the next run carries on where it stopped.
+type Budget struct {
+	// MaxWHOIS is how many WHOIS queries a run may send; 0 is no limit
+	MaxWHOIS int64 `yaml:"max_whois"`
+	// MaxDuration is how long a run may take; 0 is no limit
+	MaxDuration time.Duration `yaml:"max_duration"`
+}
+
+// DefaultJitter is how much Stealth randomizes the gaps between queries
+// when its jitter isn't set
+const DefaultJitter = 0.3
+
+// Stealth spreads each run's queries evenly over a window, e.g. 31k
+// domains over 12 hours, so a long scan trickles in below registries'
+// radar instead of arriving in bursts
+type Stealth struct {
+	// Window is how long a run should take; 0 scans at full speed
+	Window time.Duration `yaml:"window"`
+	// Jitter randomizes each gap between queries by up to ±Jitter of it,
+	// from 0 to 1; 0 means DefaultJitter
+	Jitter float64 `yaml:"jitter"`
+}
+
+// DefaultScan returns the built-in configuration: 1- and 2-char names
+// across the premium TLDs at 7:00 every day, with no notification channels
+func DefaultScan() *Scan {
+	return &Scan{
+		Database: "domainhunter.db",
+		Schedule: "0 7 * * *",
+		Scans: []ScanSpec{
+			{Name: "1", Length: 1, TLDList: "premium"},
+			{Name: "2", Length: 2, TLDList: "premium"},
+		},
+		TLDSettings: make(map[string]TLDSetting),
+		Notify:      DefaultNotify(),
+		Enrich:      DefaultEnrich(),
+		Server:      DefaultServer(),
+	}
+}
+
+// Load returns the configuration every command and the server start from.
+// Each layer overrides the one before: the DefaultScan settings, then the
+// YAML file at path, if any, then the environment variables ApplyEnv
+// reads. Command-line flags override the result. Fields the file leaves
+// out keep their defaults, except scans which are replaced when given.
+//
+// The config is validated, but without notifiers or output files: only
+// scans need somewhere to report to, and their flags can add one.
+func Load(path string) (*Scan, error) {
+	cfg := DefaultScan()
+	source := "environment"
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		defaultScans := cfg.Scans
+		cfg.Scans = nil
+		if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), cfg); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		if len(cfg.Scans) == 0 {
+			cfg.Scans = defaultScans
+		}
+		cfg.Concurrency.expand()
+		source = path
+	}
+
+	if err := cfg.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	cfg.normalize()
+	if err := cfg.Validate(); err != nil && !errors.Is(err, ErrNoOutput) {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return cfg, nil
+}
+
+// normalize puts the settings read from the file and environment in the
+// form the rest of the config expects: tld_settings keyed by lowercase TLDs
+// without the dot, and unnamed scans and escalation rules named
+func (c *Scan) normalize() {
+	settings := make(map[string]TLDSetting, len(c.TLDSettings))
+	for tld, set := range c.TLDSettings {
+		settings[strings.TrimPrefix(strings.ToLower(strings.TrimSpace(tld)), ".")] = set
+	}
+	c.TLDSettings = settings
+	for i := range c.Scans {
+		if s := &c.Scans[i]; s.Name == "" {
+			s.Name = s.defaultName()
+		}
+	}
+	c.Notify.TLDs = normalizeTLDs(c.Notify.TLDs)
+	for i := range c.Notify.Escalate {
+		if r := &c.Notify.Escalate[i]; r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+	}
+}
+
+// defaultName is the name of a scan that isn't given one: its length and
+// prefix, then any charset but alnum
+func (s ScanSpec) defaultName() string {
+	name := strconv.Itoa(s.Length) + s.Prefix
+	if s.Charset != "" && s.Charset != "alnum" {
+		name += "-" + s.Charset
+	}
+	return name
+}
+
+// Validate checks the config for mistakes that would only surface mid-run.
+// It changes nothing; Load and ApplyScope normalize what they set.
+func (c *Scan) Validate() error {
+	for tld, set := range c.TLDSettings {
+		if set.Priority < 0 {
+			return fmt.Errorf("tld_settings: %s: priority must be positive", tld)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, s := range c.Scans {
+		if s.Length < 1 || s.Length > checker.MaxCandidateLength {
+			return fmt.Errorf("scan %d: length must be 1 to %d", i+1, checker.MaxCandidateLength)
+		}
+		if len(s.Prefix) > s.Length {
+			return fmt.Errorf("scan %d: prefix longer than length", i+1)
+		}
+		if s.Charset != "" && checker.Charsets[s.Charset] == "" {
+			return fmt.Errorf("scan %d: unknown charset %q", i+1, s.Charset)
+		}
+		name := cmp.Or(s.Name, s.defaultName())
+		if names[name] {
+			return fmt.Errorf("duplicate scan name %q", name)
+		}
+		names[name] = true
+		if _, err := c.TLDsFor(s); err != nil {
+			return fmt.Errorf("scan %q: %w", name, err)
+		}
+	}
+
+	switch c.Notify.SMTP.TLS {
+	case "", notify.TLSStartTLS, notify.TLSImplicit, notify.TLSNone:
+	default:
+		return fmt.Errorf("smtp: unknown tls mode %q", c.Notify.SMTP.TLS)
+	}
+	for _, day := range []string{c.RecapWeekday, c.DigestWeekday} {
+		if day != "" && !isWeekday(day) {
+			return fmt.Errorf("unknown weekday %q", day)
+		}
+	}
+	if c.RecapWeekday != "" && c.DigestWeekday != "" {
+		return errors.New("recap_weekday and digest_weekday are mutually exclusive")
+	}
+	if c.Notify.MinDomains < 0 {
+		return errors.New("notify.min_domains must not be negative")
+	}
+	enabled := c.Notify.enabled()
+	for _, name := range c.Notify.Fallback {
+		if !slices.ContainsFunc(enabled, func(n notify.Notifier) bool { return n.Name() == name }) {
+			return fmt.Errorf("notify.fallback: %q is not a configured notifier", name)
+		}
+	}
+	for i, r := range c.Notify.Escalate {
+		name := cmp.Or(r.Name, fmt.Sprintf("rule %d", i+1))
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("notify.escalate: %s: %w", name, err)
+		}
+		if r.MinLength < 0 || r.MaxLength < 0 || r.MinScore < 0 {
+			return fmt.Errorf("notify.escalate: %s: values must not be negative", name)
+		}
+		if len(c.Notify.Channels(r.Channels)) == 0 {
+			return fmt.Errorf("notify.escalate: %s: none of %v is a configured notifier", name, r.Channels)
+		}
+	}
+	if err := c.Server.Validate(); err != nil {
+		return err
+	}
+	if err := c.Launches.validate(); err != nil {
+		return err
+	}
+	if c.ChunkSize < 0 {
+		return errors.New("chunk_size must not be negative")
+	}
+	if c.Budget.MaxWHOIS < 0 || c.Budget.MaxDuration < 0 {
+		return errors.New("budget: values must not be negative")
+	}
+	if c.Stealth.Window < 0 {
+		return errors.New("stealth.window must not be negative")
+	}
+	if c.Stealth.Jitter < 0 || c.Stealth.Jitter > 1 {
+		return errors.New("stealth.jitter must be from 0 to 1")
+	}
+	if err := c.Concurrency.Validate(); err != nil {
+		return err
+	}
+	if _, err := schedule.Parse(c.Schedule); err != nil {
+		return err
+	}
+	for _, r := range c.Notify.Registrars {
+		if r.Name == "" || !strings.Contains(r.URL, "{domain}") {
+			return fmt.Errorf("notify.registrars: %q needs a name and a url containing {domain}", r.Name)
+		}
+	}
+	if _, err := notify.ParseTemplate(c.Notify.EmailTemplate); err != nil {
+		return fmt.Errorf("email template: %w", err)
+	}
+
+	if c.Output.Format != "" && !export.Valid(c.Output.Format) {
+		return fmt.Errorf("output: unknown format %q", c.Output.Format)
+	}
+
+	if len(c.Notifiers()) == 0 && c.Output.File == "" && c.Output.Results == "" {
+		return ErrNoOutput
+	}
+	return nil
+}
+
+// Scope overrides the scan scope from the command line. Zero values leave
+// the configured scans alone.
+type Scope struct {
+	Lengths []int
+	Prefix  string
+	TLDs    []string
+	TLDList string
+	Charset string
+}
+
+// ApplyScope narrows or replaces the configured scans. Given lengths, the
+// scans are replaced by one per length; otherwise prefix, TLDs or a TLD
+// list, and charset override every configured scan. Call Validate
+// afterwards.
+func (c *Scan) ApplyScope(sc Scope) {
+	if len(sc.Lengths) > 0 {
+		c.Scans = make([]ScanSpec, len(sc.Lengths))
+		for i, l := range sc.Lengths {
+			c.Scans[i] = ScanSpec{Length: l}
+		}
+	}
+
+	override := sc.Prefix != "" || len(sc.TLDs) > 0 || sc.TLDList != "" || sc.Charset != ""
+	for i := range c.Scans {
+		s := &c.Scans[i]
+		if override {
+			// The name keyed the old scope's checkpoint; derive a new one
+			s.Name = ""
+		}
+		if sc.Prefix != "" {
+			s.Prefix = sc.Prefix
+		}
+		if len(sc.TLDs) > 0 {
+			s.TLDs = sc.TLDs
+		}
+		if sc.TLDList != "" {
+			s.TLDs, s.TLDList = nil, sc.TLDList
+		}
+		if sc.Charset != "" {
+			s.Charset = sc.Charset
+		}
+	}
+	c.normalize()
+}
+
+// Candidates returns the domains a scan spec covers, higher-priority TLDs
+// first, generated as the scan reaches them
+func (c *Scan) Candidates(s ScanSpec) ([]checker.Candidates, error) {
+	tiers, err := c.TLDTiers(s)
+	if err != nil {
+		return nil, err
+	}
+	cands := make([]checker.Candidates, len(tiers))
+	for i, tier := range tiers {
+		cands[i] = checker.Candidates{Length: s.Length, Prefix: s.Prefix, Chars: s.Chars(), TLDs: tier}
+	}
+	return cands, nil
+}
+
+// TLDsFor resolves the TLDs a scan spec covers, leaving out disabled
+// TLDs and ordering the rest by priority
+func (c *Scan) TLDsFor(s ScanSpec) ([]string, error) {
+	tiers, err := c.TLDTiers(s)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(tiers...), nil
+}
+
+// TLDTiers resolves a scan spec's enabled TLDs grouped by priority, highest
+// priority first. TLDs within a tier keep their list order.
+func (c *Scan) TLDTiers(s ScanSpec) ([][]string, error) {
+	list, err := c.tldList(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var enabled []string
+	priority := make(map[string]int)
+	for _, tld := range list {
+		set := c.TLDSettings[tld]
+		if set.Enabled != nil && !*set.Enabled {
+			continue
+		}
+		enabled = append(enabled, tld)
+		priority[tld] = set.Priority
+	}
+	if len(enabled) == 0 {
+		return nil, errors.New("every TLD is disabled")
+	}
+	return checker.ByPriority(enabled, priority), nil
+}
+
+func (c *Scan) tldList(s ScanSpec) ([]string, error) {
+	if len(s.TLDs) > 0 {
+		return normalizeTLDs(s.TLDs), nil
+	}
+
+	name := s.TLDList
+	if name == "" {
+		name = "premium"
+	}
+	if list, ok := c.TLDLists[name]; ok {
+		return normalizeTLDs(list), nil
+	}
+	if list, ok := BuiltinTLDLists[name]; ok {
+		return list, nil
+	}
+	return nil, fmt.Errorf("unknown tld list %q", name)
+}
+
+// BuiltinTLDLists are the TLD lists available without configuration
+var BuiltinTLDLists = map[string][]string{
+	"premium": checker.PremiumTLDs,
+	"common":  checker.CommonTLDs,
+}
+
+// Checker builds a checker with the configured concurrency, rate and
+// stealth settings
+func (c *Scan) Checker() *checker.Checker {
+	opts := append(c.Concurrency.Options(), checker.WithPremiumPricers(DefaultAutoBuy().PremiumPricers()...))
+	if c.Stealth.Window > 0 {
+		// The scan runner sets the pace itself as the run goes
+		opts = append(opts, checker.WithPace(0, cmp.Or(c.Stealth.Jitter, DefaultJitter)))
+	}
+	return checker.New(opts...)
+}
+
+// isWeekday reports whether day names a weekday, e.g. "Monday"
+func isWeekday(day string) bool {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), day) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeTLDs(tlds []string) []string {
+	out := make([]string, 0, len(tlds))
+	for _, t := range tlds {
+		t = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(t)), ".")
+		if t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// Server configures domainhunter serve
+type Server struct {
+	Port string `yaml:"port"`
+	// AdminToken is the basic-auth password for the /admin pages
+	AdminToken string `yaml:"admin_token"`
+	// RedisURL is a cache shared by several instances
+	RedisURL string `yaml:"redis_url"`
+	// DebugAddr serves pprof and expvar on a localhost address
+	DebugAddr string `yaml:"debug_addr"`
+	// WatchInterval is how often watched domains are re-checked, unless
+	// they set their own
+	WatchInterval time.Duration `yaml:"watch_interval"`
+	// AlertRemind is how long before an unacknowledged, unchanged alert
+	// is repeated; 0 never repeats it
+	AlertRemind time.Duration `yaml:"alert_remind"`
+	// CTKeywords are brand keywords to watch Certificate Transparency logs
+	// for
+	CTKeywords []string `yaml:"ct_keywords"`
+
+	// TLSCert and TLSKey are PEM files to serve HTTPS with
+	TLSCert string `yaml:"tls_cert"`
+	TLSKey  string `yaml:"tls_key"`
+	// AutocertDomains are host names to get Let's Encrypt certificates
+	// for, instead of TLSCert and TLSKey
+	AutocertDomains []string `yaml:"autocert_domains"`
+	// AutocertCache is the directory certificates and the ACME account key
+	// are kept in
+	AutocertCache string `yaml:"autocert_cache"`
+
+	// TrustedProxy are the addresses or CIDR ranges of reverse proxies
+	// whose Fly-Client-IP or X-Forwarded-For header names the client. On
+	// Fly (FLY_APP_NAME set) Fly's proxy is trusted as well.
+	TrustedProxy []string `yaml:"trusted_proxy"`
+}
+
+// TrustedProxies parses TrustedProxy, a bare address being a range of one
+func (s Server) TrustedProxies() ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, p := range s.TrustedProxy {
+		if !strings.Contains(p, "/") {
+			addr, err := netip.ParseAddr(p)
+			if err != nil {
+				return nil, fmt.Errorf("server.trusted_proxy: %w", err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("server.trusted_proxy: %w", err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// DefaultServer returns the built-in server settings: plain HTTP on port
+// 8080, watched domains re-checked hourly and alerts repeated daily
+func DefaultServer() Server {
+	return Server{
+		Port:          "8080",
+		WatchInterval: time.Hour,
+		AlertRemind:   24 * time.Hour,
+		AutocertCache: "autocert",
+	}
+}
+
+// TLS reports whether the server serves HTTPS
+func (s Server) TLS() bool {
+	return s.TLSCert != "" || len(s.AutocertDomains) > 0
+}
+
+// Validate checks the server settings
+func (s Server) Validate() error {
+	if s.Port == "" {
+		return errors.New("server.port must not be empty")
+	}
+	if s.WatchInterval <= 0 {
+		return errors.New("server.watch_interval must be positive")
+	}
+	if s.AlertRemind < 0 {
+		return errors.New("server.alert_remind must not be negative")
+	}
+	if (s.TLSCert == "") != (s.TLSKey == "") {
+		return errors.New("server.tls_cert and server.tls_key go together")
+	}
+	if s.TLSCert != "" && len(s.AutocertDomains) > 0 {
+		return errors.New("server.autocert_domains can't be combined with server.tls_cert")
+	}
+	if len(s.AutocertDomains) > 0 && s.AutocertCache == "" {
+		return errors.New("server.autocert_cache must not be empty")
+	}
+	if _, err := s.TrustedProxies(); err != nil {
+		return err
+	}
+	return nil
+}
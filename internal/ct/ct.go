@@ -0,0 +1,102 @@
+// Package ct searches Certificate Transparency logs, through crt.sh, for
+// certificates issued to names containing a keyword.
+package ct
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Cert is a logged certificate
+type Cert struct {
+	// ID is crt.sh's id, which grows as certificates are logged
+	ID        int64
+	Issuer    string
+	Names     []string
+	NotBefore time.Time
+}
+
+// crt.sh answers big queries slowly
+var client = &http.Client{Timeout: time.Minute}
+
+// timeLayout is how crt.sh formats times, in UTC without a zone
+const timeLayout = "2006-01-02T15:04:05"
+
+// Search returns unexpired certificates logged after ID since, oldest
+// first, with a name containing keyword
+func Search(ctx context.Context, keyword string, since int64) ([]Cert, error) {
+	q := url.Values{
+		"q":           {"%" + keyword + "%"},
+		"output":      {"json"},
+		"exclude":     {"expired"},
+		"deduplicate": {"Y"},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://crt.sh/?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crt.sh returned status %d", resp.StatusCode)
+	}
+
+	var entries []struct {
+		ID        int64  `json:"id"`
+		Issuer    string `json:"issuer_name"`
+		NameValue string `json:"name_value"`
+		NotBefore string `json:"not_before"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	var certs []Cert
+	for _, e := range entries {
+		if e.ID <= since {
+			continue
+		}
+		notBefore, _ := time.Parse(timeLayout, e.NotBefore)
+		certs = append(certs, Cert{
+			ID:        e.ID,
+			Issuer:    issuerOrg(e.Issuer),
+			Names:     names(e.NameValue, keyword),
+			NotBefore: notBefore,
+		})
+	}
+	slices.SortFunc(certs, func(a, b Cert) int { return cmp.Compare(a.ID, b.ID) })
+	return certs, nil
+}
+
+// names splits a certificate's newline-separated names, keeping the ones
+// containing keyword once each
+func names(value, keyword string) []string {
+	var out []string
+	for _, n := range strings.Fields(strings.ToLower(value)) {
+		if strings.Contains(n, keyword) && !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// issuerOrg picks the organization out of an issuer's distinguished name,
+// e.g. "Let's Encrypt" from "C=US, O=Let's Encrypt, CN=R3"
+func issuerOrg(dn string) string {
+	for _, part := range strings.Split(dn, ", ") {
+		if org, ok := strings.CutPrefix(part, "O="); ok {
+			return strings.Trim(org, `"`)
+		}
+	}
+	return dn
+}
@@ -0,0 +1,71 @@
+// Package debug serves pprof profiles and expvar metrics on a local
+// address, for looking into a running scan or server.
+package debug
+
+import (
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"sync"
+	"sync/atomic"
+
+	"github.com/berckan/domainhunter/internal/checker"
+)
+
+// Serve serves /debug/pprof/ and /debug/vars on addr in the background and
+// returns the address it listens on. Profiles expose the process's
+// internals, so addr must be on localhost; a bare ":port" listens on
+// 127.0.0.1.
+func Serve(addr string) (net.Addr, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("debug address: %w", err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("debug address %s: must be on localhost", addr)
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	go func() {
+		if err := http.Serve(ln, mux); err != nil {
+			slog.Error("debug server", "err", err)
+		}
+	}()
+	return ln.Addr(), nil
+}
+
+var (
+	watched atomic.Pointer[checker.Checker]
+	publish sync.Once
+)
+
+// Watch publishes c's DNS cache and worker stats as the "checker" expvar,
+// replacing any checker watched before
+func Watch(c *checker.Checker) {
+	watched.Store(c)
+	publish.Do(func() {
+		expvar.Publish("checker", expvar.Func(func() any {
+			c := watched.Load()
+			return map[string]any{
+				"dns_cache": c.DNSCacheStats(),
+				"workers":   c.WorkerStats(),
+			}
+		}))
+	})
+}
@@ -0,0 +1,124 @@
+// Package drop follows taken domains through redemption and pending delete,
+// estimating when each becomes registrable so it can be re-checked closely
+// around that time.
+package drop
+
+import (
+	"context"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// Typical gTLD deletion timeline. Registrars usually hold an expired
+// domain through the auto-renew grace period before deleting it.
+const (
+	autoRenewGrace      = 45 * 24 * time.Hour
+	redemptionPeriod    = 30 * 24 * time.Hour
+	pendingDeletePeriod = 5 * 24 * time.Hour
+)
+
+// Re-check intervals by distance to the estimated drop
+const (
+	// dropWindow around the estimate is checked every intenseInterval
+	dropWindow      = 6 * time.Hour
+	intenseInterval = 2 * time.Minute
+	// approachWindow before the window is checked every approachInterval
+	approachWindow   = 48 * time.Hour
+	approachInterval = 30 * time.Minute
+	// overdueInterval applies once the estimate has passed the window
+	overdueInterval = 15 * time.Minute
+)
+
+// Estimate returns when a domain that entered phase at entered should drop:
+// the end of pending delete, after redemption if it's still there. entered
+// is the WHOIS updated date; if it is unknown the phase is assumed to have
+// just begun. An estimate in the past means the drop is imminent, so now is
+// returned.
+func Estimate(phase string, entered, now time.Time) time.Time {
+	if entered.IsZero() || entered.After(now) {
+		entered = now
+	}
+	var at time.Time
+	switch phase {
+	case models.PhasePendingDelete:
+		at = entered.Add(pendingDeletePeriod)
+	case models.PhaseRedemption:
+		at = entered.Add(redemptionPeriod + pendingDeletePeriod)
+	default:
+		return time.Time{}
+	}
+	if at.Before(now) {
+		return now
+	}
+	return at
+}
+
+// Plan schedules a new watch on a taken domain from its WHOIS record. A
+// domain already being deleted is tracked to its estimated drop. One with a
+// known expiry can't drop before it expires, so it isn't checked until
+// then, and is expected to drop after the grace and redemption periods if
+// nobody renews it.
+func Plan(w *models.WatchedDomain, rec models.WhoisRecord, now time.Time) {
+	w.Whois, w.WhoisAt = rec, now
+	w.ExpiresAt = rec.Expires
+	w.Phase = rec.DropPhase()
+	switch {
+	case w.Phase != "":
+		w.DropAt = Estimate(w.Phase, rec.Updated, now)
+		w.NextCheckAt = now
+	case rec.Expires.After(now):
+		w.DropAt = rec.Expires.Add(autoRenewGrace + redemptionPeriod + pendingDeletePeriod)
+		w.NextCheckAt = rec.Expires
+	default:
+		w.NextCheckAt = now
+	}
+}
+
+// RecheckAfter is how long to wait before checking a domain expected to
+// drop at dropAt again: minutes around the drop, normal far from it or when
+// no drop is expected
+func RecheckAfter(dropAt, now time.Time, normal time.Duration) time.Duration {
+	if dropAt.IsZero() {
+		return normal
+	}
+	until := dropAt.Sub(now)
+	switch {
+	case until.Abs() <= dropWindow:
+		return min(normal, intenseInterval)
+	case until < 0:
+		return min(normal, overdueInterval)
+	case until <= approachWindow:
+		return min(normal, approachInterval)
+	}
+	return normal
+}
+
+// Track adds the results in a drop phase to the watch list so the watcher
+// follows them to the drop, skipping domains already watched. It returns
+// how many were added.
+func Track(ctx context.Context, store storage.Store, results []models.DomainResult) (int, error) {
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return 0, err
+	}
+	watched := make(map[string]bool, len(watches))
+	for _, w := range watches {
+		watched[w.Domain] = true
+	}
+
+	added := 0
+	for _, r := range results {
+		if r.Phase == "" || watched[r.Domain] {
+			continue
+		}
+		w := models.WatchedDomain{Domain: r.Domain, Status: r.Status, Phase: r.Phase}
+		if err := store.AddWatch(ctx, &w); err != nil {
+			return added, err
+		}
+		watched[r.Domain] = true
+		added++
+	}
+	return added, nil
+}
@@ -0,0 +1,97 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/score"
+)
+
+// Appraiser estimates what a domain would sell for, in US dollars
+type Appraiser interface {
+	Name() string
+	Appraise(ctx context.Context, r models.DomainResult) (float64, error)
+}
+
+// Appraisal attaches an estimated value to each available finding, from
+// the first appraiser that gives one. An appraiser that fails is left out
+// for the rest of the run, so the later ones act as fallbacks.
+type Appraisal struct {
+	Appraisers []Appraiser
+}
+
+// Name implements Enricher
+func (a *Appraisal) Name() string { return "appraisal" }
+
+// Enrich implements Enricher
+func (a *Appraisal) Enrich(ctx context.Context, results []models.DomainResult) error {
+	var errs []error
+	failed := make(map[string]bool)
+	for i, r := range results {
+		if r.Status != models.StatusAvailable {
+			continue
+		}
+		for _, ap := range a.Appraisers {
+			if failed[ap.Name()] {
+				continue
+			}
+			value, err := ap.Appraise(ctx, r)
+			if err != nil {
+				failed[ap.Name()] = true
+				errs = append(errs, fmt.Errorf("%s: %w", ap.Name(), err))
+				continue
+			}
+			of(&results[i]).Appraisal = &models.Appraisal{Source: ap.Name(), Value: value}
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GoDaddy appraises domains through the GoDaddy appraisal API
+type GoDaddy struct {
+	APIKey    string
+	APISecret string
+}
+
+// Name implements Appraiser
+func (GoDaddy) Name() string { return "GoDaddy" }
+
+// Appraise implements Appraiser
+func (g GoDaddy) Appraise(ctx context.Context, r models.DomainResult) (float64, error) {
+	header := http.Header{"Authorization": {"sso-key " + g.APIKey + ":" + g.APISecret}}
+	var out struct {
+		GoValue float64 `json:"govalue"`
+	}
+	if err := getJSON(ctx, "https://api.godaddy.com/v1/appraisal/"+url.PathEscape(r.Domain), header, &out); err != nil {
+		return 0, err
+	}
+	return out.GoValue, nil
+}
+
+// Heuristic is the built-in appraisal model. Every 10 points of
+// score.Score double a $10 base, and link authority found by SEO raises
+// the estimate by up to 5x. It never fails, so it makes a good last
+// resort.
+type Heuristic struct{}
+
+// Name implements Appraiser
+func (Heuristic) Name() string { return "estimate" }
+
+// Appraise implements Appraiser
+func (Heuristic) Appraise(_ context.Context, r models.DomainResult) (float64, error) {
+	value := 10 * math.Pow(2, float64(score.Score(r.Domain))/10)
+	if e := r.Enrichment; e != nil {
+		authority := 0.0
+		for _, m := range e.SEO {
+			authority = max(authority, m.Authority)
+		}
+		value *= 1 + authority/25
+	}
+	return math.Round(value/10) * 10, nil
+}
@@ -0,0 +1,52 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// Cache keeps paid lookups between runs. cache.Cache satisfies it.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// errMiss is StoreCache's miss; callers only care that Get failed
+var errMiss = errors.New("enrich: cache miss")
+
+// StoreCache keeps cached lookups as settings in the database, so they
+// outlive the one-shot daily scan
+type StoreCache struct {
+	Store storage.Store
+}
+
+type storeEntry struct {
+	Value   []byte    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// Get implements Cache
+func (c StoreCache) Get(ctx context.Context, key string) ([]byte, error) {
+	raw, err := c.Store.GetSetting(ctx, "enrich/"+key)
+	if err != nil || raw == "" {
+		return nil, errMiss
+	}
+	var e storeEntry
+	if err := json.Unmarshal([]byte(raw), &e); err != nil || time.Now().After(e.Expires) {
+		return nil, errMiss
+	}
+	return e.Value, nil
+}
+
+// Set implements Cache
+func (c StoreCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	raw, err := json.Marshal(storeEntry{Value: value, Expires: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+	return c.Store.SetSetting(ctx, "enrich/"+key, string(raw))
+}
@@ -0,0 +1,81 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// DefaultBlocklists are domain blocklists that are free for low-volume use
+var DefaultBlocklists = []string{"dbl.spamhaus.org", "multi.surbl.org", "multi.uribl.com"}
+
+// DNSBL flags findings listed on domain blocklists (Zones), so a domain
+// with a poisoned reputation isn't bought by mistake. Domains Prior found
+// fresh have no reputation to check and are skipped.
+//
+// Blocklists refuse queries that come through big public resolvers, so
+// these go through the system resolver rather than the checker's.
+type DNSBL struct {
+	Zones []string
+}
+
+// Name implements Enricher
+func (d *DNSBL) Name() string { return "dnsbl" }
+
+// Enrich implements Enricher. A zone that refuses a query is left out for
+// the rest of the run.
+func (d *DNSBL) Enrich(ctx context.Context, results []models.DomainResult) error {
+	var errs []error
+	refused := make(map[string]bool)
+	for i, r := range results {
+		if e := r.Enrichment; e != nil && e.Prior != nil && e.Prior.Class == models.PriorFresh {
+			continue
+		}
+		var listed []string
+		for _, zone := range d.Zones {
+			if refused[zone] {
+				continue
+			}
+			ok, err := lookupDNSBL(ctx, r.Domain, zone)
+			if err != nil {
+				refused[zone] = true
+				errs = append(errs, err)
+				continue
+			}
+			if ok {
+				listed = append(listed, zone)
+			}
+		}
+		if listed != nil {
+			of(&results[i]).Blocklists = listed
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookupDNSBL reports whether zone lists domain. Listings answer with a
+// 127.0.0.0/8 address; 127.0.0.1 (URIBL) and 127.255.255.x (Spamhaus)
+// mean the query itself was refused.
+func lookupDNSBL(ctx context.Context, domain, zone string) (bool, error) {
+	addrs, err := net.DefaultResolver.LookupHost(ctx, domain+"."+zone)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", zone, err)
+	}
+	for _, a := range addrs {
+		if a == "127.0.0.1" || strings.HasPrefix(a, "127.255.255.") {
+			return false, fmt.Errorf("%s refused the query (%s)", zone, a)
+		}
+		if strings.HasPrefix(a, "127.") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,75 @@
+// Package enrich annotates findings with information from outside sources,
+// such as trademark offices, before they're reported.
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Enricher adds information to results in place
+type Enricher interface {
+	Name() string
+	Enrich(ctx context.Context, results []models.DomainResult) error
+}
+
+// Run applies each enricher in turn. A failing enricher doesn't stop the
+// others: results keep whatever could be gathered, and the failures are
+// returned together.
+func Run(ctx context.Context, enrichers []Enricher, results []models.DomainResult) error {
+	var errs []error
+	for _, e := range enrichers {
+		if err := e.Enrich(ctx, results); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// of returns r's enrichment, adding one if it has none yet
+func of(r *models.DomainResult) *models.Enrichment {
+	if r.Enrichment == nil {
+		r.Enrichment = &models.Enrichment{}
+	}
+	return r.Enrichment
+}
+
+// byLabel groups result indexes by second-level label, e.g. "foo" for both
+// foo.io and foo.dev, so lookups by name happen once per name
+func byLabel(results []models.DomainResult) map[string][]int {
+	labels := make(map[string][]int)
+	for i, r := range results {
+		label, _, _ := strings.Cut(r.Domain, ".")
+		labels[label] = append(labels[label], i)
+	}
+	return labels
+}
+
+var client = &http.Client{Timeout: 15 * time.Second}
+
+// getJSON GETs url with header and decodes the JSON response into out
+func getJSON(ctx context.Context, url string, header http.Header, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
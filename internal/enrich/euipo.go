@@ -0,0 +1,143 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+const (
+	euipoTokenURL  = "https://euipo.europa.eu/cas-server-webapp/oidc/accessToken"
+	euipoSearchAPI = "https://api.euipo.europa.eu/trademark-search/trademarks"
+)
+
+// euipoDead are statuses of marks that no longer protect anything
+var euipoDead = []string{"EXPIRED", "WITHDRAWN", "REFUSED", "CANCELLED", "SURRENDERED"}
+
+// EUIPO searches the EU trademark register through the EUIPO Trademark
+// Search API, which needs client credentials from the EUIPO developer
+// portal
+type EUIPO struct {
+	ClientID     string
+	ClientSecret string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Name implements TrademarkOffice
+func (e *EUIPO) Name() string { return "EUIPO" }
+
+// Search implements TrademarkOffice
+func (e *EUIPO) Search(ctx context.Context, mark string) ([]models.Trademark, error) {
+	token, err := e.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{
+		"query": {fmt.Sprintf(`wordMarkSpecification.verbalElement==%q`, mark)},
+		"size":  {"25"},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", euipoSearchAPI+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-IBM-Client-Id", e.ClientID)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("euipo returned status %d", resp.StatusCode)
+	}
+
+	var found struct {
+		Trademarks []struct {
+			ApplicationNumber string `json:"applicationNumber"`
+			Status            string `json:"status"`
+			WordMark          struct {
+				VerbalElement string `json:"verbalElement"`
+			} `json:"wordMarkSpecification"`
+			Applicants []struct {
+				Name string `json:"name"`
+			} `json:"applicants"`
+		} `json:"trademarks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		return nil, err
+	}
+
+	var marks []models.Trademark
+	for _, t := range found.Trademarks {
+		if slices.Contains(euipoDead, t.Status) {
+			continue
+		}
+		var owners []string
+		for _, a := range t.Applicants {
+			owners = append(owners, a.Name)
+		}
+		marks = append(marks, models.Trademark{
+			Office: "EUIPO",
+			Mark:   t.WordMark.VerbalElement,
+			Number: t.ApplicationNumber,
+			Owner:  strings.Join(owners, ", "),
+			Status: strings.ToLower(t.Status),
+		})
+	}
+	return marks, nil
+}
+
+// accessToken returns a cached OAuth token, fetching a new one when it's
+// about to expire
+func (e *EUIPO) accessToken(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != "" && time.Until(e.expires) > time.Minute {
+		return e.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {e.ClientID},
+		"client_secret": {e.ClientSecret},
+		"scope":         {"uid"},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", euipoTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("euipo token returned status %d", resp.StatusCode)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", err
+	}
+	e.token = tok.AccessToken
+	e.expires = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return e.token, nil
+}
@@ -0,0 +1,37 @@
+package enrich
+
+import (
+	"context"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/social"
+)
+
+// Handles checks whether each available finding's name is free as a
+// username on social sites, once per name. Sites that don't answer leave
+// the handle unknown rather than failing the enricher.
+type Handles struct {
+	Sites []social.Site
+}
+
+// Name implements Enricher
+func (h *Handles) Name() string { return "handles" }
+
+// Enrich implements Enricher
+func (h *Handles) Enrich(ctx context.Context, results []models.DomainResult) error {
+	checked := make(map[string][]models.Handle)
+	for i, r := range results {
+		if r.Status != models.StatusAvailable {
+			continue
+		}
+		label, _, _ := strings.Cut(r.Domain, ".")
+		handles, ok := checked[label]
+		if !ok {
+			handles = social.Check(ctx, h.Sites, label)
+			checked[label] = handles
+		}
+		of(&results[i]).Handles = handles
+	}
+	return nil
+}
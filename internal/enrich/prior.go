@@ -0,0 +1,135 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// PriorSource is one kind of evidence that a domain was registered before
+type PriorSource interface {
+	Name() string
+	// Seen reports whether the domain was ever seen registered or in use
+	// and, if the source knows, when last
+	Seen(ctx context.Context, r *models.DomainResult) (bool, time.Time, error)
+}
+
+// Prior classifies each finding as fresh, never registered, or dropped,
+// registered before, from whatever Sources can tell. A source that fails
+// is left out for the rest of the run; a finding no source could answer
+// for stays unclassified.
+type Prior struct {
+	Sources []PriorSource
+}
+
+// Name implements Enricher
+func (p *Prior) Name() string { return "prior" }
+
+// Enrich implements Enricher
+func (p *Prior) Enrich(ctx context.Context, results []models.DomainResult) error {
+	var errs []error
+	failed := make(map[string]bool)
+	for i := range results {
+		prior := models.Prior{Class: models.PriorFresh}
+		answered := false
+		for _, src := range p.Sources {
+			if failed[src.Name()] {
+				continue
+			}
+			seen, last, err := src.Seen(ctx, &results[i])
+			if err != nil {
+				failed[src.Name()] = true
+				errs = append(errs, fmt.Errorf("%s: %w", src.Name(), err))
+				continue
+			}
+			answered = true
+			if seen {
+				prior.Class = models.PriorDropped
+				prior.Evidence = append(prior.Evidence, src.Name())
+				if last.After(prior.LastSeen) {
+					prior.LastSeen = last
+				}
+			}
+		}
+		if answered {
+			of(&results[i]).Prior = &prior
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WaybackSource counts archived content as evidence, reusing the Wayback
+// enrichment when it already ran
+type WaybackSource struct{}
+
+// Name implements PriorSource
+func (WaybackSource) Name() string { return "wayback" }
+
+// Seen implements PriorSource
+func (WaybackSource) Seen(ctx context.Context, r *models.DomainResult) (bool, time.Time, error) {
+	if r.Enrichment == nil || r.Enrichment.History == nil {
+		h, err := Wayback{}.history(ctx, r.Domain)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		of(r).History = &h
+	}
+	h := r.Enrichment.History
+	return h.Months > 0, h.Last, nil
+}
+
+// SecurityTrails counts passive DNS history, A records ever observed for
+// the domain, as evidence
+type SecurityTrails struct {
+	APIKey string
+}
+
+// Name implements PriorSource
+func (SecurityTrails) Name() string { return "passive DNS" }
+
+// Seen implements PriorSource
+func (s SecurityTrails) Seen(ctx context.Context, r *models.DomainResult) (bool, time.Time, error) {
+	var body struct {
+		Records []struct {
+			LastSeen string `json:"last_seen"`
+		} `json:"records"`
+	}
+	err := getJSON(ctx, "https://api.securitytrails.com/v1/history/"+url.PathEscape(r.Domain)+"/dns/a",
+		http.Header{"APIKEY": {s.APIKey}}, &body)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	var last time.Time
+	for _, rec := range body.Records {
+		if t, err := time.Parse(time.DateOnly, rec.LastSeen); err == nil && t.After(last) {
+			last = t
+		}
+	}
+	return len(body.Records) > 0, last, nil
+}
+
+// WhoisXMLHistory counts historical WHOIS records as evidence. Preview
+// mode only says how many there are, so it gives no date.
+type WhoisXMLHistory struct {
+	APIKey string
+}
+
+// Name implements PriorSource
+func (WhoisXMLHistory) Name() string { return "WHOIS history" }
+
+// Seen implements PriorSource
+func (w WhoisXMLHistory) Seen(ctx context.Context, r *models.DomainResult) (bool, time.Time, error) {
+	q := url.Values{"apiKey": {w.APIKey}, "domainName": {r.Domain}, "mode": {"preview"}, "outputFormat": {"JSON"}}
+	var body struct {
+		RecordsCount int `json:"recordsCount"`
+	}
+	if err := getJSON(ctx, "https://whois-history.whoisxmlapi.com/api/v1?"+q.Encode(), nil, &body); err != nil {
+		return false, time.Time{}, err
+	}
+	return body.RecordsCount > 0, time.Time{}, nil
+}
@@ -0,0 +1,58 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/pricing"
+)
+
+// Renewals compares first-year and renewal prices for the findings' TLDs
+// across registrars, fetched once per run, so renewal traps are flagged
+// before anyone registers into one
+type Renewals struct {
+	Sources []pricing.Source
+}
+
+// Name implements Enricher
+func (r *Renewals) Name() string { return "renewals" }
+
+// Enrich implements Enricher
+func (r *Renewals) Enrich(ctx context.Context, results []models.DomainResult) error {
+	var tlds []string
+	for _, res := range results {
+		if tld := tldOf(res.Domain); !slices.Contains(tlds, tld) {
+			tlds = append(tlds, tld)
+		}
+	}
+
+	var errs []error
+	var prices []models.Price
+	for _, src := range r.Sources {
+		ps, err := src.Prices(ctx, tlds)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name(), err))
+		}
+		prices = append(prices, ps...)
+	}
+
+	byTLD := make(map[string][]models.Price)
+	for _, c := range pricing.Compare(prices) {
+		byTLD[c.TLD] = c.Prices
+	}
+	for i, res := range results {
+		if ps := byTLD[tldOf(res.Domain)]; len(ps) > 0 {
+			of(&results[i]).Prices = ps
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func tldOf(domain string) string {
+	_, tld, _ := strings.Cut(domain, ".")
+	return tld
+}
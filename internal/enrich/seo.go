@@ -0,0 +1,77 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// SEOProvider fetches link metrics for a domain
+type SEOProvider interface {
+	Name() string
+	Metrics(ctx context.Context, domain string) (models.SEOMetrics, error)
+}
+
+// SEO annotates findings with each provider's authority and backlink
+// counts. Metrics are cached for TTL, since the APIs are paid and change
+// slowly. Domains Prior found fresh have no links to count and are
+// skipped; a provider that fails is left out for the rest of the run.
+type SEO struct {
+	Providers []SEOProvider
+	Cache     Cache
+	TTL       time.Duration
+}
+
+// Name implements Enricher
+func (s *SEO) Name() string { return "seo" }
+
+// Enrich implements Enricher
+func (s *SEO) Enrich(ctx context.Context, results []models.DomainResult) error {
+	var errs []error
+	failed := make(map[string]bool)
+	for i, r := range results {
+		if e := r.Enrichment; e != nil && e.Prior != nil && e.Prior.Class == models.PriorFresh {
+			continue
+		}
+		for _, p := range s.Providers {
+			if failed[p.Name()] {
+				continue
+			}
+			m, err := s.metrics(ctx, p, r.Domain)
+			if err != nil {
+				failed[p.Name()] = true
+				errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+				continue
+			}
+			e := of(&results[i])
+			e.SEO = append(e.SEO, m)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// metrics returns p's metrics for domain from the cache, or fetches and
+// caches them
+func (s *SEO) metrics(ctx context.Context, p SEOProvider, domain string) (models.SEOMetrics, error) {
+	key := "seo/" + p.Name() + "/" + domain
+	var m models.SEOMetrics
+	if s.Cache != nil {
+		if data, err := s.Cache.Get(ctx, key); err == nil && json.Unmarshal(data, &m) == nil {
+			return m, nil
+		}
+	}
+	m, err := p.Metrics(ctx, domain)
+	if err != nil {
+		return m, err
+	}
+	if s.Cache != nil {
+		if data, err := json.Marshal(m); err == nil {
+			s.Cache.Set(ctx, key, data, s.TTL)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,156 @@
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Moz fetches Domain Authority through the Moz Links API
+type Moz struct {
+	AccessID  string
+	SecretKey string
+}
+
+// Name implements SEOProvider
+func (Moz) Name() string { return "Moz" }
+
+// Metrics implements SEOProvider
+func (m Moz) Metrics(ctx context.Context, domain string) (models.SEOMetrics, error) {
+	body, err := json.Marshal(map[string]any{"targets": []string{domain}})
+	if err != nil {
+		return models.SEOMetrics{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://lsapi.seomoz.com/v2/url_metrics", bytes.NewReader(body))
+	if err != nil {
+		return models.SEOMetrics{}, err
+	}
+	req.SetBasicAuth(m.AccessID, m.SecretKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return models.SEOMetrics{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return models.SEOMetrics{}, fmt.Errorf("moz returned status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Results []struct {
+			DomainAuthority float64 `json:"domain_authority"`
+			ExternalPages   int64   `json:"external_pages_to_root_domain"`
+			RootDomains     int64   `json:"root_domains_to_root_domain"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return models.SEOMetrics{}, err
+	}
+	if len(out.Results) == 0 {
+		return models.SEOMetrics{}, errors.New("moz: no result")
+	}
+	r := out.Results[0]
+	return models.SEOMetrics{
+		Source:     "Moz",
+		Metric:     "DA",
+		Authority:  r.DomainAuthority,
+		Backlinks:  r.ExternalPages,
+		RefDomains: r.RootDomains,
+	}, nil
+}
+
+// Ahrefs fetches Domain Rating and backlink counts through the Ahrefs API
+type Ahrefs struct {
+	APIKey string
+}
+
+// Name implements SEOProvider
+func (Ahrefs) Name() string { return "Ahrefs" }
+
+// Metrics implements SEOProvider
+func (a Ahrefs) Metrics(ctx context.Context, domain string) (models.SEOMetrics, error) {
+	header := http.Header{"Authorization": {"Bearer " + a.APIKey}}
+	q := url.Values{"target": {domain}, "date": {time.Now().Format(time.DateOnly)}, "mode": {"domain"}}
+
+	var rating struct {
+		DomainRating struct {
+			DomainRating float64 `json:"domain_rating"`
+		} `json:"domain_rating"`
+	}
+	if err := getJSON(ctx, "https://api.ahrefs.com/v3/site-explorer/domain-rating?"+q.Encode(), header, &rating); err != nil {
+		return models.SEOMetrics{}, err
+	}
+	var stats struct {
+		Metrics struct {
+			Live           int64 `json:"live"`
+			LiveRefdomains int64 `json:"live_refdomains"`
+		} `json:"metrics"`
+	}
+	if err := getJSON(ctx, "https://api.ahrefs.com/v3/site-explorer/backlinks-stats?"+q.Encode(), header, &stats); err != nil {
+		return models.SEOMetrics{}, err
+	}
+	return models.SEOMetrics{
+		Source:     "Ahrefs",
+		Metric:     "DR",
+		Authority:  rating.DomainRating.DomainRating,
+		Backlinks:  stats.Metrics.Live,
+		RefDomains: stats.Metrics.LiveRefdomains,
+	}, nil
+}
+
+// Majestic fetches Trust Flow and backlink counts through the Majestic API
+type Majestic struct {
+	APIKey string
+}
+
+// Name implements SEOProvider
+func (Majestic) Name() string { return "Majestic" }
+
+// Metrics implements SEOProvider
+func (m Majestic) Metrics(ctx context.Context, domain string) (models.SEOMetrics, error) {
+	q := url.Values{
+		"app_api_key": {m.APIKey},
+		"cmd":         {"GetIndexItemInfo"},
+		"items":       {"1"},
+		"item0":       {domain},
+		"datasource":  {"fresh"},
+	}
+	var out struct {
+		Code         string
+		ErrorMessage string
+		DataTables   struct {
+			Results struct {
+				Data []struct {
+					TrustFlow    float64
+					ExtBackLinks int64
+					RefDomains   int64
+				}
+			}
+		}
+	}
+	if err := getJSON(ctx, "https://api.majestic.com/api/json?"+q.Encode(), nil, &out); err != nil {
+		return models.SEOMetrics{}, err
+	}
+	if out.Code != "OK" {
+		return models.SEOMetrics{}, fmt.Errorf("majestic: %s", out.ErrorMessage)
+	}
+	if len(out.DataTables.Results.Data) == 0 {
+		return models.SEOMetrics{}, errors.New("majestic: no result")
+	}
+	d := out.DataTables.Results.Data[0]
+	return models.SEOMetrics{
+		Source:     "Majestic",
+		Metric:     "TF",
+		Authority:  d.TrustFlow,
+		Backlinks:  d.ExtBackLinks,
+		RefDomains: d.RefDomains,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// TrademarkOffice searches one office's register
+type TrademarkOffice interface {
+	Name() string
+	// Search returns live marks whose text matches mark
+	Search(ctx context.Context, mark string) ([]models.Trademark, error)
+}
+
+// Trademarks flags findings whose name is a live trademark, matched
+// exactly and ignoring case, at any of Offices
+type Trademarks struct {
+	Offices []TrademarkOffice
+}
+
+// Name implements Enricher
+func (t *Trademarks) Name() string { return "trademarks" }
+
+// Enrich implements Enricher. An office that fails is skipped for the
+// rest of the run, so one outage doesn't cost a query per name.
+func (t *Trademarks) Enrich(ctx context.Context, results []models.DomainResult) error {
+	var errs []error
+	for _, office := range t.Offices {
+		for label, idx := range byLabel(results) {
+			marks, err := office.Search(ctx, label)
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+			for _, m := range marks {
+				if !strings.EqualFold(m.Mark, label) {
+					continue
+				}
+				for _, i := range idx {
+					e := of(&results[i])
+					e.Trademarks = append(e.Trademarks, m)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+const usptoSearchAPI = "https://tmsearch.uspto.gov/api-v1-0-0/tmsearch"
+
+// USPTO searches the US trademark register through the open search API
+// behind tmsearch.uspto.gov. It needs no key.
+type USPTO struct{}
+
+// Name implements TrademarkOffice
+func (USPTO) Name() string { return "USPTO" }
+
+// Search implements TrademarkOffice
+func (USPTO) Search(ctx context.Context, mark string) ([]models.Trademark, error) {
+	query := map[string]any{
+		"query": map[string]any{
+			"bool": map[string]any{
+				"must": []any{
+					map[string]any{"match_phrase": map[string]any{"wordmark": mark}},
+					map[string]any{"term": map[string]any{"alive": true}},
+				},
+			},
+		},
+		"size": 25,
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", usptoSearchAPI, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("uspto returned status %d", resp.StatusCode)
+	}
+
+	var found struct {
+		Hits struct {
+			Hits []struct {
+				ID     string `json:"id"`
+				Source struct {
+					Wordmark  string   `json:"wordmark"`
+					OwnerName []string `json:"ownerName"`
+					Alive     bool     `json:"alive"`
+				} `json:"source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		return nil, err
+	}
+
+	var marks []models.Trademark
+	for _, h := range found.Hits.Hits {
+		if !h.Source.Alive {
+			continue
+		}
+		marks = append(marks, models.Trademark{
+			Office: "USPTO",
+			Mark:   h.Source.Wordmark,
+			Number: h.ID,
+			Owner:  strings.Join(h.Source.OwnerName, ", "),
+			Status: "live",
+		})
+	}
+	return marks, nil
+}
@@ -0,0 +1,79 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+const waybackCDX = "https://web.archive.org/cdx/search/cdx"
+
+// Wayback records whether each finding had content in the Wayback Machine
+// and when. A previously used domain may carry SEO value, or spam baggage.
+type Wayback struct{}
+
+// Name implements Enricher
+func (Wayback) Name() string { return "wayback" }
+
+// Enrich implements Enricher. Lookups stop at the first failure, which is
+// usually the CDX server rate limiting us.
+func (w Wayback) Enrich(ctx context.Context, results []models.DomainResult) error {
+	for i := range results {
+		h, err := w.history(ctx, results[i].Domain)
+		if err != nil {
+			return err
+		}
+		of(&results[i]).History = &h
+	}
+	return nil
+}
+
+// history asks the CDX server for successful captures of the domain,
+// collapsed to one per month
+func (Wayback) history(ctx context.Context, domain string) (models.History, error) {
+	q := url.Values{
+		"url":      {domain},
+		"output":   {"json"},
+		"fl":       {"timestamp"},
+		"filter":   {"statuscode:200"},
+		"collapse": {"timestamp:6"},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", waybackCDX+"?"+q.Encode(), nil)
+	if err != nil {
+		return models.History{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return models.History{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return models.History{}, fmt.Errorf("wayback returned status %d", resp.StatusCode)
+	}
+
+	// An empty body means no captures; otherwise a header row, then one
+	// row per capture
+	var rows [][]string
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil && err != io.EOF {
+		return models.History{}, err
+	}
+	var h models.History
+	for _, row := range rows {
+		t, err := time.Parse("20060102150405", row[0])
+		if err != nil {
+			continue // header
+		}
+		if h.First.IsZero() {
+			h.First = t
+		}
+		h.Last = t
+		h.Months++
+	}
+	return h, nil
+}
@@ -0,0 +1,236 @@
+// Package export writes check results in machine- and human-readable formats.
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/render"
+)
+
+// Formats are the supported output formats
+var Formats = render.Formats
+
+// Valid reports whether format is supported
+func Valid(format string) bool {
+	return render.Valid(format)
+}
+
+// FormatFor infers a file format from path's extension, defaulting to json
+func FormatFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	case ".csv":
+		return "csv"
+	case ".txt":
+		return "table"
+	}
+	return "json"
+}
+
+// results prints check results; tables mark statuses with a glyph and show
+// premium prices, CSV has RFC 3339 times
+var results = render.Table[models.DomainResult]{
+	Columns: []string{"DOMAIN", "TLD", "STATUS", "CHECKED"},
+	Row: func(r models.DomainResult) []string {
+		status := StatusCell(r.Status)
+		if r.PriceNote() != "" {
+			status += fmt.Sprintf(" $%.2f", r.Price)
+		}
+		return []string{r.Domain, tld(r.Domain), status, r.CheckedAt.Format(time.DateTime)}
+	},
+	CSVColumns: []string{"domain", "tld", "status", "checked_at"},
+	CSVRow: func(r models.DomainResult) []string {
+		return []string{r.Domain, tld(r.Domain), string(r.Status), r.CheckedAt.Format(time.RFC3339)}
+	},
+	Colors: func(r models.DomainResult) []render.Color {
+		domain := render.Default
+		if r.Status == models.StatusAvailable {
+			domain = render.Green
+		}
+		return []render.Color{domain, render.Default, StatusColor(r.Status), render.Dim}
+	},
+}
+
+// statusGlyphs tell statuses apart at a glance, with or without color
+var statusGlyphs = map[models.DomainStatus]string{
+	models.StatusAvailable: "✓",
+	models.StatusTaken:     "✗",
+	models.StatusPremium:   "$",
+	models.StatusUnknown:   "?",
+	models.StatusUnchecked: "·",
+	models.StatusError:     "!",
+}
+
+// StatusCell is how a table shows status: its glyph, then its name
+func StatusCell(status models.DomainStatus) string {
+	if g, ok := statusGlyphs[status]; ok {
+		return g + " " + string(status)
+	}
+	return string(status)
+}
+
+// StatusColor is the color a table shows status in: green available, red
+// taken or failed, yellow anything in between
+func StatusColor(status models.DomainStatus) render.Color {
+	switch status {
+	case models.StatusAvailable:
+		return render.Green
+	case models.StatusTaken, models.StatusError:
+		return render.Red
+	case models.StatusPremium, models.StatusUnknown:
+		return render.Yellow
+	}
+	return render.Dim
+}
+
+// Write writes results to w in format:
+//   - json: an indented array
+//   - ndjson: one object per line
+//   - csv: domain,tld,status,checked_at with a header row
+//   - table: aligned columns for terminals
+func Write(w io.Writer, format string, rs []models.DomainResult) error {
+	return results.Write(w, format, rs)
+}
+
+// Only returns the results with one of statuses, all of them if none are
+// given
+func Only(rs []models.DomainResult, statuses ...models.DomainStatus) []models.DomainResult {
+	if len(statuses) == 0 {
+		return rs
+	}
+	var kept []models.DomainResult
+	for _, r := range rs {
+		if slices.Contains(statuses, r.Status) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// WriteFile writes results to path, replacing it. An empty format is
+// inferred from the extension.
+func WriteFile(path, format string, results []models.DomainResult) error {
+	if format == "" {
+		format = FormatFor(path)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, format, results); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read reads results written as json or ndjson, telling them apart by
+// the first character: an array is json, anything else one object per line
+func Read(r io.Reader) ([]models.DomainResult, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(br)
+	if first == '[' {
+		var rs []models.DomainResult
+		if err := dec.Decode(&rs); err != nil {
+			return nil, err
+		}
+		return rs, nil
+	}
+	var rs []models.DomainResult
+	for {
+		var res models.DomainResult
+		if err := dec.Decode(&res); errors.Is(err, io.EOF) {
+			return rs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("result %d: %w", len(rs)+1, err)
+		}
+		rs = append(rs, res)
+	}
+}
+
+// ReadFile reads results from path, as Read does. "-" is stdin.
+func ReadFile(path string) ([]models.DomainResult, error) {
+	if path == "-" {
+		return Read(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without
+// consuming it
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+func tld(domain string) string {
+	return domain[strings.LastIndex(domain, ".")+1:]
+}
+
+// Appender appends results to a file as NDJSON one at a time, each line
+// written straight through, so a run that dies part way leaves every
+// result it got to
+type Appender struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// OpenAppender opens path for appending, creating it if needed. "-" is
+// stdout.
+func OpenAppender(path string) (*Appender, error) {
+	f := os.Stdout
+	if path != "-" {
+		var err error
+		if f, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return &Appender{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Write appends r as one line. Its method value is a checker.Sink.
+func (a *Appender) Write(r models.DomainResult) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.enc.Encode(r)
+}
+
+// Close closes the file, leaving stdout open
+func (a *Appender) Close() error {
+	if a.f == os.Stdout {
+		return nil
+	}
+	return a.f.Close()
+}
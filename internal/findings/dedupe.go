@@ -0,0 +1,72 @@
+package findings
+
+import (
+	"context"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// Unreported returns the available results that haven't been sent in a
+// previous report
+func Unreported(ctx context.Context, store storage.Store, available []models.DomainResult) ([]models.DomainResult, error) {
+	reported, err := store.ListReported(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(reported))
+	for _, f := range reported {
+		seen[f.Domain] = true
+	}
+
+	var fresh []models.DomainResult
+	for _, r := range available {
+		if !seen[r.Domain] {
+			fresh = append(fresh, r)
+		}
+	}
+	return fresh, nil
+}
+
+// MarkReported records available as reported and forgets previously
+// reported domains that were re-checked and are no longer available, so
+// they are reported again if they come back. checked reports whether a
+// domain was re-checked; nil means none were.
+func MarkReported(ctx context.Context, store storage.Store, available []models.DomainResult, checked func(domain string) bool) error {
+	reported, err := store.ListReported(ctx)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]models.ReportedFinding, len(reported))
+	for _, f := range reported {
+		previous[f.Domain] = f
+	}
+
+	now := time.Now()
+	isAvailable := make(map[string]bool, len(available))
+	updates := make([]models.ReportedFinding, 0, len(available))
+	for _, r := range available {
+		isAvailable[r.Domain] = true
+		f, ok := previous[r.Domain]
+		if !ok {
+			f = models.ReportedFinding{Domain: r.Domain, FirstReportedAt: now}
+		}
+		f.LastReportedAt = now
+		updates = append(updates, f)
+	}
+
+	var gone []string
+	for d := range previous {
+		if checked != nil && checked(d) && !isAvailable[d] {
+			gone = append(gone, d)
+		}
+	}
+
+	if err := store.SaveReported(ctx, updates); err != nil {
+		return err
+	}
+	return store.DeleteReported(ctx, gone)
+}
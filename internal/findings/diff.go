@@ -0,0 +1,39 @@
+package findings
+
+import (
+	"context"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// PreviousRun returns the most recent scan of kind with the same params,
+// or false if there is none
+func PreviousRun(ctx context.Context, store storage.Store, kind, params string) (models.Scan, bool, error) {
+	scans, err := store.ListScans(ctx, 0)
+	if err != nil {
+		return models.Scan{}, false, err
+	}
+	for _, s := range scans {
+		if s.Kind == kind && s.Params == params {
+			return s, true, nil
+		}
+	}
+	return models.Scan{}, false, nil
+}
+
+// NewSince returns the available results that were not available in prev
+func NewSince(prev models.Scan, available []models.DomainResult) []models.DomainResult {
+	before := make(map[string]bool, len(prev.Available))
+	for _, d := range prev.Available {
+		before[d] = true
+	}
+
+	var fresh []models.DomainResult
+	for _, r := range available {
+		if !before[r.Domain] {
+			fresh = append(fresh, r)
+		}
+	}
+	return fresh
+}
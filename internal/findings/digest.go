@@ -0,0 +1,50 @@
+package findings
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// GotTaken returns the domains that were available in a daily run since
+// since but were checked again and no longer are. checked reports whether a
+// domain was checked again.
+func GotTaken(ctx context.Context, store storage.Store, since time.Time, available []models.DomainResult, checked func(domain string) bool) ([]string, error) {
+	scans, err := store.ListScans(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	stillAvailable := make(map[string]bool, len(available))
+	for _, r := range available {
+		stillAvailable[r.Domain] = true
+	}
+
+	var taken []string
+	for _, s := range scans {
+		if s.Kind != "daily" || s.StartedAt.Before(since) {
+			continue
+		}
+		for _, d := range s.Available {
+			if checked(d) && !stillAvailable[d] && !slices.Contains(taken, d) {
+				taken = append(taken, d)
+			}
+		}
+	}
+	slices.Sort(taken)
+	return taken, nil
+}
+
+// WatchChanges returns the watched domains whose status changed since since
+func WatchChanges(ctx context.Context, store storage.Store, since time.Time) ([]models.WatchedDomain, error) {
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(watches, func(w models.WatchedDomain) bool {
+		return w.ChangedAt.Before(since)
+	}), nil
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/backup"
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+var adminToken string
+
+// SetAdminToken sets the password for admin pages. Admin pages are disabled
+// while it is empty.
+func SetAdminToken(token string) {
+	adminToken = token
+}
+
+// AdminOnly requires HTTP basic auth with the admin token as password
+func AdminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if adminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+		_, pass, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(pass), []byte(adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Domain Hunter admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// serverHealth is one row of the registry health panel
+type serverHealth struct {
+	models.ServerStats
+	Health   checker.Health
+	ErrorPct float64
+	// Limit and Spacing are the concurrency and query spacing the checker
+	// has adapted to
+	Limit   int
+	Spacing time.Duration
+	// CircuitUntil is when the server's open circuit lets a probe through;
+	// zero while closed
+	CircuitUntil time.Time
+}
+
+// AdminHealth renders the registry health panel
+func AdminHealth(w http.ResponseWriter, r *http.Request) {
+	var rows []serverHealth
+	for _, s := range domainChecker.Telemetry().Snapshot() {
+		limit, spacing := domainChecker.Limit(s.Server)
+		rows = append(rows, serverHealth{
+			ServerStats:  s,
+			Health:       checker.HealthOf(s),
+			ErrorPct:     s.ErrorRate * 100,
+			Limit:        limit,
+			Spacing:      spacing,
+			CircuitUntil: domainChecker.Circuit(s.Server),
+		})
+	}
+	render(w, r, "admin-health.html", struct {
+		Servers   []serverHealth
+		DNSCache  checker.CacheStats
+		Wildcards []string
+	}{rows, domainChecker.DNSCacheStats(), domainChecker.Wildcards()})
+}
+
+// AdminOverview renders every user's watches and recent scans
+func AdminOverview(w http.ResponseWriter, r *http.Request) {
+	watches, err := store.ListWatches(r.Context())
+	if err != nil {
+		serverError(w, r, "Could not load watch list", err)
+		return
+	}
+	scans, err := store.ListScans(r.Context(), 50)
+	if err != nil {
+		serverError(w, r, "Could not load scans", err)
+		return
+	}
+
+	render(w, r, "admin-overview.html", struct {
+		Watches []models.WatchedDomain
+		Scans   []models.Scan
+	}{
+		Watches: watches,
+		Scans:   scans,
+	})
+}
+
+// AdminBackup sends a hot backup of the database. bbolt locks the file
+// while the server runs, so this is how a cron job backs up a live instance.
+// The archive is built before anything is sent, so a failed snapshot is a
+// 500 rather than a truncated download.
+func AdminBackup(w http.ResponseWriter, r *http.Request) {
+	var archive bytes.Buffer
+	if err := backup.Write(&archive, store, nil); err != nil {
+		serverError(w, r, "Backup failed", err)
+		return
+	}
+
+	name := "domainhunter-" + time.Now().Format("20060102-150405") + ".tar.gz"
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(archive.Len()))
+	if _, err := archive.WriteTo(w); err != nil {
+		slog.ErrorContext(r.Context(), "backup", "err", err)
+	}
+}
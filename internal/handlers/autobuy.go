@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/autobuy"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+)
+
+var (
+	autoBuyRegistrars map[string]autobuy.Registrar
+	autoBuyAudit      *autobuy.Audit
+)
+
+// SetAutoBuy sets the registrars watches can auto-register through and the
+// audit log every attempt is appended to
+func SetAutoBuy(registrars map[string]autobuy.Registrar, audit *autobuy.Audit) {
+	autoBuyRegistrars = registrars
+	autoBuyAudit = audit
+}
+
+// autoBuy attempts w's auto-buy while it's seen available and no attempt
+// has settled it. The domain is checked once more first so a flaky lookup
+// can't spend money. A failed attempt is recorded and tried again on the
+// next check, alerting only the first time. A registered domain joins the
+// portfolio.
+func autoBuy(ctx context.Context, w *models.WatchedDomain, now time.Time) (notify.Alert, bool) {
+	ab := w.AutoBuy
+	if ab == nil || !ab.AttemptedAt.IsZero() {
+		return notify.Alert{}, false
+	}
+	if res := domainChecker.Check(ctx, w.Domain); res.Status != models.StatusAvailable {
+		slog.InfoContext(ctx, "auto-buy skipped, not confirmed available", "domain", w.Domain, "status", res.Status)
+		return notify.Alert{}, false
+	}
+
+	var a autobuy.Attempt
+	if r, ok := autoBuyRegistrars[ab.Registrar]; ok {
+		a = autobuy.Buy(ctx, r, w.Domain, ab.MaxPrice, ab.DryRun)
+	} else {
+		a = autobuy.Attempt{
+			Time: now, Domain: w.Domain, Registrar: ab.Registrar, MaxPrice: ab.MaxPrice, DryRun: ab.DryRun,
+			Outcome: autobuy.OutcomeFailed, Error: "registrar isn't configured",
+		}
+	}
+	if autoBuyAudit != nil {
+		if err := autoBuyAudit.Record(a); err != nil {
+			slog.ErrorContext(ctx, "auto-buy audit", "domain", w.Domain, "err", err)
+		}
+	}
+	slog.InfoContext(ctx, "auto-buy", "domain", w.Domain, "result", a.Describe())
+
+	failedBefore := ab.Outcome == autobuy.OutcomeFailed
+	ab.Outcome, ab.Error = a.Outcome, a.Error
+	if !a.Final() {
+		if failedBefore {
+			return notify.Alert{}, false
+		}
+		return notify.Alert{Domain: w.Domain, Kind: notify.AlertAutoBuy, Message: a.Describe() + "; will retry"}, true
+	}
+	ab.AttemptedAt = now
+	if a.Outcome == autobuy.OutcomeRegistered {
+		w.Owned = true
+		w.Status = models.StatusTaken
+		w.Registrar = ab.Registrar
+		w.NextCheckAt = time.Time{}
+	}
+	return notify.Alert{Domain: w.Domain, Kind: notify.AlertAutoBuy, Message: a.Describe()}, true
+}
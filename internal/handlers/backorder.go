@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/backorder"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+)
+
+var (
+	backorderProviders map[string]backorder.Provider
+	backorderBudget    float64
+)
+
+// SetBackorders sets the providers watches can backorder through and the
+// most to commit per calendar month, zero meaning no limit
+func SetBackorders(providers map[string]backorder.Provider, monthlyBudget float64) {
+	backorderProviders = providers
+	backorderBudget = monthlyBudget
+}
+
+// placeBackorder places w's backorder once it is in pending delete. Each
+// order is tried once; the outcome is recorded on the watch and returned
+// as an alert.
+func placeBackorder(ctx context.Context, w *models.WatchedDomain, now time.Time) (notify.Alert, bool) {
+	b := w.Backorder
+	if b == nil || b.Placed() || b.Error != "" || w.Phase != models.PhasePendingDelete {
+		return notify.Alert{}, false
+	}
+
+	order, err := func() (backorder.Order, error) {
+		p, ok := backorderProviders[b.Provider]
+		if !ok {
+			return backorder.Order{}, fmt.Errorf("provider %q isn't configured", b.Provider)
+		}
+		if backorderBudget > 0 {
+			spent, err := backorderSpent(ctx, now)
+			if err != nil {
+				return backorder.Order{}, err
+			}
+			if spent+b.MaxPrice > backorderBudget {
+				return backorder.Order{}, fmt.Errorf("$%.2f cap would exceed the monthly budget ($%.2f of $%.2f committed)", b.MaxPrice, spent, backorderBudget)
+			}
+		}
+		return p.Place(ctx, w.Domain, b.MaxPrice)
+	}()
+
+	alert := notify.Alert{Domain: w.Domain, Kind: notify.AlertBackorder}
+	if err != nil {
+		slog.ErrorContext(ctx, "place backorder", "domain", w.Domain, "err", err)
+		b.Error = err.Error()
+		alert.Message = fmt.Sprintf("backorder with %s failed: %v", b.Provider, err)
+		return alert, true
+	}
+	b.OrderID, b.Amount, b.PlacedAt = order.ID, order.Amount, now
+	alert.Message = fmt.Sprintf("backorder placed with %s for up to $%.2f", b.Provider, order.Amount)
+	return alert, true
+}
+
+// backorderSpent totals the backorders placed in now's calendar month
+func backorderSpent(ctx context.Context, now time.Time) (float64, error) {
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var spent float64
+	for _, w := range watches {
+		if b := w.Backorder; b != nil && b.Placed() &&
+			b.PlacedAt.Year() == now.Year() && b.PlacedAt.Month() == now.Month() {
+			spent += b.Amount
+		}
+	}
+	return spent, nil
+}
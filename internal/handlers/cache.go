@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/social"
+)
+
+const (
+	// resultTTL is how long a check result is reused before re-querying WHOIS
+	resultTTL = 10 * time.Minute
+	// handleTTL is how long a name's social handles are reused before
+	// asking the sites again
+	handleTTL = time.Hour
+
+	// Per-client request budget for the check endpoints
+	rateLimitRequests = 30
+	rateLimitWindow   = time.Minute
+)
+
+// checkCached checks domains, reusing recent results from the cache and
+// only querying WHOIS for the rest
+func checkCached(ctx context.Context, domains []string) []models.DomainResult {
+	results := make([]models.DomainResult, len(domains))
+	var missing []string
+	var missingIdx []int
+
+	for i, d := range domains {
+		if b, err := resultCache.Get(ctx, "result:"+d); err == nil {
+			if json.Unmarshal(b, &results[i]) == nil {
+				continue
+			}
+		}
+		missing = append(missing, d)
+		missingIdx = append(missingIdx, i)
+	}
+
+	if len(missing) == 0 {
+		return results
+	}
+
+	// A request cancelled mid-check leaves results not worth keeping
+	fresh, err := domainChecker.CheckBulk(ctx, missing)
+	if err != nil {
+		for j, r := range fresh {
+			results[missingIdx[j]] = r
+		}
+		return results
+	}
+	saveResults(ctx, fresh)
+
+	for j, r := range fresh {
+		results[missingIdx[j]] = r
+		if r.Status == models.StatusUnknown {
+			// Skipped while its registry was down; ask again next time
+			continue
+		}
+		if b, err := json.Marshal(r); err == nil {
+			if err := resultCache.Set(ctx, "result:"+r.Domain, b, resultTTL); err != nil {
+				slog.ErrorContext(ctx, "cache set", "domain", r.Domain, "err", err)
+			}
+		}
+	}
+
+	return results
+}
+
+// checkHandles checks name's social handles, reusing a recent answer from
+// the cache. One a site gave no clear answer on is asked again next time.
+func checkHandles(ctx context.Context, name string) []models.Handle {
+	key := "handles:" + name
+	if b, err := resultCache.Get(ctx, key); err == nil {
+		var handles []models.Handle
+		if json.Unmarshal(b, &handles) == nil {
+			return handles
+		}
+	}
+
+	handles := social.Check(ctx, social.Sites, name)
+	if ctx.Err() != nil || slices.ContainsFunc(handles, func(h models.Handle) bool { return h.Status == models.HandleUnknown }) {
+		return handles
+	}
+	if b, err := json.Marshal(handles); err == nil {
+		if err := resultCache.Set(ctx, key, b, handleTTL); err != nil {
+			slog.ErrorContext(ctx, "cache set", "name", name, "err", err)
+		}
+	}
+	return handles
+}
+
+// RateLimit limits each client to rateLimitRequests per rateLimitWindow.
+// Counters live in the shared cache so the limit holds across instances.
+func RateLimit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		window := time.Now().Unix() / int64(rateLimitWindow.Seconds())
+		key := "ratelimit:" + clientIP(r) + ":" + strconv.FormatInt(window, 10)
+
+		n, err := resultCache.Incr(r.Context(), key, rateLimitWindow)
+		if err != nil {
+			// Fail open: a cache outage shouldn't take the checker down
+			slog.ErrorContext(r.Context(), "rate limit", "err", err)
+		} else if n > rateLimitRequests {
+			w.Header().Set("Retry-After", strconv.Itoa(int(rateLimitWindow.Seconds())))
+			http.Error(w, "Too many requests, slow down", http.StatusTooManyRequests)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// Proxies whose client IP headers are believed
+var (
+	trustedProxies []netip.Prefix
+	behindFly      bool
+)
+
+// SetTrustedProxies sets the reverse proxies whose Fly-Client-IP or
+// X-Forwarded-For header names the client; fly trusts Fly's own proxy.
+// Without either, rate limits go by the connection's address, since
+// anyone can send those headers.
+func SetTrustedProxies(proxies []netip.Prefix, fly bool) {
+	trustedProxies, behindFly = proxies, fly
+}
+
+// clientIP returns the caller's address: the one a trusted proxy passes on,
+// or else the connection's
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if behindFly {
+		if ip := r.Header.Get("Fly-Client-IP"); ip != "" {
+			return ip
+		}
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !slices.ContainsFunc(trustedProxies, func(p netip.Prefix) bool { return p.Contains(addr.Unmap()) }) {
+		return host
+	}
+	if ip := r.Header.Get("Fly-Client-IP"); ip != "" {
+		return ip
+	}
+	// The proxy appends the address it saw; earlier entries are the
+	// client's to make up
+	if fwd := r.Header.Values("X-Forwarded-For"); len(fwd) > 0 {
+		hops := strings.Split(fwd[len(fwd)-1], ",")
+		if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+			return ip
+		}
+	}
+	return host
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+)
+
+// certThresholds are the days before a TLS certificate lapses at which a
+// watched domain alerts, each once per certificate
+var certThresholds = []int{14, 7, 1}
+
+// certRecheck is how often watched domains' certificates are probed
+const certRecheck = 24 * time.Hour
+
+// checkCerts probes the certificates of watched domains that are due and
+// alerts for those nearing expiry. Available domains have no site to probe.
+func checkCerts(ctx context.Context, notifiers []notify.Notifier) error {
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var alerts []notify.Alert
+	for _, w := range watches {
+		if w.Status == models.StatusAvailable || now.Sub(w.Cert.CheckedAt) < certRecheck {
+			continue
+		}
+		probeCert(ctx, &w, now)
+		if a, ok := certAlert(&w, now); ok {
+			alerts = append(alerts, a)
+		}
+		if err := store.UpdateWatch(ctx, &w); err != nil {
+			slog.ErrorContext(ctx, "update watch", "domain", w.Domain, "err", err)
+		}
+	}
+	if len(alerts) > 0 {
+		sendAlert(ctx, notifiers, notify.Report{Title: "Certificate Alert", Alerts: alerts, Date: now})
+	}
+	return nil
+}
+
+// probeCert records w's current certificate. A failed probe keeps the last
+// certificate seen; a later expiry means it was renewed, so its alerts
+// start over.
+func probeCert(ctx context.Context, w *models.WatchedDomain, now time.Time) {
+	cert, err := domainChecker.ProbeTLS(ctx, w.Domain)
+	if err != nil {
+		w.Cert.Error = err.Error()
+		w.Cert.CheckedAt = now
+		return
+	}
+	if cert.NotAfter.After(w.Cert.NotAfter) {
+		w.CertAlerted = 0
+	}
+	w.Cert = cert
+}
+
+// certAlert returns an alert if w's certificate has crossed an expiry
+// threshold it hasn't been alerted for
+func certAlert(w *models.WatchedDomain, now time.Time) (notify.Alert, bool) {
+	threshold, ok := crossedThreshold(w.Cert.NotAfter, now, certThresholds, w.CertAlerted)
+	if !ok {
+		return notify.Alert{}, false
+	}
+	w.CertAlerted = threshold
+
+	msg := "TLS certificate " + expiresIn(w.Cert.NotAfter, now)
+	if w.Cert.Issuer != "" {
+		msg += ", issued by " + w.Cert.Issuer
+	}
+	return notify.Alert{Domain: w.Domain, Kind: notify.AlertCert, Message: msg}, true
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/ct"
+	"github.com/berckan/domainhunter/internal/notify"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// ctRecheck is how often Certificate Transparency logs are searched
+const ctRecheck = time.Hour
+
+var (
+	ctKeywords  []string
+	ctCheckedAt time.Time
+)
+
+// SetCTKeywords sets the brand keywords whose new certificates are alerted
+// on. Blank entries are ignored.
+func SetCTKeywords(keywords []string) {
+	ctKeywords = nil
+	for _, k := range keywords {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			ctKeywords = append(ctKeywords, k)
+		}
+	}
+}
+
+// checkCT searches Certificate Transparency logs for certificates issued
+// since the last search to names containing a brand keyword, such as a
+// phishing site on a lookalike domain. The first search for a keyword only
+// records where the logs are up to. Names under owned domains are yours
+// and left out.
+func checkCT(ctx context.Context, notifiers []notify.Notifier) error {
+	now := time.Now()
+	if len(ctKeywords) == 0 || now.Sub(ctCheckedAt) < ctRecheck {
+		return nil
+	}
+	ctCheckedAt = now
+
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return err
+	}
+	var owned []string
+	for _, w := range watches {
+		if w.Owned {
+			owned = append(owned, w.Domain)
+		}
+	}
+
+	var alerts []notify.Alert
+	for _, kw := range ctKeywords {
+		key := "ct/" + kw
+		since, err := ctSince(ctx, key)
+		if err != nil {
+			slog.ErrorContext(ctx, "ct search", "keyword", kw, "err", err)
+			continue
+		}
+		certs, err := ct.Search(ctx, kw, max(since, 0))
+		if err != nil {
+			slog.ErrorContext(ctx, "ct search", "keyword", kw, "err", err)
+			continue
+		}
+		if len(certs) == 0 {
+			continue
+		}
+		if since >= 0 {
+			for _, c := range certs {
+				if a, ok := ctAlert(kw, c, owned); ok {
+					alerts = append(alerts, a)
+				}
+			}
+		}
+		if err := store.SetSetting(ctx, key, strconv.FormatInt(certs[len(certs)-1].ID, 10)); err != nil {
+			slog.ErrorContext(ctx, "ct search", "keyword", kw, "err", err)
+		}
+	}
+	if len(alerts) > 0 {
+		sendAlert(ctx, notifiers, notify.Report{Title: "Certificate Transparency Alert", Alerts: alerts, Date: now})
+	}
+	return nil
+}
+
+// ctSince returns the last certificate id seen for a keyword, or -1 if it
+// was never searched
+func ctSince(ctx context.Context, key string) (int64, error) {
+	v, err := store.GetSetting(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// ctAlert describes c, unless all its names are under owned domains
+func ctAlert(keyword string, c ct.Cert, owned []string) (notify.Alert, bool) {
+	var names []string
+	for _, n := range c.Names {
+		if !underAny(n, owned) {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return notify.Alert{}, false
+	}
+	msg := fmt.Sprintf("new certificate from %s for %s, matching %q", c.Issuer, strings.Join(names, ", "), keyword)
+	if !c.NotBefore.IsZero() {
+		msg += ", valid from " + c.NotBefore.Format("Jan 2 2006")
+	}
+	return notify.Alert{Domain: names[0], Kind: notify.AlertCT, Message: msg}, true
+}
+
+// underAny reports whether name is one of domains or a subdomain of one
+func underAny(name string, domains []string) bool {
+	name = strings.TrimPrefix(name, "*.")
+	for _, d := range domains {
+		if name == d || strings.HasSuffix(name, "."+d) {
+			return true
+		}
+	}
+	return false
+}
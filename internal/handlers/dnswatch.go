@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+)
+
+// dnsRecheck is how often watched domains' DNS records are snapshotted
+const dnsRecheck = 6 * time.Hour
+
+// checkDNS snapshots the NS, A and MX records of watched domains that are
+// due and alerts on changes. Nameservers moving or a site going dark often
+// come shortly before a domain lapses.
+func checkDNS(ctx context.Context, notifiers []notify.Notifier) error {
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var alerts []notify.Alert
+	for _, w := range watches {
+		if w.Status == models.StatusAvailable || now.Sub(w.DNS.CheckedAt) < dnsRecheck {
+			continue
+		}
+		snap, err := domainChecker.Snapshot(ctx, w.Domain)
+		if err != nil {
+			slog.WarnContext(ctx, "watch dns snapshot", "domain", w.Domain, "err", err)
+			continue
+		}
+		if !w.DNS.CheckedAt.IsZero() {
+			alerts = append(alerts, dnsChanges(w.Domain, w.DNS, snap)...)
+		}
+		w.DNS = snap
+		if err := store.UpdateWatch(ctx, &w); err != nil {
+			slog.ErrorContext(ctx, "update watch", "domain", w.Domain, "err", err)
+		}
+	}
+	if len(alerts) > 0 {
+		sendAlert(ctx, notifiers, notify.Report{Title: "DNS Change Alert", Alerts: alerts, Date: now})
+	}
+	return nil
+}
+
+// dnsChanges returns an alert for each record type that differs between
+// the snapshots
+func dnsChanges(domain string, old, cur models.DNSSnapshot) []notify.Alert {
+	var alerts []notify.Alert
+	add := func(msg string) {
+		alerts = append(alerts, notify.Alert{Domain: domain, Kind: notify.AlertDNS, Message: msg})
+	}
+	if msg, ok := recordChange("nameservers", old.NS, cur.NS); ok {
+		add(msg)
+	}
+	if len(old.A) > 0 && len(cur.A) == 0 {
+		add("site taken down: A records removed (was " + listOrNone(old.A) + ")")
+	} else if msg, ok := recordChange("A records", old.A, cur.A); ok {
+		add(msg)
+	}
+	if msg, ok := recordChange("mail servers", old.MX, cur.MX); ok {
+		add(msg)
+	}
+	return alerts
+}
+
+// recordChange describes a change to one record set
+func recordChange(what string, old, cur []string) (string, bool) {
+	if slices.Equal(old, cur) {
+		return "", false
+	}
+	if len(cur) == 0 {
+		return what + " removed (was " + listOrNone(old) + ")", true
+	}
+	return what + " changed from " + listOrNone(old) + " to " + listOrNone(cur), true
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+)
+
+// expiryThresholds are the days before expiry at which an owned domain
+// alerts, each once per expiry date, so alerts escalate as it nears
+var expiryThresholds = []int{60, 30, 7, 1}
+
+// ownedRecheck is how often owned domains' WHOIS is refreshed
+const ownedRecheck = 24 * time.Hour
+
+// checkOwned refreshes due owned domains from WHOIS and alerts for those
+// that crossed an expiry threshold or whose WHOIS changed
+func checkOwned(ctx context.Context, notifiers []notify.Notifier) error {
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var alerts, changes []notify.Alert
+	for _, w := range watches {
+		if !w.Owned || w.NextCheckAt.After(now) {
+			continue
+		}
+		if changed, ok := refreshWhois(ctx, &w, now); ok {
+			changes = append(changes, changed...)
+			refreshExpiry(&w)
+		}
+		if a, ok := expiryAlert(&w, now); ok {
+			alerts = append(alerts, a)
+		}
+		w.NextCheckAt = now.Add(ownedRecheck)
+		if err := store.UpdateWatch(ctx, &w); err != nil {
+			slog.ErrorContext(ctx, "update watch", "domain", w.Domain, "err", err)
+		}
+	}
+	if len(alerts) > 0 {
+		sendAlert(ctx, notifiers, notify.Report{Title: "Expiry Alert", Alerts: alerts, Date: now})
+	}
+	if len(changes) > 0 {
+		sendAlert(ctx, notifiers, notify.Report{Title: whoisAlertTitle, Alerts: changes, Date: now})
+	}
+	return nil
+}
+
+// refreshExpiry updates w's expiry and registrar from its WHOIS record. A
+// later expiry means the domain was renewed, so its alerts start over.
+func refreshExpiry(w *models.WatchedDomain) {
+	rec := w.Whois
+	if !rec.Expires.IsZero() {
+		if rec.Expires.After(w.ExpiresAt) {
+			w.ExpiryAlerted = 0
+		}
+		w.ExpiresAt = rec.Expires
+	}
+	if w.Registrar == "" {
+		w.Registrar = rec.Registrar
+	}
+}
+
+// expiryAlert returns an alert if w has crossed an expiry threshold it
+// hasn't been alerted for
+func expiryAlert(w *models.WatchedDomain, now time.Time) (notify.Alert, bool) {
+	threshold, ok := crossedThreshold(w.ExpiresAt, now, expiryThresholds, w.ExpiryAlerted)
+	if !ok {
+		return notify.Alert{}, false
+	}
+	w.ExpiryAlerted = threshold
+
+	msg := expiresIn(w.ExpiresAt, now)
+	if w.Registrar != "" {
+		msg += " at " + w.Registrar
+	}
+	switch {
+	case w.AutoRenew == nil:
+	case *w.AutoRenew:
+		msg += " (auto-renew on)"
+	default:
+		msg += " (auto-renew off)"
+	}
+	return notify.Alert{Domain: w.Domain, Kind: notify.AlertExpiry, Message: msg}, true
+}
+
+// crossedThreshold returns the tightest of thresholds (days, descending)
+// that expires falls within, if it is tighter than the one already
+// alerted; alerted 0 means none was
+func crossedThreshold(expires, now time.Time, thresholds []int, alerted int) (int, bool) {
+	if expires.IsZero() {
+		return 0, false
+	}
+	days := daysUntil(expires, now)
+	threshold := 0
+	for _, t := range thresholds {
+		if days <= t {
+			threshold = t
+		}
+	}
+	if threshold == 0 || (alerted != 0 && threshold >= alerted) {
+		return 0, false
+	}
+	return threshold, true
+}
+
+// expiresIn describes when t expires, e.g. "expires in 7 days (Oct 22, 2026)"
+func expiresIn(t, now time.Time) string {
+	date := t.Format("Jan 2, 2006")
+	switch days := daysUntil(t, now); {
+	case days <= 0:
+		return "expired on " + date
+	case days == 1:
+		return "expires tomorrow (" + date + ")"
+	default:
+		return fmt.Sprintf("expires in %d days (%s)", days, date)
+	}
+}
+
+// daysUntil counts started days from now to t
+func daysUntil(t, now time.Time) int {
+	return int(math.Ceil(t.Sub(now).Hours() / 24))
+}
@@ -2,26 +2,88 @@ package handlers
 
 import (
 	"html/template"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/berckan/domainhunter/internal/cache"
 	"github.com/berckan/domainhunter/internal/checker"
 	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/storage"
 )
 
 var (
-	templates     = template.Must(template.ParseGlob("web/templates/*.html"))
-	domainChecker = checker.New()
+	templates     *template.Template
+	domainChecker               = checker.New()
+	resultCache   cache.Cache   = cache.NewMemory()
+	store         storage.Store = storage.NewMemory()
 )
 
+// render executes the named template, logging a failure: by then the
+// response has usually begun, so the client can't be told
+func render(w http.ResponseWriter, r *http.Request, name string, data any) {
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		slog.ErrorContext(r.Context(), "render template", "template", name, "path", r.URL.Path, "err", err)
+	}
+}
+
+// serverError logs err along with the request and answers 500 with msg
+func serverError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	slog.ErrorContext(r.Context(), msg, "method", r.Method, "path", r.URL.Path, "err", err)
+	http.Error(w, msg, http.StatusInternalServerError)
+}
+
+// LoadTemplates parses the page templates matching pattern, e.g.
+// web/templates/*.html; the server must load them before serving
+func LoadTemplates(pattern string) error {
+	t, err := template.ParseGlob(pattern)
+	if err != nil {
+		return err
+	}
+	templates = t
+	return nil
+}
+
+// SetStore replaces the store used to persist results and scan history.
+// Handlers default to an in-memory store so they work without a database.
+func SetStore(s storage.Store) {
+	store = s
+}
+
+// SetChecker replaces the checker used by the handlers and the watcher
+func SetChecker(c *checker.Checker) {
+	domainChecker = c
+}
+
+// SetCache replaces the cache used for recent results and rate limiting
+func SetCache(c cache.Cache) {
+	resultCache = c
+}
+
+// tldPriority orders the TLDs of short-domain scans; see SetTLDPriority
+var tldPriority map[string]int
+
+// SetTLDPriority makes short-domain scans check TLDs with priority 1 first,
+// then 2 and so on, leaving the rest for last, so a scan that's stopped
+// early has covered the TLDs that matter most
+func SetTLDPriority(priority map[string]int) {
+	tldPriority = priority
+}
+
+// shortCandidates are the premium-TLD domains of a short-domain scan, in
+// priority order
+func shortCandidates(length int, prefix string) checker.Tiered {
+	return checker.NewTiered(length, prefix, checker.Charsets["alnum"], checker.PremiumTLDs, tldPriority)
+}
+
 // Home renders the main page
 func Home(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
 		http.NotFound(w, r)
 		return
 	}
-	templates.ExecuteTemplate(w, "index.html", nil)
+	render(w, r, "index.html", nil)
 }
 
 // CheckDomain handles single domain check via HTMX
@@ -42,8 +104,8 @@ func CheckDomain(w http.ResponseWriter, r *http.Request) {
 		domain = domain + ".com"
 	}
 
-	result := domainChecker.Check(domain)
-	templates.ExecuteTemplate(w, "result.html", result)
+	result := checkCached(r.Context(), []string{domain})[0]
+	render(w, r, "result.html", result)
 }
 
 // CheckBulk handles multiple domain checks
@@ -77,8 +139,8 @@ func CheckBulk(w http.ResponseWriter, r *http.Request) {
 		domains = domains[:50]
 	}
 
-	results := domainChecker.CheckBulk(domains)
-	templates.ExecuteTemplate(w, "results-bulk.html", results)
+	results := checkCached(r.Context(), domains)
+	render(w, r, "results-bulk.html", results)
 }
 
 // ScanShort scans short domains across ALL premium TLDs
@@ -103,7 +165,7 @@ func ScanShort(w http.ResponseWriter, r *http.Request) {
 	// 3 chars: need 2 char prefix (36 names × 24 TLDs = 864)
 	minPrefixLen := length - 1
 	if len(prefix) < minPrefixLen {
-		templates.ExecuteTemplate(w, "scan-empty.html", struct {
+		render(w, r, "scan-empty.html", struct {
 			Message string
 		}{
 			Message: "For " + lengthStr + "-char domains, please provide at least " + strconv.Itoa(minPrefixLen) + " character(s) as prefix",
@@ -111,36 +173,23 @@ func ScanShort(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate domains across all premium TLDs
-	domains := checker.GenerateShortDomainsMultiTLD(length, prefix)
-
-	if len(domains) == 0 {
-		templates.ExecuteTemplate(w, "scan-empty.html", nil)
+	// Domains span all premium TLDs
+	if shortCandidates(length, prefix).Len() == 0 {
+		render(w, r, "scan-empty.html", nil)
 		return
 	}
 
-	// Use hybrid check: DNS fast scan + WHOIS confirmation
-	allResults := domainChecker.CheckBulkHybrid(domains)
-
-	// Filter only available domains
-	var available []models.DomainResult
-	for _, r := range allResults {
-		if r.Status == models.StatusAvailable {
-			available = append(available, r)
-		}
-	}
-
-	data := struct {
-		Available []models.DomainResult
-		Total     int
-		Checked   int
-	}{
-		Available: available,
-		Total:     len(available),
-		Checked:   len(domains),
+	// Long scans run as background jobs; the returned partial polls for the result
+	job, err := jobQueue.Enqueue(r.Context(), "short", map[string]string{
+		"length": lengthStr,
+		"prefix": prefix,
+	})
+	if err != nil {
+		serverError(w, r, "Could not queue scan", err)
+		return
 	}
 
-	templates.ExecuteTemplate(w, "scan-results.html", data)
+	render(w, r, "scan-job.html", job)
 }
 
 // CheckMultiTLD checks a domain name across all common TLDs
@@ -165,7 +214,24 @@ func CheckMultiTLD(w http.ResponseWriter, r *http.Request) {
 	domains := checker.GenerateMultiTLD(name, nil)
 
 	// Check all concurrently
-	results := domainChecker.CheckBulk(domains)
+	results := checkCached(r.Context(), domains)
+
+	// A name free as a domain is worth claiming elsewhere too
+	data := multiTLDData{Name: name, Results: results}
+	for _, res := range results {
+		if res.Status == models.StatusAvailable {
+			data.Handles = checkHandles(r.Context(), name)
+			break
+		}
+	}
+
+	render(w, r, "results-multitld.html", data)
+}
 
-	templates.ExecuteTemplate(w, "results-multitld.html", results)
+// multiTLDData is a name's results across TLDs, plus its social handles
+// when any TLD is available
+type multiTLDData struct {
+	Name    string
+	Results []models.DomainResult
+	Handles []models.Handle
 }
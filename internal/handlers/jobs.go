@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/drop"
+	"github.com/berckan/domainhunter/internal/jobs"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/scan"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+var jobQueue = newJobQueue(store)
+
+func newJobQueue(s storage.Store) *jobs.Queue {
+	q := jobs.New(s)
+	q.Handle("short", runShortScan)
+	return q
+}
+
+// StartJobs starts n background workers for queued scans. Call after
+// SetStore so the queue uses the configured store.
+func StartJobs(ctx context.Context, n int) error {
+	jobQueue = newJobQueue(store)
+	return jobQueue.Start(ctx, n)
+}
+
+// runShortScan is the job handler for the short domain scanner. The
+// checkpoint is keyed by job ID, so a retried or requeued job resumes.
+func runShortScan(ctx context.Context, job models.Job) (int64, error) {
+	length, _ := strconv.Atoi(job.Params["length"])
+	prefix := job.Params["prefix"]
+	var domains scan.Concat
+	for _, tier := range shortCandidates(length, prefix) {
+		domains = append(domains, tier)
+	}
+
+	startedAt := time.Now()
+	key := "job:" + strconv.FormatInt(job.ID, 10)
+	res, err := scan.NewRunner(domainChecker, store).RunDomains(ctx, key, domains)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := drop.Track(ctx, store, res.Dropping); err != nil {
+		slog.ErrorContext(ctx, "track drops", "err", err)
+	}
+
+	s := models.Scan{
+		UserID:     job.UserID,
+		Kind:       "short",
+		Params:     "length=" + job.Params["length"] + " prefix=" + prefix,
+		Checked:    domains.Len(),
+		Available:  domainNames(res.Available),
+		Stats:      res.Stats,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+	}
+	if err := store.SaveScan(ctx, &s); err != nil {
+		return 0, err
+	}
+	return s.ID, nil
+}
+
+// JobStatus renders a scan job: the polling partial while it runs, the
+// results once complete
+func JobStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	job, err := jobQueue.Get(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		serverError(w, r, "Could not load job", err)
+		return
+	}
+
+	switch job.State {
+	case models.JobComplete:
+		s, err := store.GetScan(r.Context(), job.ScanID)
+		if err != nil {
+			serverError(w, r, "Could not load scan results", err)
+			return
+		}
+		available := make([]models.DomainResult, len(s.Available))
+		for i, d := range s.Available {
+			available[i] = models.DomainResult{Domain: d, Status: models.StatusAvailable, CheckedAt: s.FinishedAt}
+		}
+		render(w, r, "scan-results.html", struct {
+			Available []models.DomainResult
+			Total     int
+			Checked   int
+		}{
+			Available: available,
+			Total:     len(available),
+			Checked:   s.Checked,
+		})
+	case models.JobFailed:
+		render(w, r, "scan-empty.html", struct {
+			Message string
+		}{
+			Message: "Scan failed: " + job.Error,
+		})
+	default:
+		render(w, r, "scan-job.html", job)
+	}
+}
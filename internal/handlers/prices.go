@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+	"github.com/berckan/domainhunter/internal/pricing"
+)
+
+const (
+	// priceRecheck is how often registrar prices are fetched
+	priceRecheck = 24 * time.Hour
+	// pricesCheckedKey is the setting holding when prices were last fetched
+	pricesCheckedKey = "prices/checked"
+)
+
+var (
+	priceSources []pricing.Source
+	priceTLDs    []string
+)
+
+// SetPriceTracking sets the registrars and TLDs whose prices are tracked
+func SetPriceTracking(sources []pricing.Source, tlds []string) {
+	priceSources = sources
+	priceTLDs = tlds
+}
+
+// checkPrices fetches the tracked TLDs' prices once a day, stores them and
+// alerts on promotions. A registrar that fails is skipped until next time.
+func checkPrices(ctx context.Context, notifiers []notify.Notifier) error {
+	if len(priceSources) == 0 || len(priceTLDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	if v, err := store.GetSetting(ctx, pricesCheckedKey); err == nil {
+		if last, err := time.Parse(time.RFC3339, v); err == nil && now.Sub(last) < priceRecheck {
+			return nil
+		}
+	}
+	if err := store.SetSetting(ctx, pricesCheckedKey, now.Format(time.RFC3339)); err != nil {
+		return err
+	}
+
+	history, err := store.ListPrices(ctx, "")
+	if err != nil {
+		return err
+	}
+	latest := make(map[string]models.Price)
+	for _, p := range history {
+		latest[p.Registrar+"/"+p.TLD] = p
+	}
+
+	var alerts []notify.Alert
+	for _, src := range priceSources {
+		prices, err := src.Prices(ctx, priceTLDs)
+		if err != nil {
+			slog.ErrorContext(ctx, "fetch prices", "source", src.Name(), "err", err)
+		}
+		for _, p := range prices {
+			if msg, ok := pricing.Promotion(latest[p.Registrar+"/"+p.TLD], p); ok {
+				alerts = append(alerts, notify.Alert{Domain: "." + p.TLD, Kind: notify.AlertPrice, Message: msg})
+			}
+		}
+		if err := store.SavePrices(ctx, prices); err != nil {
+			return err
+		}
+	}
+	if len(alerts) > 0 {
+		sendAlert(ctx, notifiers, notify.Report{Title: "Price Alert", Alerts: alerts, Date: now})
+	}
+	return nil
+}
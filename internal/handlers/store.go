@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// saveResults persists fresh results; storage errors are logged, not shown
+// to the user, since the check itself succeeded
+func saveResults(ctx context.Context, results []models.DomainResult) {
+	if err := store.SaveResults(ctx, results); err != nil {
+		slog.ErrorContext(ctx, "store results", "err", err)
+	}
+}
+
+// domainNames extracts the domain names from results
+func domainNames(results []models.DomainResult) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Domain
+	}
+	return names
+}
+
+// PersistTelemetry seeds the checker with stored WHOIS telemetry and then
+// saves a snapshot every interval until ctx is done
+func PersistTelemetry(ctx context.Context, interval time.Duration) {
+	stats, err := store.ListServerStats(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "load telemetry", "err", err)
+	}
+	domainChecker.Telemetry().Load(stats)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := store.SaveServerStats(ctx, domainChecker.Telemetry().Snapshot()); err != nil {
+					slog.ErrorContext(ctx, "save telemetry", "err", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+const (
+	// streamSaveBatch is how many streamed results are saved to the store
+	// at a time
+	streamSaveBatch = 100
+	// maxStreams is how many scans may stream at once; each one holds the
+	// checker for as long as its client keeps the connection open
+	maxStreams = 2
+)
+
+// streams holds a slot for each scan streaming
+var streams = make(chan struct{}, maxStreams)
+
+// ScanStream runs a short-domain scan like ScanShort, but streams every
+// result back as NDJSON the moment it's known instead of queueing a job,
+// saving results to the store along the way. Closing the connection stops
+// the scan. Beyond maxStreams at once, it turns scans away.
+func ScanStream(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.Atoi(r.FormValue("length"))
+	if err != nil || length < 1 || length > 3 {
+		http.Error(w, "Length must be 1, 2, or 3", http.StatusBadRequest)
+		return
+	}
+	prefix := strings.ToLower(strings.TrimSpace(r.FormValue("prefix")))
+	if len(prefix) < length-1 || len(prefix) > length {
+		http.Error(w, "For "+strconv.Itoa(length)+"-char domains, the prefix must be "+strconv.Itoa(length-1)+" or "+strconv.Itoa(length)+" characters", http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case streams <- struct{}{}:
+		defer func() { <-streams }()
+	default:
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Too many scans streaming, try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	domains := shortCandidates(length, prefix)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+
+	batch := make([]models.DomainResult, 0, streamSaveBatch)
+	err = domainChecker.Stream(r.Context(), domains.All(), func(res models.DomainResult) error {
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if batch = append(batch, res); len(batch) == streamSaveBatch {
+			saveResults(r.Context(), batch)
+			batch = batch[:0]
+		}
+		return nil
+	})
+	// Streamed results are complete even if the scan was stopped
+	if len(batch) > 0 {
+		saveResults(context.WithoutCancel(r.Context()), batch)
+	}
+	if err != nil && r.Context().Err() == nil {
+		slog.ErrorContext(r.Context(), "scan stream", "err", err)
+	}
+}
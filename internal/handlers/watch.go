@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/alerting"
+	"github.com/berckan/domainhunter/internal/drop"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+)
+
+// watchTick is how often the watch list is polled for due re-checks
+const watchTick = time.Minute
+
+// WatchDomains re-checks watched domains until ctx is done and alerts
+// through notifiers when one becomes available. Each domain is re-checked
+// every interval, or its own CheckInterval, and more often around an
+// expected drop. Owned domains are tracked for expiry instead; see
+// checkOwned. Taken domains are also monitored for certificate expiry
+// (checkCerts) and for DNS and WHOIS changes (checkDNS, checkWhois), and
+// Certificate Transparency logs for new certificates matching brand
+// keywords (checkCT). Registrar prices are tracked alongside (checkPrices).
+func WatchDomains(ctx context.Context, interval time.Duration, notifiers []notify.Notifier) {
+	go func() {
+		ticker := time.NewTicker(watchTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := checkWatches(ctx, interval, notifiers); err != nil {
+					slog.ErrorContext(ctx, "check watches", "err", err)
+				}
+				if err := checkOwned(ctx, notifiers); err != nil {
+					slog.ErrorContext(ctx, "check owned domains", "err", err)
+				}
+				if err := checkCerts(ctx, notifiers); err != nil {
+					slog.ErrorContext(ctx, "check certificates", "err", err)
+				}
+				if err := checkDNS(ctx, notifiers); err != nil {
+					slog.ErrorContext(ctx, "check dns", "err", err)
+				}
+				if err := checkWhois(ctx, notifiers); err != nil {
+					slog.ErrorContext(ctx, "check whois", "err", err)
+				}
+				if err := checkCT(ctx, notifiers); err != nil {
+					slog.ErrorContext(ctx, "check ct", "err", err)
+				}
+				if err := checkPrices(ctx, notifiers); err != nil {
+					slog.ErrorContext(ctx, "check prices", "err", err)
+				}
+			}
+		}
+	}()
+}
+
+// checkWatches re-checks the watches that are due
+func checkWatches(ctx context.Context, interval time.Duration, notifiers []notify.Notifier) error {
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var due []models.WatchedDomain
+	for _, w := range watches {
+		if !w.Owned && !w.NextCheckAt.After(now) {
+			due = append(due, w)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	domains := make([]string, len(due))
+	for i, w := range due {
+		domains[i] = w.Domain
+	}
+	results, err := domainChecker.CheckBulk(ctx, domains)
+	if err != nil {
+		return err
+	}
+	saveResults(ctx, results)
+
+	var dropped []models.DomainResult
+	var alerts []notify.Alert
+	for i, res := range results {
+		w := due[i]
+		// Errors and domains skipped while their registry is down say
+		// nothing new
+		if res.Status != models.StatusError && res.Status != models.StatusUnknown {
+			if res.Status != w.Status {
+				if w.Status == models.StatusAvailable {
+					// Alert afresh if it drops again
+					if err := (alerting.Gate{Store: store}).Resolve(ctx, w.Domain, alerting.KindAvailable); err != nil {
+						slog.ErrorContext(ctx, "resolve alert", "domain", w.Domain, "err", err)
+					}
+				}
+				if res.Status == models.StatusAvailable {
+					dropped = append(dropped, res)
+				}
+				w.Status = res.Status
+				w.ChangedAt = now
+			}
+			if res.Status == models.StatusAvailable {
+				if a, ok := autoBuy(ctx, &w, now); ok {
+					alerts = append(alerts, a)
+				}
+			}
+			trackDrop(ctx, &w, res.Phase, now)
+			if a, ok := placeBackorder(ctx, &w, now); ok {
+				alerts = append(alerts, a)
+			}
+		}
+		w.NextCheckAt = now.Add(drop.RecheckAfter(w.DropAt, now, cmp.Or(w.CheckInterval, interval)))
+		if err := store.UpdateWatch(ctx, &w); err != nil {
+			slog.ErrorContext(ctx, "update watch", "domain", w.Domain, "err", err)
+		}
+	}
+	if len(dropped) == 0 && len(alerts) == 0 {
+		return nil
+	}
+
+	sendAlert(ctx, notifiers, notify.Report{Title: "Watchlist Alert", Domains: dropped, Alerts: alerts, Date: time.Now()})
+	return nil
+}
+
+// alertRemind is how long before an unacknowledged, unchanged alert is
+// repeated
+var alertRemind = 24 * time.Hour
+
+// SetAlertRemind sets how long before an unacknowledged alert whose state
+// hasn't changed is sent again; zero never repeats it
+func SetAlertRemind(d time.Duration) {
+	alertRemind = d
+}
+
+// sendAlert delivers r through every notifier, logging failures. Alerts
+// already sent, acknowledged or for snoozed domains are left out; if that
+// leaves nothing, nothing is sent.
+func sendAlert(ctx context.Context, notifiers []notify.Notifier, r notify.Report) {
+	r, err := alerting.Gate{Store: store, Remind: alertRemind}.Filter(ctx, r)
+	if err != nil {
+		// A repeat beats a missed alert
+		slog.ErrorContext(ctx, "dedupe alert", "title", r.Title, "err", err)
+	}
+	if len(r.Domains) == 0 && len(r.Alerts) == 0 {
+		return
+	}
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, r); err != nil {
+			slog.ErrorContext(ctx, "send alert", "title", r.Title, "notifier", n.Name(), "err", err)
+		}
+	}
+}
+
+// trackDrop records w's drop phase and, when it enters one, estimates the
+// drop from the WHOIS updated date
+func trackDrop(ctx context.Context, w *models.WatchedDomain, phase string, now time.Time) {
+	if phase == w.Phase && (phase == "" || !w.DropAt.IsZero()) {
+		return
+	}
+	w.Phase, w.DropAt = phase, time.Time{}
+	if phase == "" {
+		return
+	}
+
+	rec, err := domainChecker.Lookup(ctx, w.Domain)
+	if err != nil {
+		slog.WarnContext(ctx, "watch whois lookup", "domain", w.Domain, "err", err)
+	}
+	w.DropAt = drop.Estimate(phase, rec.Updated, now)
+	slog.InfoContext(ctx, "watched domain dropping", "domain", w.Domain, "phase", phase, "drop_at", w.DropAt)
+}
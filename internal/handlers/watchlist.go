@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/drop"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// WatchDomain adds a taken domain to the watch list so the watcher alerts
+// when it drops. The re-check schedule comes from its WHOIS expiry; see
+// drop.Plan, and an optional interval such as "10m" or "168h" sets how
+// often it's re-checked. HTMX requests get a status partial, API clients
+// asking for JSON get the watch.
+func WatchDomain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(r.FormValue("domain")))
+	if domain == "" || !strings.Contains(domain, ".") {
+		http.Error(w, "Domain is required", http.StatusBadRequest)
+		return
+	}
+
+	var every time.Duration
+	if v := r.FormValue("interval"); v != "" {
+		var err error
+		if every, err = time.ParseDuration(v); err != nil || every < watchTick {
+			http.Error(w, "Interval must be a duration of at least 1m", http.StatusBadRequest)
+			return
+		}
+	}
+
+	watches, err := store.ListWatches(r.Context())
+	if err != nil {
+		serverError(w, r, "Could not load watch list", err)
+		return
+	}
+	status := http.StatusOK
+	i := slices.IndexFunc(watches, func(wd models.WatchedDomain) bool { return wd.Domain == domain })
+	watch := models.WatchedDomain{Domain: domain, Status: models.StatusTaken}
+	if i >= 0 {
+		watch = watches[i]
+		if every > 0 && every != watch.CheckInterval {
+			watch.SetCheckInterval(every, time.Now())
+			if err := store.UpdateWatch(r.Context(), &watch); err != nil {
+				serverError(w, r, "Could not update watch", err)
+				return
+			}
+		}
+	} else {
+		watch.CheckInterval = every
+		now := time.Now()
+		if rec, err := domainChecker.Lookup(r.Context(), domain); err != nil {
+			slog.WarnContext(r.Context(), "watch whois lookup", "domain", domain, "err", err)
+			watch.NextCheckAt = now
+		} else {
+			drop.Plan(&watch, rec, now)
+		}
+		if err := store.AddWatch(r.Context(), &watch); err != nil {
+			serverError(w, r, "Could not add watch", err)
+			return
+		}
+		status = http.StatusCreated
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(watch)
+		return
+	}
+	render(w, r, "watch-added.html", struct {
+		models.WatchedDomain
+		Existing bool
+	}{watch, i >= 0})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/notify"
+)
+
+// whoisRecheck is how often taken watched domains' WHOIS is diffed. Owned
+// domains are looked up by checkOwned on its own schedule.
+const whoisRecheck = 24 * time.Hour
+
+const whoisAlertTitle = "WHOIS Change Alert"
+
+// checkWhois looks up taken watched domains that are due and alerts when
+// their registrar, statuses or expiry changed since the last lookup
+func checkWhois(ctx context.Context, notifiers []notify.Notifier) error {
+	watches, err := store.ListWatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var alerts []notify.Alert
+	for _, w := range watches {
+		if w.Owned || w.Status != models.StatusTaken || now.Sub(w.WhoisAt) < whoisRecheck {
+			continue
+		}
+		changes, ok := refreshWhois(ctx, &w, now)
+		if !ok {
+			continue
+		}
+		alerts = append(alerts, changes...)
+		if err := store.UpdateWatch(ctx, &w); err != nil {
+			slog.ErrorContext(ctx, "update watch", "domain", w.Domain, "err", err)
+		}
+	}
+	if len(alerts) > 0 {
+		sendAlert(ctx, notifiers, notify.Report{Title: whoisAlertTitle, Alerts: alerts, Date: now})
+	}
+	return nil
+}
+
+// refreshWhois looks w up, records the record and returns alerts for what
+// changed since the previous one; false if the lookup failed
+func refreshWhois(ctx context.Context, w *models.WatchedDomain, now time.Time) ([]notify.Alert, bool) {
+	rec, err := domainChecker.Lookup(ctx, w.Domain)
+	if err != nil {
+		slog.WarnContext(ctx, "watch whois lookup", "domain", w.Domain, "err", err)
+		return nil, false
+	}
+	var alerts []notify.Alert
+	if !w.WhoisAt.IsZero() {
+		alerts = whoisChanges(w.Domain, w.Whois, rec)
+	}
+	w.Whois, w.WhoisAt = rec, now
+	return alerts, true
+}
+
+// whoisChanges returns an alert for each of registrar, statuses and expiry
+// that differs between the records. Fields missing from the new record are
+// taken as a parse gap, not a change.
+func whoisChanges(domain string, old, cur models.WhoisRecord) []notify.Alert {
+	var alerts []notify.Alert
+	add := func(msg string) {
+		alerts = append(alerts, notify.Alert{Domain: domain, Kind: notify.AlertWhois, Message: msg})
+	}
+
+	if old.Registrar != "" && cur.Registrar != "" && !strings.EqualFold(old.Registrar, cur.Registrar) {
+		add("registrar changed from " + old.Registrar + " to " + cur.Registrar)
+	}
+	if len(cur.Statuses) > 0 {
+		added := statusDiff(cur.Statuses, old)
+		removed := statusDiff(old.Statuses, cur)
+		var parts []string
+		if len(added) > 0 {
+			parts = append(parts, "added "+strings.Join(added, ", "))
+		}
+		if len(removed) > 0 {
+			parts = append(parts, "removed "+strings.Join(removed, ", "))
+		}
+		if len(parts) > 0 {
+			add("status " + strings.Join(parts, "; "))
+		}
+	}
+	if !old.Expires.IsZero() && !cur.Expires.IsZero() && !old.Expires.Equal(cur.Expires) {
+		add("expiry changed from " + old.Expires.Format("Jan 2, 2006") + " to " + cur.Expires.Format("Jan 2, 2006"))
+	}
+	return alerts
+}
+
+// statusDiff returns the statuses that rec doesn't have
+func statusDiff(statuses []string, rec models.WhoisRecord) []string {
+	return slices.DeleteFunc(slices.Clone(statuses), rec.HasStatus)
+}
@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/logging"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// DefaultMaxAttempts is how often a failing job is tried before it fails
+const DefaultMaxAttempts = 3
+
+// pollInterval is how often idle workers look for runnable jobs
+const pollInterval = 2 * time.Second
+
+// Handler runs one job and returns the ID of the scan it produced
+type Handler func(ctx context.Context, job models.Job) (scanID int64, err error)
+
+// Queue is a durable job queue persisted in the store and consumed by a
+// pool of workers. Jobs survive restarts: anything left running by a
+// previous process is put back in the queue on Start.
+type Queue struct {
+	store    storage.Store
+	handlers map[string]Handler
+	wake     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// New creates a queue backed by store
+func New(store storage.Store) *Queue {
+	return &Queue{
+		store:    store,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Handle registers the handler for a job kind. Call before Start.
+func (q *Queue) Handle(kind string, h Handler) {
+	q.handlers[kind] = h
+}
+
+// Enqueue persists a new job and wakes a worker
+func (q *Queue) Enqueue(ctx context.Context, kind string, params map[string]string) (models.Job, error) {
+	if _, ok := q.handlers[kind]; !ok {
+		return models.Job{}, fmt.Errorf("unknown job kind %q", kind)
+	}
+
+	job := models.Job{
+		Kind:        kind,
+		Params:      params,
+		State:       models.JobQueued,
+		MaxAttempts: DefaultMaxAttempts,
+		CreatedAt:   time.Now(),
+	}
+	if err := q.store.SaveJob(ctx, &job); err != nil {
+		return job, err
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Get returns a job by ID
+func (q *Queue) Get(ctx context.Context, id int64) (models.Job, error) {
+	return q.store.GetJob(ctx, id)
+}
+
+// Start requeues jobs interrupted by a previous shutdown and starts n
+// workers. Workers stop when ctx is cancelled; Wait blocks until they have.
+func (q *Queue) Start(ctx context.Context, n int) error {
+	running, err := q.store.ListJobs(ctx, models.JobRunning)
+	if err != nil {
+		return err
+	}
+	for _, job := range running {
+		job.State = models.JobQueued
+		if err := q.store.SaveJob(ctx, &job); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "job requeued after restart", "job", job.ID)
+	}
+
+	for range n {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+	return nil
+}
+
+// Wait blocks until all workers have exited
+func (q *Queue) Wait() {
+	q.wg.Wait()
+}
+
+func (q *Queue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := q.store.ClaimJob(ctx, time.Now())
+		switch {
+		case err == nil:
+			q.run(ctx, job)
+			continue
+		case !errors.Is(err, storage.ErrNotFound):
+			slog.ErrorContext(ctx, "claim job", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Queue) run(ctx context.Context, job models.Job) {
+	ctx = logging.With(ctx, "job", job.ID, "kind", job.Kind)
+	scanID, err := q.handlers[job.Kind](ctx, job)
+
+	// Shutting down: leave the job running so Start requeues it next time
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		job.State = models.JobComplete
+		job.ScanID = scanID
+		job.Error = ""
+		job.FinishedAt = now
+	case job.Attempts < job.MaxAttempts:
+		job.State = models.JobQueued
+		job.Error = err.Error()
+		job.NotBefore = now.Add(backoff(job.Attempts))
+		slog.WarnContext(ctx, "job failed, retrying", "attempt", job.Attempts, "retry_at", job.NotBefore, "err", err)
+	default:
+		job.State = models.JobFailed
+		job.Error = err.Error()
+		job.FinishedAt = now
+		slog.ErrorContext(ctx, "job failed", "attempts", job.Attempts, "err", err)
+	}
+
+	if err := q.store.SaveJob(context.WithoutCancel(ctx), &job); err != nil {
+		slog.ErrorContext(ctx, "save job", "err", err)
+	}
+}
+
+// backoff grows quadratically: 30s, 2m, 4.5m, ...
+func backoff(attempt int) time.Duration {
+	return time.Duration(attempt*attempt) * 30 * time.Second
+}
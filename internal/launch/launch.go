@@ -0,0 +1,160 @@
+// Package launch reads a calendar of new TLD launches, with each TLD's
+// sunrise, landrush and general availability dates.
+package launch
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Launch phases, in order
+const (
+	PhaseSunrise  = "sunrise"
+	PhaseLandrush = "landrush"
+	PhaseGA       = "general availability"
+)
+
+// Launch is one TLD's launch schedule. Dates it skips, such as a landrush
+// some registries don't hold, are zero.
+type Launch struct {
+	TLD      string
+	Sunrise  time.Time
+	Landrush time.Time
+	GA       time.Time
+}
+
+// Event is a launch phase starting
+type Event struct {
+	TLD   string
+	Phase string
+	At    time.Time
+}
+
+// Upcoming returns the phases starting from now until within from now,
+// soonest first
+func Upcoming(launches []Launch, now time.Time, within time.Duration) []Event {
+	var events []Event
+	for _, l := range launches {
+		for _, e := range l.events() {
+			if !e.At.Before(now.Truncate(24*time.Hour)) && e.At.Before(now.Add(within)) {
+				events = append(events, e)
+			}
+		}
+	}
+	slices.SortStableFunc(events, func(a, b Event) int { return a.At.Compare(b.At) })
+	return events
+}
+
+// Opened returns the TLDs that reached general availability in the window
+// ending today, so a run missed on the day itself still catches them
+func Opened(launches []Launch, now time.Time, window time.Duration) []string {
+	var tlds []string
+	for _, l := range launches {
+		if !l.GA.IsZero() && !l.GA.After(now) && now.Sub(l.GA) < window {
+			tlds = append(tlds, l.TLD)
+		}
+	}
+	return tlds
+}
+
+func (l Launch) events() []Event {
+	var events []Event
+	for _, e := range []Event{
+		{l.TLD, PhaseSunrise, l.Sunrise},
+		{l.TLD, PhaseLandrush, l.Landrush},
+		{l.TLD, PhaseGA, l.GA},
+	} {
+		if !e.At.IsZero() {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// client fetches remote calendars
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Load reads a calendar from a file or an http(s) URL; see Parse
+func Load(ctx context.Context, source string) ([]Launch, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return Parse(f)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return Parse(resp.Body)
+}
+
+// Parse reads a CSV calendar with a header row naming its tld, sunrise,
+// landrush and ga columns, in any order. Dates are YYYY-MM-DD and may be
+// blank; other columns are ignored.
+func Parse(r io.Reader) ([]Launch, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	col := make(map[string]int)
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := col["tld"]; !ok {
+		return nil, errors.New("no tld column")
+	}
+
+	var launches []Launch
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := col[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		l := Launch{TLD: strings.TrimPrefix(strings.ToLower(field("tld")), ".")}
+		if l.TLD == "" {
+			continue
+		}
+		for name, t := range map[string]*time.Time{"sunrise": &l.Sunrise, "landrush": &l.Landrush, "ga": &l.GA} {
+			v := field(name)
+			if v == "" {
+				continue
+			}
+			if *t, err = time.Parse(time.DateOnly, v); err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", line, name, err)
+			}
+		}
+		launches = append(launches, l)
+	}
+	return launches, nil
+}
@@ -0,0 +1,102 @@
+// Package logging sets up structured logging with log/slog, and carries
+// correlation IDs in contexts so every line of one scan or job can be
+// picked out of the rest.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LevelTrace is below debug, for lines about every query and retry
+const LevelTrace = slog.LevelDebug - 4
+
+// Setup makes a logger writing to w the default for slog and the standard
+// log package. level is trace, debug, info, warn or error, and format text
+// or json; empty means info and text.
+func Setup(w io.Writer, level, format string) error {
+	var lvl slog.Level
+	switch {
+	case strings.EqualFold(level, "trace"):
+		lvl = LevelTrace
+	case level != "":
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return fmt.Errorf("log level %q: want trace, debug, info, warn or error", level)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: nameTrace}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("log format %q: want text or json", format)
+	}
+	slog.SetDefault(slog.New(contextHandler{h}))
+	return nil
+}
+
+// nameTrace shows LevelTrace as TRACE rather than DEBUG-4
+func nameTrace(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if lvl, ok := a.Value.Any().(slog.Level); ok && lvl == LevelTrace {
+			a.Value = slog.StringValue("TRACE")
+		}
+	}
+	return a
+}
+
+// attrsKey is the context key for the attributes added by With
+type attrsKey struct{}
+
+// With returns a context whose log lines carry args, as key-value pairs or
+// slog.Attrs, after those of ctx
+func With(ctx context.Context, args ...any) context.Context {
+	prev, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	attrs := append(prev[:len(prev):len(prev)], slog.Group("", args...).Value.Group()...)
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+// NewID returns a short random ID for correlating a scan's log lines
+func NewID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// contextHandler adds the attributes of With, and the trace ID when a span
+// is recording, to lines logged with a context
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle implements slog.Handler
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
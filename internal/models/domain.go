@@ -1,30 +1,175 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // DomainStatus represents the availability status of a domain
 type DomainStatus string
 
 const (
-	StatusAvailable   DomainStatus = "available"
-	StatusTaken       DomainStatus = "taken"
-	StatusError       DomainStatus = "error"
-	StatusChecking    DomainStatus = "checking"
+	StatusAvailable DomainStatus = "available"
+	StatusTaken     DomainStatus = "taken"
+	StatusError     DomainStatus = "error"
+	StatusChecking  DomainStatus = "checking"
+	// StatusPremium is a registry premium name a registrar quoted a price for
+	StatusPremium DomainStatus = "premium"
+	// StatusUnknown is a domain that was skipped because its WHOIS server
+	// was down
+	StatusUnknown DomainStatus = "unknown"
+	// StatusUnchecked is a domain a bulk check didn't get to before its
+	// deadline
+	StatusUnchecked DomainStatus = "unchecked"
+)
+
+// Check methods: which lookup decided a result
+const (
+	MethodDNS   = "dns"
+	MethodWHOIS = "whois"
+)
+
+// DNS answers recorded in DomainResult.DNSRcode; other rcodes keep their
+// standard names
+const (
+	RcodeNoError  = "NOERROR"
+	RcodeNXDomain = "NXDOMAIN"
+	RcodeServFail = "SERVFAIL"
+	RcodeRefused  = "REFUSED"
+	// RcodeTimeout is a query the resolver never answered
+	RcodeTimeout = "TIMEOUT"
 )
 
-// DomainResult holds the result of a domain check
+// DomainResult holds the result of a domain check. Error records a failed
+// lookup even when the status fell back to taken. Phase is set for taken
+// domains WHOIS shows on their way to deletion. DNSRcode is the answer the
+// DNS phase got; bulk checks keep it on results WHOIS went on to decide.
 type DomainResult struct {
 	Domain    string       `json:"domain"`
 	Status    DomainStatus `json:"status"`
+	Method    string       `json:"method,omitempty"`
+	Phase     string       `json:"phase,omitempty"`
 	CheckedAt time.Time    `json:"checked_at"`
 	Error     string       `json:"error,omitempty"`
+	DNSRcode  string       `json:"dns_rcode,omitempty"`
+	// Price is a premium name's first-year price in US dollars
+	Price float64 `json:"price,omitempty"`
+	// Enrichment is added to findings before they're reported
+	Enrichment *Enrichment `json:"enrichment,omitempty"`
+}
+
+// Finding reports whether r is worth reporting: available, or a premium
+// name a registrar put a price on
+func (r DomainResult) Finding() bool {
+	return r.Status == StatusAvailable || r.Status == StatusPremium && r.Price > 0
 }
 
-// WatchedDomain represents a domain in the watch list
+// PriceNote is how reports show a premium name's price, e.g. "premium
+// $120.00"; empty for anything else
+func (r DomainResult) PriceNote() string {
+	if r.Status != StatusPremium || r.Price <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("premium $%.2f", r.Price)
+}
+
+// CertInfo is the TLS certificate a domain served when last probed. Error
+// records a failed probe, e.g. a domain with no HTTPS site.
+type CertInfo struct {
+	Issuer    string    `json:"issuer,omitempty"`
+	NotAfter  time.Time `json:"not_after,omitzero"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitzero"`
+}
+
+// DNSSnapshot is a domain's NS, A and MX records at one point, each
+// sorted
+type DNSSnapshot struct {
+	NS        []string  `json:"ns,omitempty"`
+	A         []string  `json:"a,omitempty"`
+	MX        []string  `json:"mx,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitzero"`
+}
+
+// Backorder is a watch's drop-catch order: which provider to use and the
+// most to spend on it, then what happened when it was placed
+type Backorder struct {
+	Provider string    `json:"provider"`
+	MaxPrice float64   `json:"max_price"`
+	OrderID  string    `json:"order_id,omitempty"`
+	Amount   float64   `json:"amount,omitempty"`
+	PlacedAt time.Time `json:"placed_at,omitzero"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Placed reports whether the order went through
+func (b *Backorder) Placed() bool {
+	return !b.PlacedAt.IsZero()
+}
+
+// AutoBuy registers a watched domain as soon as it's confirmed available,
+// if the registrar's price is within MaxPrice; DryRun only quotes. Outcome
+// records the latest attempt. A failed one, with its Error, is tried again
+// on the next check that finds the domain available; AttemptedAt is set
+// once an attempt settles it.
+type AutoBuy struct {
+	Registrar   string    `json:"registrar"`
+	MaxPrice    float64   `json:"max_price"`
+	DryRun      bool      `json:"dry_run,omitempty"`
+	Outcome     string    `json:"outcome,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	AttemptedAt time.Time `json:"attempted_at,omitzero"`
+}
+
+// WatchedDomain represents a domain in the watch list. Owned domains are
+// part of the user's portfolio and are tracked for expiry instead of drops.
 type WatchedDomain struct {
 	ID        int64        `json:"id"`
+	UserID    string       `json:"user_id,omitempty"`
 	Domain    string       `json:"domain"`
 	Status    DomainStatus `json:"status"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
+	Owned     bool         `json:"owned,omitempty"`
+	Registrar string       `json:"registrar,omitempty"`
+	ExpiresAt time.Time    `json:"expires_at,omitzero"`
+	// AutoRenew is the registrar's auto-renew setting for an owned domain,
+	// nil when unknown
+	AutoRenew *bool `json:"auto_renew,omitempty"`
+	// Phase is the drop phase last seen in WHOIS and DropAt when the
+	// domain is expected to become registrable
+	Phase  string    `json:"phase,omitempty"`
+	DropAt time.Time `json:"drop_at,omitzero"`
+	// NextCheckAt is when the watcher re-checks the domain next, and
+	// CheckInterval, if set, how often instead of the watcher's interval;
+	// checks still speed up around an expected drop
+	NextCheckAt   time.Time     `json:"next_check_at,omitzero"`
+	CheckInterval time.Duration `json:"check_interval,omitempty"`
+	// ChangedAt is when Status last changed
+	ChangedAt time.Time `json:"changed_at,omitzero"`
+	// ExpiryAlerted is the last expiry threshold, in days, alerted for
+	// ExpiresAt; a renewal resets it
+	ExpiryAlerted int `json:"expiry_alerted,omitempty"`
+	// Cert is the latest TLS probe and CertAlerted its last alerted
+	// threshold, like ExpiryAlerted
+	Cert        CertInfo `json:"cert,omitzero"`
+	CertAlerted int      `json:"cert_alerted,omitempty"`
+	// DNS is the latest snapshot of the domain's records
+	DNS DNSSnapshot `json:"dns,omitzero"`
+	// Whois is the latest WHOIS record, looked up at WhoisAt
+	Whois   WhoisRecord `json:"whois,omitzero"`
+	WhoisAt time.Time   `json:"whois_at,omitzero"`
+	// Backorder, if set, is placed when the domain enters pending delete
+	Backorder *Backorder `json:"backorder,omitempty"`
+	// AutoBuy, if set, registers the domain once it's available
+	AutoBuy   *AutoBuy  `json:"auto_buy,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetCheckInterval sets how often w is re-checked, zero for the watcher's
+// default, bringing the next check forward if the interval is due sooner
+func (w *WatchedDomain) SetCheckInterval(every time.Duration, now time.Time) {
+	w.CheckInterval = every
+	if next := now.Add(every); every > 0 && w.NextCheckAt.After(next) {
+		w.NextCheckAt = next
+	}
 }
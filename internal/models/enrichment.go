@@ -0,0 +1,180 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Enrichment is what enrichers found out about a finding before it was
+// reported. Each enricher fills its own fields.
+type Enrichment struct {
+	// Trademarks are live marks matching the name exactly
+	Trademarks []Trademark `json:"trademarks,omitempty"`
+	// History is the domain's record in the Wayback Machine
+	History *History `json:"history,omitempty"`
+	// Prior is whether the domain was registered before
+	Prior *Prior `json:"prior,omitempty"`
+	// Blocklists are the DNSBL zones listing the domain
+	Blocklists []string `json:"blocklists,omitempty"`
+	// SEO holds each SEO provider's link metrics
+	SEO []SEOMetrics `json:"seo,omitempty"`
+	// Appraisal is the domain's estimated resale value
+	Appraisal *Appraisal `json:"appraisal,omitempty"`
+	// Handles is whether the name is free as a username on social sites
+	Handles []Handle `json:"handles,omitempty"`
+	// Prices are the TLD's latest prices at each registrar
+	Prices []Price `json:"prices,omitempty"`
+}
+
+// Appraisal is an estimate of what a domain would sell for, in US dollars,
+// and who made it
+type Appraisal struct {
+	Source string  `json:"source"`
+	Value  float64 `json:"value"`
+}
+
+func (a Appraisal) String() string {
+	return fmt.Sprintf("appraised at $%.0f (%s)", a.Value, a.Source)
+}
+
+// SEOMetrics are one provider's link metrics for a domain. Metric names
+// its authority score: Moz DA, Ahrefs DR or Majestic TF, all 0-100.
+type SEOMetrics struct {
+	Source     string  `json:"source"`
+	Metric     string  `json:"metric"`
+	Authority  float64 `json:"authority"`
+	Backlinks  int64   `json:"backlinks"`
+	RefDomains int64   `json:"ref_domains"`
+}
+
+func (m SEOMetrics) String() string {
+	return fmt.Sprintf("%s %s %.0f, %d backlinks from %d domains", m.Source, m.Metric, m.Authority, m.Backlinks, m.RefDomains)
+}
+
+// Prior classifications
+const (
+	PriorFresh   = "fresh"
+	PriorDropped = "dropped"
+)
+
+// Prior is the evidence of a past registration: which sources saw the
+// domain in use and, if they know, when last
+type Prior struct {
+	Class    string    `json:"class"`
+	Evidence []string  `json:"evidence,omitempty"`
+	LastSeen time.Time `json:"last_seen,omitzero"`
+}
+
+func (p Prior) String() string {
+	if p.Class == PriorFresh {
+		return "fresh: never registered as far as we can tell"
+	}
+	s := "dropped"
+	if !p.LastSeen.IsZero() {
+		s += ", last seen " + p.LastSeen.Format("Jan 2006")
+	}
+	return s + " (" + strings.Join(p.Evidence, ", ") + ")"
+}
+
+// History summarises a domain's archived content: the first and last
+// successful captures and how many months have any
+type History struct {
+	First  time.Time `json:"first,omitzero"`
+	Last   time.Time `json:"last,omitzero"`
+	Months int       `json:"months"`
+}
+
+func (h History) String() string {
+	if h.Months == 0 {
+		return "no archived content"
+	}
+	return fmt.Sprintf("archived %s–%s (%d months)", h.First.Format("2006"), h.Last.Format("2006"), h.Months)
+}
+
+// Trademark is a registered or pending mark at one office
+type Trademark struct {
+	Office string `json:"office"`
+	Mark   string `json:"mark"`
+	Number string `json:"number,omitempty"`
+	Owner  string `json:"owner,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+func (t Trademark) String() string {
+	s := fmt.Sprintf("trademark %q at %s", t.Mark, t.Office)
+	if t.Owner != "" {
+		s += " (" + t.Owner + ")"
+	}
+	return s
+}
+
+// Warnings lists reasons to think twice before registering the domain
+func (e *Enrichment) Warnings() []string {
+	if e == nil {
+		return nil
+	}
+	var warnings []string
+	for _, t := range e.Trademarks {
+		warnings = append(warnings, t.String())
+	}
+	for _, zone := range e.Blocklists {
+		warnings = append(warnings, "blocklisted on "+zone)
+	}
+	for _, p := range e.Prices {
+		if p.Trap() {
+			warnings = append(warnings, p.TrapWarning())
+		}
+	}
+	return warnings
+}
+
+// Notes lists what's known about the domain that isn't a warning
+func (e *Enrichment) Notes() []string {
+	if e == nil {
+		return nil
+	}
+	var notes []string
+	if e.Appraisal != nil {
+		notes = append(notes, e.Appraisal.String())
+	}
+	if e.Prior != nil {
+		notes = append(notes, e.Prior.String())
+	}
+	if e.History != nil {
+		notes = append(notes, e.History.String())
+	}
+	for _, m := range e.SEO {
+		notes = append(notes, m.String())
+	}
+	if s := HandleSummary(e.Handles); s != "" {
+		notes = append(notes, s)
+	}
+	if s := priceSummary(e.Prices); s != "" {
+		notes = append(notes, s)
+	}
+	return notes
+}
+
+// priceSummary names the cheapest registrars to register and to renew at,
+// e.g. "first year from $9.68 at porkbun, renewal from $10.28 at namecheap"
+func priceSummary(prices []Price) string {
+	var first, renew *Price
+	for i, p := range prices {
+		if p.Register > 0 && (first == nil || p.Register < first.Register) {
+			first = &prices[i]
+		}
+		if p.RenewalPrice() > 0 && (renew == nil || p.RenewalPrice() < renew.RenewalPrice()) {
+			renew = &prices[i]
+		}
+	}
+	if first == nil || renew == nil {
+		return ""
+	}
+	return fmt.Sprintf("first year from $%.2f at %s, renewal from $%.2f at %s", first.Register, first.Registrar, renew.RenewalPrice(), renew.Registrar)
+}
+
+// Risky reports whether there are any warnings
+func (e *Enrichment) Risky() bool {
+	return len(e.Warnings()) > 0
+}
@@ -0,0 +1,47 @@
+package models
+
+import "strings"
+
+// HandleStatus is whether a username is free on a social site
+type HandleStatus string
+
+const (
+	HandleAvailable HandleStatus = "available"
+	HandleTaken     HandleStatus = "taken"
+	// HandleInvalid means the site doesn't allow the name as a username
+	HandleInvalid HandleStatus = "invalid"
+	// HandleUnknown means the site didn't give a clear answer
+	HandleUnknown HandleStatus = "unknown"
+)
+
+// Handle is a name's availability as a username on one site
+type Handle struct {
+	Site   string       `json:"site"`
+	Status HandleStatus `json:"status"`
+}
+
+// HandleSummary describes handles in one line, e.g. "handle free on
+// GitHub, X; taken on Instagram", leaving out sites that don't allow the
+// name or didn't answer
+func HandleSummary(handles []Handle) string {
+	var free, taken []string
+	for _, h := range handles {
+		switch h.Status {
+		case HandleAvailable:
+			free = append(free, h.Site)
+		case HandleTaken:
+			taken = append(taken, h.Site)
+		}
+	}
+	var parts []string
+	if len(free) > 0 {
+		parts = append(parts, "free on "+strings.Join(free, ", "))
+	}
+	if len(taken) > 0 {
+		parts = append(parts, "taken on "+strings.Join(taken, ", "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "handle " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// JobState is the lifecycle state of a queued job
+type JobState string
+
+const (
+	JobQueued   JobState = "queued"
+	JobRunning  JobState = "running"
+	JobFailed   JobState = "failed"
+	JobComplete JobState = "complete"
+)
+
+// Job is a unit of background work persisted in the job queue
+type Job struct {
+	ID          int64             `json:"id"`
+	UserID      string            `json:"user_id,omitempty"`
+	Kind        string            `json:"kind"`
+	Params      map[string]string `json:"params,omitempty"`
+	State       JobState          `json:"state"`
+	Attempts    int               `json:"attempts"`
+	MaxAttempts int               `json:"max_attempts"`
+	Error       string            `json:"error,omitempty"`
+	ScanID      int64             `json:"scan_id,omitempty"`
+	NotBefore   time.Time         `json:"not_before,omitzero"`
+	CreatedAt   time.Time         `json:"created_at"`
+	StartedAt   time.Time         `json:"started_at,omitzero"`
+	FinishedAt  time.Time         `json:"finished_at,omitzero"`
+}
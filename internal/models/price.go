@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Price is what a registrar charged for a TLD when it was checked, in US
+// dollars for one year. Zero means the registrar didn't quote it.
+type Price struct {
+	Registrar string    `json:"registrar"`
+	TLD       string    `json:"tld"`
+	Register  float64   `json:"register"`
+	Renew     float64   `json:"renew"`
+	Transfer  float64   `json:"transfer"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// A renewal trap is a renewal price at least trapRatio times the
+// first-year price and trapJump dollars more, like .io's cheap first year
+const (
+	trapRatio = 1.5
+	trapJump  = 10.0
+)
+
+// Trap reports whether p's renewal jumps from its first-year price
+func (p Price) Trap() bool {
+	return p.Register > 0 && p.Renew >= p.Register*trapRatio && p.Renew-p.Register >= trapJump
+}
+
+// TrapWarning describes p's renewal trap
+func (p Price) TrapWarning() string {
+	return fmt.Sprintf("renewal trap: .%s renews at $%.2f on %s after $%.2f the first year", p.TLD, p.Renew, p.Registrar, p.Register)
+}
+
+// RenewalPrice is p's renewal price, falling back to the first-year price
+// for registrars that don't quote renewals
+func (p Price) RenewalPrice() float64 {
+	if p.Renew > 0 {
+		return p.Renew
+	}
+	return p.Register
+}
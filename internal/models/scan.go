@@ -0,0 +1,115 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Scan records a completed scan run
+type Scan struct {
+	ID         int64     `json:"id"`
+	UserID     string    `json:"user_id,omitempty"`
+	Kind       string    `json:"kind"`
+	Params     string    `json:"params,omitempty"`
+	Checked    int       `json:"checked"`
+	Available  []string  `json:"available"`
+	Stats      ScanStats `json:"stats,omitzero"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// ScanStats summarizes how a scan's domains were checked
+type ScanStats struct {
+	Checked   int `json:"checked"`
+	Available int `json:"available"`
+	Errors    int `json:"errors"`
+	// ByDNS were settled by DNS alone; ByWHOIS needed a WHOIS query
+	ByDNS   int `json:"by_dns"`
+	ByWHOIS int `json:"by_whois"`
+	// Unchecked were left when the scan was cut short, making it partial;
+	// they aren't counted in Checked
+	Unchecked int                 `json:"unchecked,omitempty"`
+	TLDs      map[string]TLDStats `json:"tlds,omitempty"`
+}
+
+// TLDStats are the ScanStats counts for one TLD
+type TLDStats struct {
+	Checked   int `json:"checked"`
+	Available int `json:"available"`
+	Errors    int `json:"errors"`
+}
+
+// Add counts results
+func (s *ScanStats) Add(results []DomainResult) {
+	if s.TLDs == nil {
+		s.TLDs = make(map[string]TLDStats)
+	}
+	for _, r := range results {
+		if r.Status == StatusUnchecked {
+			s.Unchecked++
+			continue
+		}
+		tld := r.Domain[strings.LastIndex(r.Domain, ".")+1:]
+		t := s.TLDs[tld]
+		s.Checked++
+		t.Checked++
+		if r.Status == StatusAvailable {
+			s.Available++
+			t.Available++
+		}
+		if r.Error != "" || r.Status == StatusError {
+			s.Errors++
+			t.Errors++
+		}
+		if r.Method == MethodWHOIS {
+			s.ByWHOIS++
+		} else {
+			s.ByDNS++
+		}
+		s.TLDs[tld] = t
+	}
+}
+
+// Merge adds o's counts to s
+func (s *ScanStats) Merge(o ScanStats) {
+	if s.TLDs == nil {
+		s.TLDs = make(map[string]TLDStats)
+	}
+	s.Checked += o.Checked
+	s.Available += o.Available
+	s.Errors += o.Errors
+	s.ByDNS += o.ByDNS
+	s.ByWHOIS += o.ByWHOIS
+	s.Unchecked += o.Unchecked
+	for tld, o := range o.TLDs {
+		t := s.TLDs[tld]
+		t.Checked += o.Checked
+		t.Available += o.Available
+		t.Errors += o.Errors
+		s.TLDs[tld] = t
+	}
+}
+
+// ReportedFinding records an available domain that was already sent out
+type ReportedFinding struct {
+	Domain          string    `json:"domain"`
+	FirstReportedAt time.Time `json:"first_reported_at"`
+	LastReportedAt  time.Time `json:"last_reported_at"`
+}
+
+// Checkpoint is the persisted progress of an unfinished scan. Done counts
+// domains from the start of the (deterministically ordered) candidate list.
+type Checkpoint struct {
+	Key         string         `json:"key"`
+	Fingerprint string         `json:"fingerprint"`
+	Total       int            `json:"total"`
+	Done        int            `json:"done"`
+	Available   []DomainResult `json:"available"`
+	Dropping    []DomainResult `json:"dropping,omitempty"`
+	Stats       ScanStats      `json:"stats"`
+	StartedAt   time.Time      `json:"started_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	// Deferred are domains skipped while their WHOIS server was down, to
+	// be checked again once the rest is done
+	Deferred []string `json:"deferred,omitempty"`
+}
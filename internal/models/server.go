@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ServerStats aggregates WHOIS telemetry for a single registry server
+type ServerStats struct {
+	Server      string    `json:"server"`
+	Queries     int64     `json:"queries"`
+	Errors      int64     `json:"errors"`
+	Timeouts    int64     `json:"timeouts"`
+	Refused     int64     `json:"refused"`
+	RateLimited int64     `json:"rate_limited"`
+	AvgLatency  float64   `json:"avg_latency_ms"` // exponentially weighted
+	ErrorRate   float64   `json:"error_rate"`     // exponentially weighted, 0-1
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+	LastLimitAt time.Time `json:"last_limit_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Drop phases: EPP statuses of a deleted domain on its way back to the
+// pool. Redemption lasts about 30 days, pending delete about 5.
+const (
+	PhaseRedemption    = "redemptionPeriod"
+	PhasePendingDelete = "pendingDelete"
+)
+
+// WhoisRecord is the structured part of a WHOIS response
+type WhoisRecord struct {
+	Registrar string `json:"registrar,omitempty"`
+	// Statuses are EPP status codes, e.g. clientTransferProhibited
+	Statuses    []string  `json:"statuses,omitempty"`
+	NameServers []string  `json:"name_servers,omitempty"`
+	Created     time.Time `json:"created,omitzero"`
+	Updated     time.Time `json:"updated,omitzero"`
+	Expires     time.Time `json:"expires,omitzero"`
+}
+
+// HasStatus reports whether the record lists status, ignoring case
+func (r WhoisRecord) HasStatus(status string) bool {
+	for _, s := range r.Statuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// DropPhase returns the record's drop phase, or "" if it isn't being deleted
+func (r WhoisRecord) DropPhase() string {
+	switch {
+	case r.HasStatus(PhasePendingDelete):
+		return PhasePendingDelete
+	case r.HasStatus(PhaseRedemption):
+		return PhaseRedemption
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Discord embed limits. A message's embeds may total 6000 characters; one
+// embed per message keeps well under that with room for the title.
+const (
+	discordFieldLimit  = 1024
+	discordMaxFields   = 25
+	discordEmbedBudget = 5500
+	discordMaxMessages = 10
+	discordColor       = 0x22c55e
+)
+
+// Discord posts reports to a channel through a webhook
+type Discord struct {
+	WebhookURL string
+	client     *http.Client
+}
+
+// NewDiscord creates a Discord notifier
+func NewDiscord(webhookURL string) *Discord {
+	return &Discord{
+		WebhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Notifier
+func (n *Discord) Name() string { return "discord" }
+
+// Notify posts the findings as embeds, one message per embed
+func (n *Discord) Notify(ctx context.Context, r Report) error {
+	for _, e := range discordEmbeds(r) {
+		msg := map[string]any{"embeds": []discordEmbed{e}}
+		if err := withRetry(ctx, func() error { return n.post(ctx, msg) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+}
+
+type discordField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	count int
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// discordEmbeds lays r out as embeds with one field per TLD, splitting
+// large TLDs across fields. Past discordMaxMessages the rest is summarized
+// in the last embed's footer.
+func discordEmbeds(r Report) []discordEmbed {
+	first := discordEmbed{
+		Title:       "🎯 " + r.Title,
+		Description: headline(r) + " · " + r.Date.Format("January 2, 2006"),
+		Color:       discordColor,
+	}
+	for _, a := range r.Alerts {
+		line := "\n🔔 `" + a.Domain + "` " + a.Message
+		// Embed descriptions are limited to 4096 characters
+		if len(first.Description)+len(line) > 4000 {
+			first.Description += "\n…"
+			break
+		}
+		first.Description += line
+	}
+	embeds := []discordEmbed{first}
+	size := len(first.Title) + len(first.Description)
+
+	shown := 0
+	for _, f := range discordFields(GroupByTLD(r.Domains)) {
+		cur := &embeds[len(embeds)-1]
+		if len(cur.Fields) == discordMaxFields || size+len(f.Name)+len(f.Value) > discordEmbedBudget {
+			if len(embeds) == discordMaxMessages {
+				break
+			}
+			embeds = append(embeds, discordEmbed{Color: discordColor})
+			cur = &embeds[len(embeds)-1]
+			size = 0
+		}
+		cur.Fields = append(cur.Fields, f)
+		size += len(f.Name) + len(f.Value)
+		shown += f.count
+	}
+
+	if rest := len(r.Domains) - shown; rest > 0 {
+		embeds[len(embeds)-1].Footer = &discordFooter{Text: "…and " + strconv.Itoa(rest) + " more"}
+	}
+	return embeds
+}
+
+// discordFields renders each TLD group as one or more fields
+func discordFields(groups []TLDGroup) []discordField {
+	var fields []discordField
+	for _, g := range groups {
+		name := fmt.Sprintf(".%s (%d)", g.TLD, len(g.Domains))
+		var b strings.Builder
+		count := 0
+		for _, d := range g.Domains {
+			item := "`" + d.Domain + "`" + riskMark(d) + " "
+			if b.Len()+len(item) > discordFieldLimit {
+				fields = append(fields, discordField{Name: name, Value: strings.TrimSpace(b.String()), count: count})
+				name = fmt.Sprintf(".%s (cont.)", g.TLD)
+				b.Reset()
+				count = 0
+			}
+			b.WriteString(item)
+			count++
+		}
+		fields = append(fields, discordField{Name: name, Value: strings.TrimSpace(b.String()), count: count})
+	}
+	return fields
+}
+
+func (n *Discord) post(ctx context.Context, msg map[string]any) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return statusError("discord", resp)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/export"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/score"
+)
+
+// email is one rendered report message
+type email struct {
+	To          string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []attachment
+}
+
+// attachment is a file sent along with an email
+type attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// emailSubject is the subject line for a report of n domains, or of
+// alerts alone
+func emailSubject(r Report, n int) string {
+	if n == 0 && len(r.Alerts) > 0 {
+		return fmt.Sprintf("🔔 %s - %s", r.Title, r.Date.Format("Jan 2"))
+	}
+	return fmt.Sprintf("🎯 %d domains available - %s", n, r.Date.Format("Jan 2"))
+}
+
+//go:embed templates/report.html
+var defaultTemplate string
+
+// textTemplate renders the plain-text alternative to the HTML email
+//
+//go:embed templates/report.txt
+var textTemplateSource string
+
+var textTemplate = texttemplate.Must(texttemplate.New("report.txt").Parse(textTemplateSource))
+
+// EmailData is what a report email template renders
+type EmailData struct {
+	// Title is the report kind, e.g. "Daily Report" or "Weekly Recap"
+	Title string
+	// Date is when the report was generated
+	Date time.Time
+	// Total is the number of domains in this email
+	Total int
+	// Domains are this recipient's results in report order
+	Domains []models.DomainResult
+	// Groups are the same results grouped by TLD, alphabetically, each
+	// sorted by score
+	Groups []TLDGroup
+	// Digest is the rest of a weekly digest; nil for other reports
+	Digest *Digest
+	// Alerts are this recipient's notices about watched domains
+	Alerts []Alert
+	// Registrars are where each domain can be bought; see Registrar.Link
+	Registrars []Registrar
+	// Top are the topPicks best-scored results across all TLDs, set only
+	// when there are more results than that
+	Top []models.DomainResult
+	// Summary describes the whole scan, not just this recipient's part;
+	// nil for reports that don't come from a scan
+	Summary *Summary
+}
+
+// ParseTemplate parses the report email template at path, or the built-in
+// one when path is empty
+func ParseTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return template.New("report").Parse(defaultTemplate)
+	}
+	return template.ParseFiles(path)
+}
+
+// topPicks is how many results the email highlights above the TLD groups
+const topPicks = 10
+
+// emailData is the template data for domains from r
+func emailData(r Report, domains []models.DomainResult, alerts []Alert, registrars []Registrar) EmailData {
+	data := EmailData{
+		Title:      r.Title,
+		Date:       r.Date,
+		Total:      len(domains),
+		Domains:    domains,
+		Groups:     GroupByTLD(domains),
+		Summary:    r.Summary,
+		Digest:     r.Digest,
+		Alerts:     alerts,
+		Registrars: registrars,
+	}
+	if len(domains) > topPicks {
+		data.Top = score.Top(domains, topPicks)
+	}
+	return data
+}
+
+// renderHTML builds the report email body
+func renderHTML(tmpl *template.Template, data EmailData) (string, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, data)
+	return buf.String(), err
+}
+
+// renderText builds the plain-text alternative body, with CRLF line
+// endings as mail requires
+func renderText(data EmailData) (string, error) {
+	var buf bytes.Buffer
+	err := textTemplate.Execute(&buf, data)
+	return strings.ReplaceAll(buf.String(), "\n", "\r\n"), err
+}
+
+// emailEach sends each recipient the part of r that passes their filter,
+// rendered with the template at tmplPath (built-in when empty), linking
+// each domain to registrars, and with the same results attached as CSV,
+// continuing past failures and returning them as a *PartialError
+func emailEach(ctx context.Context, recipients []Recipient, r Report, tmplPath string, registrars []Registrar, send func(email) error) error {
+	tmpl, err := ParseTemplate(tmplPath)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var failed []string
+	for _, rcpt := range recipients {
+		if r.Recipients != nil && !slices.Contains(r.Recipients, rcpt.Email) {
+			continue
+		}
+		// Recipients with nothing to see are skipped, unless the summary
+		// has failures or unchecked domains to warn about or it's a digest
+		theirs, alerts := rcpt.Filter(r.Domains), rcpt.FilterAlerts(r.Alerts)
+		if len(theirs) == 0 && len(alerts) == 0 && r.Digest == nil && (r.Summary == nil || r.Summary.Errors == 0 && r.Summary.Unchecked == 0) {
+			continue
+		}
+		data := emailData(r, theirs, alerts, registrars)
+		html, err := renderHTML(tmpl, data)
+		if err != nil {
+			return err
+		}
+		text, err := renderText(data)
+		if err != nil {
+			return err
+		}
+		msg := email{
+			To:      rcpt.Email,
+			Subject: emailSubject(r, len(theirs)),
+			HTML:    html,
+			Text:    text,
+		}
+		if len(theirs) > 0 {
+			msg.Attachments = []attachment{{
+				Filename:    "domainhunter-" + r.Date.Format("2006-01-02") + ".csv",
+				ContentType: "text/csv",
+				Content:     resultsCSV(theirs),
+			}}
+		}
+		if err := withRetry(ctx, func() error { return send(msg) }); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rcpt.Email, err))
+			failed = append(failed, rcpt.Email)
+		}
+	}
+	if len(failed) > 0 {
+		return &PartialError{Failed: failed, Err: errors.Join(errs...)}
+	}
+	return nil
+}
+
+// resultsCSV renders results with one row per domain
+func resultsCSV(results []models.DomainResult) []byte {
+	var buf bytes.Buffer
+	export.Write(&buf, "csv", results) // writes to memory can't fail
+	return buf.Bytes()
+}
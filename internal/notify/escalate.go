@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/score"
+)
+
+// Rule picks out findings worth sending right away, e.g. two-character
+// .com names, and the channels to send them through. Conditions left zero
+// match everything.
+type Rule struct {
+	Name string `yaml:"name"`
+	// MinLength and MaxLength bound the length of the name before the TLD
+	MinLength int      `yaml:"min_length"`
+	MaxLength int      `yaml:"max_length"`
+	TLDs      []string `yaml:"tlds"`
+	// Pattern is a regular expression the name before the TLD must match
+	Pattern  string `yaml:"pattern"`
+	MinScore int    `yaml:"min_score"`
+	// Channels are notifier names, such as telegram or webhook; email
+	// stands for resend and smtp
+	Channels []string `yaml:"channels"`
+}
+
+// Match reports whether r meets every condition of the rule. An invalid
+// Pattern matches nothing.
+func (rule Rule) Match(r models.DomainResult) bool {
+	dot := strings.LastIndex(r.Domain, ".")
+	if dot < 0 {
+		return false
+	}
+	label, tld := r.Domain[:dot], r.Domain[dot+1:]
+	n := len([]rune(label))
+	if n < rule.MinLength || (rule.MaxLength > 0 && n > rule.MaxLength) {
+		return false
+	}
+	if len(rule.TLDs) > 0 && !slices.ContainsFunc(rule.TLDs, func(t string) bool {
+		return strings.TrimPrefix(strings.ToLower(t), ".") == tld
+	}) {
+		return false
+	}
+	if rule.Pattern != "" {
+		if ok, err := regexp.MatchString(rule.Pattern, label); err != nil || !ok {
+			return false
+		}
+	}
+	return rule.MinScore == 0 || score.Score(r.Domain) >= rule.MinScore
+}
+
+// Escalation is the findings one rule picked out
+type Escalation struct {
+	Rule    Rule
+	Domains []models.DomainResult
+}
+
+// Escalate splits results into those each rule picks out, in rule order,
+// and the rest. A result goes to the first rule it matches.
+func Escalate(rules []Rule, results []models.DomainResult) ([]Escalation, []models.DomainResult) {
+	if len(rules) == 0 {
+		return nil, results
+	}
+	picked := make([][]models.DomainResult, len(rules))
+	var rest []models.DomainResult
+	for _, r := range results {
+		i := slices.IndexFunc(rules, func(rule Rule) bool { return rule.Match(r) })
+		if i < 0 {
+			rest = append(rest, r)
+			continue
+		}
+		picked[i] = append(picked[i], r)
+	}
+
+	var escalations []Escalation
+	for i, domains := range picked {
+		if len(domains) > 0 {
+			escalations = append(escalations, Escalation{Rule: rules[i], Domains: domains})
+		}
+	}
+	return escalations, rest
+}
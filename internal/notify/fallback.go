@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/export"
+)
+
+// Fallback tries its notifiers in order until one delivers the report
+type Fallback []Notifier
+
+// Name implements Notifier, e.g. "resend→smtp→file"
+func (f Fallback) Name() string {
+	names := make([]string, len(f))
+	for i, n := range f {
+		names[i] = n.Name()
+	}
+	return strings.Join(names, "→")
+}
+
+// Notify delivers r through the first notifier that succeeds, returning
+// every failure if none does. When an email notifier reaches some
+// recipients but not others, only the others are handed on.
+func (f Fallback) Notify(ctx context.Context, r Report) error {
+	var errs []error
+	for _, n := range f {
+		err := n.Notify(ctx, r)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+		var pe *PartialError
+		if errors.As(err, &pe) {
+			r.Recipients = pe.Failed
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PartialError is a delivery that failed for the recipients in Failed and
+// may have reached the rest
+type PartialError struct {
+	Failed []string
+	Err    error
+}
+
+func (e *PartialError) Error() string { return e.Err.Error() }
+
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// File writes each report as a JSON file in Dir, for when nothing else
+// can deliver it
+type File struct {
+	Dir string
+}
+
+// Name implements Notifier
+func (n *File) Name() string { return "file" }
+
+// Notify writes r to Dir/report-<timestamp>.json
+func (n *File) Notify(_ context.Context, r Report) error {
+	_, err := SaveReport(n.Dir, r)
+	return err
+}
+
+// SaveReport writes r's domains, or its alerts for a report of alerts only,
+// as JSON in dir and returns the file path
+func SaveReport(dir string, r Report) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "report-"+r.Date.Format("20060102-150405")+".json")
+	if len(r.Domains) == 0 && len(r.Alerts) > 0 {
+		data, err := json.MarshalIndent(r.Alerts, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return path, os.WriteFile(path, append(data, '\n'), 0o644)
+	}
+	return path, export.WriteFile(path, "json", r.Domains)
+}
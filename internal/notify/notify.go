@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/score"
+)
+
+// Report is a set of findings to deliver
+type Report struct {
+	Title   string
+	Domains []models.DomainResult
+	Date    time.Time
+	// Summary, if set, describes the scan that produced the report
+	Summary *Summary
+	// Digest, if set, makes this a weekly digest: Domains are the week's
+	// new findings and Digest holds what else changed
+	Digest *Digest
+	// Alerts are notices about watched domains; reports of alerts usually
+	// have no Domains
+	Alerts []Alert
+	// Recipients, if set, limits email notifiers to these addresses, as
+	// when a fallback re-sends what an earlier notifier couldn't deliver
+	Recipients []string
+}
+
+// Alert kinds
+const (
+	AlertExpiry    = "expiry"
+	AlertCert      = "cert"
+	AlertDNS       = "dns"
+	AlertWhois     = "whois"
+	AlertBackorder = "backorder"
+	AlertAutoBuy   = "autobuy"
+	AlertCT        = "ct"
+	AlertPrice     = "price"
+)
+
+// Alert is a notice about one watched domain, e.g. an upcoming expiry
+type Alert struct {
+	Domain  string `json:"domain"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// headline counts what r carries, e.g. "3 available domains, 1 alert"
+func headline(r Report) string {
+	var parts []string
+	if len(r.Domains) > 0 || len(r.Alerts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d available domains", len(r.Domains)))
+	}
+	switch len(r.Alerts) {
+	case 0:
+	case 1:
+		parts = append(parts, "1 alert")
+	default:
+		parts = append(parts, fmt.Sprintf("%d alerts", len(r.Alerts)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Digest is the week's changes besides new findings
+type Digest struct {
+	Since time.Time
+	// Taken were found available during the week but no longer are
+	Taken []string
+	// Watches are watched domains whose status changed
+	Watches []models.WatchedDomain
+}
+
+// Empty reports whether d has nothing to tell; a nil Digest is empty
+func (d *Digest) Empty() bool {
+	return d == nil || len(d.Taken) == 0 && len(d.Watches) == 0
+}
+
+// Summary is how a scan went, so a report of zero domains can be told
+// apart from a scan that mostly failed
+type Summary struct {
+	models.ScanStats
+	Duration time.Duration
+}
+
+// Notifier delivers reports over one channel
+type Notifier interface {
+	// Name identifies the channel in logs, e.g. "resend"
+	Name() string
+	// Notify delivers r. Channels with several recipients try them all and
+	// return the failures joined.
+	Notify(ctx context.Context, r Report) error
+}
+
+// Recipient is an address with an optional TLD filter
+type Recipient struct {
+	Email string   `yaml:"email"`
+	TLDs  []string `yaml:"tlds"`
+}
+
+// Wants reports whether domain passes the recipient's TLD filter
+func (r Recipient) Wants(domain string) bool {
+	if len(r.TLDs) == 0 {
+		return true
+	}
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	return slices.ContainsFunc(r.TLDs, func(t string) bool {
+		return strings.TrimPrefix(strings.ToLower(t), ".") == tld
+	})
+}
+
+// FilterAlerts returns the alerts the recipient wants
+func (r Recipient) FilterAlerts(alerts []Alert) []Alert {
+	var out []Alert
+	for _, a := range alerts {
+		if r.Wants(a.Domain) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Filter returns the results the recipient wants
+func (r Recipient) Filter(results []models.DomainResult) []models.DomainResult {
+	var out []models.DomainResult
+	for _, res := range results {
+		if r.Wants(res.Domain) {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// ParseRecipients splits a comma-separated address list into unfiltered
+// recipients and appends extra
+func ParseRecipients(list string, extra []Recipient) []Recipient {
+	var all []Recipient
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			all = append(all, Recipient{Email: addr})
+		}
+	}
+	for _, rc := range extra {
+		if rc.Email != "" {
+			all = append(all, rc)
+		}
+	}
+	return all
+}
+
+// TLDGroup is the report's results under one TLD
+type TLDGroup struct {
+	TLD     string
+	Domains []models.DomainResult
+}
+
+// GroupByTLD groups results by TLD, in alphabetical TLD order, with each
+// group's domains best-scored first
+func GroupByTLD(results []models.DomainResult) []TLDGroup {
+	var groups []TLDGroup
+	index := make(map[string]int)
+	for _, r := range results {
+		i := strings.LastIndex(r.Domain, ".")
+		if i < 0 {
+			continue
+		}
+		tld := r.Domain[i+1:]
+		g, ok := index[tld]
+		if !ok {
+			g = len(groups)
+			index[tld] = g
+			groups = append(groups, TLDGroup{TLD: tld})
+		}
+		groups[g].Domains = append(groups[g].Domains, r)
+	}
+	slices.SortFunc(groups, func(a, b TLDGroup) int { return cmp.Compare(a.TLD, b.TLD) })
+	for _, g := range groups {
+		score.Sort(g.Domains)
+	}
+	return groups
+}
+
+// riskMark flags a finding enrichment found reasons to avoid, for channels
+// too terse to list them, after a premium name's price
+func riskMark(d models.DomainResult) string {
+	mark := ""
+	if p := d.PriceNote(); p != "" {
+		mark = " (" + p + ")"
+	}
+	if d.Enrichment.Risky() {
+		mark += " ⚠️"
+	}
+	return mark
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Registrar is where a found domain can be bought. URL contains {domain},
+// replaced by the query-escaped domain name.
+type Registrar struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+// DefaultRegistrars are linked from report emails unless configured
+var DefaultRegistrars = []Registrar{
+	{Name: "Namecheap", URL: "https://www.namecheap.com/domains/registration/results/?domain={domain}"},
+	{Name: "Porkbun", URL: "https://porkbun.com/checkout/search?q={domain}"},
+	{Name: "Cloudflare", URL: "https://domains.cloudflare.com/?domain={domain}"},
+}
+
+// Link returns the registrar's purchase URL for domain
+func (r Registrar) Link(domain string) string {
+	return strings.ReplaceAll(r.URL, "{domain}", url.QueryEscape(domain))
+}
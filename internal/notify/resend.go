@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Resend sends report emails through the Resend API
+type Resend struct {
+	APIKey     string
+	From       string
+	Recipients []Recipient
+	// Template is an email template file; empty uses the built-in one
+	Template string
+	// Registrars are linked from each domain in the email
+	Registrars []Registrar
+	client     *http.Client
+}
+
+// NewResend creates a Resend notifier
+func NewResend(apiKey, from string, recipients []Recipient) *Resend {
+	return &Resend{
+		APIKey:     apiKey,
+		From:       from,
+		Recipients: recipients,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Notifier
+func (n *Resend) Name() string { return "resend" }
+
+// Notify emails each recipient their filtered findings
+func (n *Resend) Notify(ctx context.Context, r Report) error {
+	return emailEach(ctx, n.Recipients, r, n.Template, n.Registrars, func(m email) error {
+		return n.send(ctx, m)
+	})
+}
+
+func (n *Resend) send(ctx context.Context, m email) error {
+	attachments := make([]map[string]string, len(m.Attachments))
+	for i, a := range m.Attachments {
+		attachments[i] = map[string]string{
+			"filename": a.Filename,
+			"content":  base64.StdEncoding.EncodeToString(a.Content),
+		}
+	}
+
+	// Resend API payload
+	payload := map[string]interface{}{
+		"from":        n.From,
+		"to":          []string{m.To},
+		"subject":     m.Subject,
+		"html":        m.HTML,
+		"text":        m.Text,
+		"attachments": attachments,
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.resend.com/emails", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+n.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return statusError("resend API", resp)
+	}
+
+	return nil
+}
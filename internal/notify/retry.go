@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// Delivery retry policy: attempts per message and the first backoff, which
+// doubles each time
+var (
+	retryAttempts = 3
+	retryBackoff  = 2 * time.Second
+)
+
+// StatusError is an unexpected HTTP status from a delivery API
+type StatusError struct {
+	Service string
+	Code    int
+	// RetryAfter is how long the service asked to be left alone, from a
+	// Retry-After header or the like; zero if it didn't say
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.Service, e.Code)
+}
+
+// statusError makes the StatusError for resp, with its Retry-After
+func statusError(service string, resp *http.Response) *StatusError {
+	return &StatusError{Service: service, Code: resp.StatusCode, RetryAfter: retryAfter(resp.Header)}
+}
+
+// retryAfter parses a Retry-After header, in seconds or as a date
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// maxRetryAfter is the longest a service may ask a delivery to wait; one
+// that wants longer is given up on rather than holding the run
+const maxRetryAfter = time.Minute
+
+// permanentError is a rejection that repeating won't change, such as an
+// unknown channel or a bad password
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+func (e permanentError) Unwrap() error { return e.err }
+
+// permanent marks err as not worth retrying
+func permanent(err error) error {
+	return permanentError{err}
+}
+
+// retryable reports whether a failed delivery may succeed if repeated:
+// network errors, rate limits, server errors and SMTP 4xx replies, but not
+// rejections such as a bad API key, an SMTP 5xx reply or an API that
+// answered ok: false
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.As(err, new(permanentError)) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return (se.Code == http.StatusTooManyRequests || se.Code >= 500) && se.RetryAfter <= maxRetryAfter
+	}
+	var te *textproto.Error
+	if errors.As(err, &te) {
+		return te.Code < 500
+	}
+	return true
+}
+
+// withRetry runs one delivery, repeating transient failures with
+// exponential backoff, or after as long as the service asked. Retries
+// happen per message so recipients that already got the report don't get
+// it twice.
+func withRetry(ctx context.Context, send func() error) error {
+	wait := retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := send()
+		if err == nil || attempt == retryAttempts || !retryable(err) {
+			return err
+		}
+		pause := wait
+		var se *StatusError
+		if errors.As(err, &se) && se.RetryAfter > 0 {
+			pause = se.RetryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(pause):
+		}
+		wait *= 2
+	}
+}
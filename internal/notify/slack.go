@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Slack message limits: section text is capped at 3000 characters and a
+// message at 50 blocks
+const (
+	slackSectionLimit = 3000
+	slackMaxBlocks    = 50
+)
+
+// Slack posts reports to a channel, either through an incoming webhook or
+// with a bot token via chat.postMessage. The webhook wins when both are set.
+type Slack struct {
+	WebhookURL string
+	Token      string
+	Channel    string
+	client     *http.Client
+}
+
+// NewSlack creates a Slack notifier
+func NewSlack(webhookURL, token, channel string) *Slack {
+	return &Slack{
+		WebhookURL: webhookURL,
+		Token:      token,
+		Channel:    channel,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Notifier
+func (n *Slack) Name() string { return "slack" }
+
+// Notify posts a summary of r grouped by TLD
+func (n *Slack) Notify(ctx context.Context, r Report) error {
+	msg := slackMessage(r)
+	if n.WebhookURL != "" {
+		return withRetry(ctx, func() error { return n.post(ctx, n.WebhookURL, "", msg) })
+	}
+	msg["channel"] = n.Channel
+	return withRetry(ctx, func() error { return n.post(ctx, "https://slack.com/api/chat.postMessage", n.Token, msg) })
+}
+
+// slackMessage formats r as Block Kit sections, one per TLD. The plain
+// text doubles as the notification preview.
+func slackMessage(r Report) map[string]any {
+	summary := fmt.Sprintf("🎯 *%s* — %s (%s)", r.Title, headline(r), r.Date.Format("Jan 2"))
+	blocks := []map[string]any{slackSection(summary)}
+	if len(r.Alerts) > 0 {
+		blocks = append(blocks, slackSection(slackAlerts(r.Alerts)))
+	}
+
+	groups := GroupByTLD(r.Domains)
+	for i, g := range groups {
+		if len(blocks) == slackMaxBlocks-1 && i < len(groups)-1 {
+			rest := 0
+			for _, g := range groups[i:] {
+				rest += len(g.Domains)
+			}
+			blocks = append(blocks, slackSection(fmt.Sprintf("…and %d more across %d TLDs", rest, len(groups)-i)))
+			break
+		}
+		blocks = append(blocks, slackSection(slackGroup(g)))
+	}
+
+	return map[string]any{"text": summary, "blocks": blocks}
+}
+
+// slackGroup renders one TLD's domains, truncated to fit a section
+func slackGroup(g TLDGroup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*.%s* (%d)\n", g.TLD, len(g.Domains))
+	for i, d := range g.Domains {
+		item := "`" + d.Domain + "`" + riskMark(d) + "  "
+		// Leave room for the "…and N more" tail
+		if b.Len()+len(item) > slackSectionLimit-32 {
+			fmt.Fprintf(&b, "…and %d more", len(g.Domains)-i)
+			break
+		}
+		b.WriteString(item)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// slackAlerts renders alerts one per line, truncated to fit a section
+func slackAlerts(alerts []Alert) string {
+	var b strings.Builder
+	for i, a := range alerts {
+		line := "🔔 `" + a.Domain + "` " + a.Message + "\n"
+		if b.Len()+len(line) > slackSectionLimit-32 {
+			fmt.Fprintf(&b, "…and %d more", len(alerts)-i)
+			break
+		}
+		b.WriteString(line)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func slackSection(text string) map[string]any {
+	return map[string]any{
+		"type": "section",
+		"text": map[string]string{"type": "mrkdwn", "text": text},
+	}
+}
+
+func (n *Slack) post(ctx context.Context, url, token string, msg map[string]any) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return statusError("slack", resp)
+	}
+	if token == "" {
+		return nil
+	}
+
+	// The Web API reports failures in the body with a 200 status
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if !result.OK {
+		err := errors.New("slack: " + result.Error)
+		if slackTransient[result.Error] {
+			return err
+		}
+		return permanent(err)
+	}
+	return nil
+}
+
+// slackTransient are the Web API errors worth retrying; the rest, such as
+// invalid_auth or channel_not_found, won't go away by repeating
+var slackTransient = map[string]bool{
+	"ratelimited":         true,
+	"internal_error":      true,
+	"fatal_error":         true,
+	"service_unavailable": true,
+	"request_timeout":     true,
+}
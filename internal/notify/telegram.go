@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// telegramLimit is the maximum length of a Telegram message
+const telegramLimit = 4096
+
+// Telegram sends reports to a chat through a bot
+type Telegram struct {
+	Token  string
+	ChatID string
+	client *http.Client
+}
+
+// NewTelegram creates a Telegram notifier
+func NewTelegram(token, chatID string) *Telegram {
+	return &Telegram{
+		Token:  token,
+		ChatID: chatID,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Notifier
+func (n *Telegram) Name() string { return "telegram" }
+
+// Notify sends the findings grouped by TLD, split into as many messages as
+// the length limit requires
+func (n *Telegram) Notify(ctx context.Context, r Report) error {
+	for _, msg := range telegramMessages(r) {
+		if err := withRetry(ctx, func() error { return n.send(ctx, msg) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// telegramMessages formats r as HTML messages, breaking between lines
+func telegramMessages(r Report) []string {
+	lines := []string{
+		fmt.Sprintf("🎯 <b>%s</b>: %s (%s)", html.EscapeString(r.Title), headline(r), r.Date.Format("Jan 2")),
+	}
+	for _, a := range r.Alerts {
+		lines = append(lines, "🔔 <code>"+a.Domain+"</code> "+html.EscapeString(a.Message))
+	}
+	for _, g := range GroupByTLD(r.Domains) {
+		lines = append(lines, "", fmt.Sprintf("<b>.%s</b> (%d)", g.TLD, len(g.Domains)))
+		for _, d := range g.Domains {
+			line := "<code>" + d.Domain + "</code>"
+			if p := d.PriceNote(); p != "" {
+				line += " (" + p + ")"
+			}
+			if w := d.Enrichment.Warnings(); len(w) > 0 {
+				line += " ⚠️ " + html.EscapeString(strings.Join(w, "; "))
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	var msgs []string
+	var b strings.Builder
+	for _, line := range lines {
+		if b.Len()+len(line)+1 > telegramLimit {
+			msgs = append(msgs, b.String())
+			b.Reset()
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return append(msgs, b.String())
+}
+
+func (n *Telegram) send(ctx context.Context, text string) error {
+	payload, err := json.Marshal(map[string]any{
+		"chat_id":    n.ChatID,
+		"text":       text,
+		"parse_mode": "HTML",
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := "https://api.telegram.org/bot" + n.Token + "/sendMessage"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// The URL embeds the token; keep it out of logs
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return fmt.Errorf("telegram: %w", uerr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		ErrorCode   int    `json:"error_code"`
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return statusError("telegram", resp)
+	}
+	if !result.OK {
+		// Flood control and server errors are worth retrying, as long as
+		// Telegram asks; anything else, such as a bad chat ID, isn't
+		if result.ErrorCode == http.StatusTooManyRequests || result.ErrorCode >= 500 {
+			return &StatusError{Service: "telegram", Code: result.ErrorCode, RetryAfter: time.Duration(result.Parameters.RetryAfter) * time.Second}
+		}
+		return permanent(errors.New("telegram: " + result.Description))
+	}
+	return nil
+}
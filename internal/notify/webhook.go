@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// When the webhook has a secret, SignatureHeader carries the hex
+// HMAC-SHA256 of the TimestampHeader value, a dot and the request body,
+// prefixed with "sha256=". The timestamp is the Unix time in seconds the
+// request was sent, each retry sending a new one; receivers should refuse
+// one more than SignatureTolerance away from their clock, so a captured
+// request can't be replayed later.
+const (
+	SignatureHeader = "X-DomainHunter-Signature"
+	TimestampHeader = "X-DomainHunter-Timestamp"
+)
+
+// SignatureTolerance is how far a request's timestamp may be from the
+// receiver's clock, either way, for Verify to accept it
+const SignatureTolerance = 5 * time.Minute
+
+// Webhook POSTs the report's results as a JSON array to any URL
+type Webhook struct {
+	URL    string
+	Secret string
+	client *http.Client
+}
+
+// NewWebhook creates a webhook notifier. With a secret, each request is
+// signed so the receiver can verify it came from us.
+func NewWebhook(url, secret string) *Webhook {
+	return &Webhook{
+		URL:    url,
+		Secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Notifier
+func (n *Webhook) Name() string { return "webhook" }
+
+// Notify posts r.Domains, or r.Alerts for a report of alerts only; the title
+// travels in the X-DomainHunter-Report header and the body's kind,
+// "results" or "alerts", in X-DomainHunter-Kind
+func (n *Webhook) Notify(ctx context.Context, r Report) error {
+	var body any = r.Domains
+	kind := "results"
+	switch {
+	case len(r.Domains) == 0 && len(r.Alerts) > 0:
+		body, kind = r.Alerts, "alerts"
+	case r.Domains == nil:
+		body = []models.DomainResult{}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return withRetry(ctx, func() error { return n.post(ctx, r.Title, kind, payload) })
+}
+
+func (n *Webhook) post(ctx context.Context, title, kind string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-DomainHunter-Report", title)
+	req.Header.Set("X-DomainHunter-Kind", kind)
+	if n.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, Sign(n.Secret, ts, payload))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return statusError("webhook", resp)
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value for body sent at timestamp, the
+// TimestampHeader value
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a webhook request as its receiver would: signature and
+// timestamp are its SignatureHeader and TimestampHeader values, and the
+// timestamp must be within SignatureTolerance of now
+func Verify(secret, signature, timestamp string, body []byte, now time.Time) error {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("webhook: bad timestamp")
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > SignatureTolerance || d < -SignatureTolerance {
+		return errors.New("webhook: timestamp outside the tolerance window")
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return errors.New("webhook: signature mismatch")
+	}
+	return nil
+}
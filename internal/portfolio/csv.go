@@ -0,0 +1,113 @@
+package portfolio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Date layouts seen in registrar CSV exports
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"01/02/2006",
+	"02.01.2006",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+}
+
+// Header names accepted for each column (lowercase)
+var (
+	domainColumns    = []string{"domain", "domain name", "name"}
+	expiresColumns   = []string{"expires", "expiry", "expiration", "expiration date", "expiry date", "expires_at"}
+	registrarColumns = []string{"registrar"}
+)
+
+// ParseCSV reads a portfolio export with a header row. Only the domain column
+// is required; expiry and registrar are picked up when present.
+func ParseCSV(r io.Reader) ([]models.WatchedDomain, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	domainCol := findColumn(header, domainColumns)
+	if domainCol == -1 {
+		return nil, errors.New("no domain column in header")
+	}
+	expiresCol := findColumn(header, expiresColumns)
+	registrarCol := findColumn(header, registrarColumns)
+
+	var domains []models.WatchedDomain
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		name := normalize(field(rec, domainCol))
+		if name == "" {
+			continue
+		}
+
+		d := models.WatchedDomain{
+			Domain:    name,
+			Status:    models.StatusTaken,
+			Owned:     true,
+			Registrar: field(rec, registrarCol),
+		}
+		if raw := field(rec, expiresCol); raw != "" {
+			if d.ExpiresAt, err = parseDate(raw); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		domains = append(domains, d)
+	}
+
+	return domains, nil
+}
+
+func findColumn(header []string, names []string) int {
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, n := range names {
+			if h == n {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func field(rec []string, col int) string {
+	if col < 0 || col >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[col])
+}
+
+func normalize(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
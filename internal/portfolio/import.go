@@ -0,0 +1,59 @@
+package portfolio
+
+import (
+	"context"
+
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// Summary reports what an import changed
+type Summary struct {
+	Added   int
+	Updated int
+}
+
+// Import upserts owned domains into the watch list. Existing entries for the
+// same domain are marked owned and get the imported expiry, registrar and
+// auto-renew setting.
+func Import(ctx context.Context, store storage.Store, domains []models.WatchedDomain) (Summary, error) {
+	var sum Summary
+
+	existing, err := store.ListWatches(ctx)
+	if err != nil {
+		return sum, err
+	}
+	byDomain := make(map[string]*models.WatchedDomain, len(existing))
+	for i := range existing {
+		byDomain[existing[i].Domain] = &existing[i]
+	}
+
+	for _, d := range domains {
+		if w, ok := byDomain[d.Domain]; ok {
+			w.Owned = true
+			w.Status = models.StatusTaken
+			if !d.ExpiresAt.IsZero() {
+				w.ExpiresAt = d.ExpiresAt
+			}
+			if d.Registrar != "" {
+				w.Registrar = d.Registrar
+			}
+			if d.AutoRenew != nil {
+				w.AutoRenew = d.AutoRenew
+			}
+			if err := store.UpdateWatch(ctx, w); err != nil {
+				return sum, err
+			}
+			sum.Updated++
+			continue
+		}
+
+		if err := store.AddWatch(ctx, &d); err != nil {
+			return sum, err
+		}
+		byDomain[d.Domain] = &d
+		sum.Added++
+	}
+
+	return sum, nil
+}
@@ -0,0 +1,89 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Source lists the domains held in a registrar account, as owned watches
+// with the expiry and auto-renew setting the registrar reports
+type Source interface {
+	Name() string
+	Domains(ctx context.Context) ([]models.WatchedDomain, error)
+}
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Cloudflare lists the domains registered with Cloudflare Registrar. Token
+// needs the account's Registrar read permission.
+type Cloudflare struct {
+	Token     string
+	AccountID string
+}
+
+// Name implements Source
+func (Cloudflare) Name() string { return "cloudflare" }
+
+// Domains implements Source
+func (c Cloudflare) Domains(ctx context.Context) ([]models.WatchedDomain, error) {
+	var domains []models.WatchedDomain
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.cloudflare.com/client/v4/accounts/%s/registrar/domains?page=%d&per_page=50", c.AccountID, page)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return domains, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		resp, err := client.Do(req)
+		if err != nil {
+			return domains, err
+		}
+
+		var body struct {
+			Success bool `json:"success"`
+			Errors  []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+			Result []struct {
+				Name      string    `json:"name"`
+				ExpiresAt time.Time `json:"expires_at"`
+				AutoRenew bool      `json:"auto_renew"`
+			} `json:"result"`
+			ResultInfo struct {
+				TotalPages int `json:"total_pages"`
+			} `json:"result_info"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			return domains, fmt.Errorf("cloudflare returned status %d", resp.StatusCode)
+		}
+		if !body.Success {
+			var msgs []string
+			for _, e := range body.Errors {
+				msgs = append(msgs, e.Message)
+			}
+			return domains, fmt.Errorf("cloudflare: %s", strings.Join(msgs, "; "))
+		}
+
+		for _, d := range body.Result {
+			domains = append(domains, models.WatchedDomain{
+				Domain:    normalize(d.Name),
+				Status:    models.StatusTaken,
+				Owned:     true,
+				Registrar: "Cloudflare",
+				ExpiresAt: d.ExpiresAt,
+				AutoRenew: &d.AutoRenew,
+			})
+		}
+		if page >= body.ResultInfo.TotalPages {
+			return domains, nil
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package pricing
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Comparison is one TLD's latest prices across registrars
+type Comparison struct {
+	TLD string
+	// Prices are each registrar's latest, cheapest first year first
+	Prices []models.Price
+}
+
+// CheapestRenewal returns the registrar price that renews for least
+func (c Comparison) CheapestRenewal() models.Price {
+	return slices.MinFunc(c.Prices, func(a, b models.Price) int {
+		return cmp.Compare(a.RenewalPrice(), b.RenewalPrice())
+	})
+}
+
+// Traps returns the registrar prices with a renewal trap
+func (c Comparison) Traps() []models.Price {
+	var traps []models.Price
+	for _, p := range c.Prices {
+		if p.Trap() {
+			traps = append(traps, p)
+		}
+	}
+	return traps
+}
+
+// Compare groups prices by TLD in TLD order, keeping each registrar's
+// latest quote
+func Compare(prices []models.Price) []Comparison {
+	latest := make(map[string]map[string]models.Price)
+	for _, p := range prices {
+		if p.Register <= 0 && p.Renew <= 0 {
+			continue
+		}
+		if latest[p.TLD] == nil {
+			latest[p.TLD] = make(map[string]models.Price)
+		}
+		if cur, ok := latest[p.TLD][p.Registrar]; !ok || p.CheckedAt.After(cur.CheckedAt) {
+			latest[p.TLD][p.Registrar] = p
+		}
+	}
+
+	var comps []Comparison
+	for tld, byRegistrar := range latest {
+		c := Comparison{TLD: tld}
+		for _, p := range byRegistrar {
+			c.Prices = append(c.Prices, p)
+		}
+		slices.SortFunc(c.Prices, func(a, b models.Price) int {
+			return cmp.Or(cmp.Compare(a.Register, b.Register), cmp.Compare(a.Registrar, b.Registrar))
+		})
+		comps = append(comps, c)
+	}
+	slices.SortFunc(comps, func(a, b Comparison) int { return cmp.Compare(a.TLD, b.TLD) })
+	return comps
+}
@@ -0,0 +1,139 @@
+// Package pricing fetches TLD prices from registrar APIs and spots
+// promotions in their history.
+package pricing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/autobuy"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Source fetches one registrar's current prices
+type Source interface {
+	Name() string
+	// Prices returns the registrar's prices for the TLDs it sells
+	Prices(ctx context.Context, tlds []string) ([]models.Price, error)
+}
+
+// promoDrop is how far a first-year price must fall to be a promotion
+const promoDrop = 0.10
+
+// Promotion describes cur as a promotion if its first-year price is at
+// least 10% below prev's
+func Promotion(prev, cur models.Price) (string, bool) {
+	if prev.Register <= 0 || cur.Register <= 0 || cur.Register > prev.Register*(1-promoDrop) {
+		return "", false
+	}
+	msg := fmt.Sprintf("first-year .%s at $%.2f on %s, down from $%.2f", cur.TLD, cur.Register, cur.Registrar, prev.Register)
+	if cur.Renew > cur.Register {
+		msg += fmt.Sprintf(" (renews at $%.2f)", cur.Renew)
+	}
+	return msg, true
+}
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Porkbun reads Porkbun's public price list, which needs no API key
+type Porkbun struct{}
+
+// Name implements Source
+func (Porkbun) Name() string { return autobuy.Porkbun }
+
+// Prices implements Source
+func (Porkbun) Prices(ctx context.Context, tlds []string) ([]models.Price, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.porkbun.com/api/json/v3/pricing/get", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("porkbun returned status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Pricing map[string]struct {
+			Registration string `json:"registration"`
+			Renewal      string `json:"renewal"`
+			Transfer     string `json:"transfer"`
+		} `json:"pricing"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if out.Status != "SUCCESS" {
+		return nil, errors.New("porkbun: " + out.Message)
+	}
+
+	now := time.Now()
+	var prices []models.Price
+	for _, tld := range tlds {
+		p, ok := out.Pricing[tld]
+		if !ok {
+			continue
+		}
+		prices = append(prices, models.Price{
+			Registrar: autobuy.Porkbun,
+			TLD:       tld,
+			Register:  parsePrice(p.Registration),
+			Renew:     parsePrice(p.Renewal),
+			Transfer:  parsePrice(p.Transfer),
+			CheckedAt: now,
+		})
+	}
+	return prices, nil
+}
+
+// parsePrice reads a price like "10.37" or "1,200.00", zero if unquoted
+func parsePrice(s string) float64 {
+	f, _ := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	return f
+}
+
+// Namecheap reads the account's prices through Namecheap's API, one call
+// per TLD
+type Namecheap struct {
+	Registrar *autobuy.NamecheapRegistrar
+}
+
+// Name implements Source
+func (Namecheap) Name() string { return autobuy.Namecheap }
+
+// Prices implements Source
+func (n Namecheap) Prices(ctx context.Context, tlds []string) ([]models.Price, error) {
+	now := time.Now()
+	var prices []models.Price
+	for _, tld := range tlds {
+		p, err := n.Registrar.Prices(ctx, tld)
+		if err != nil {
+			return prices, fmt.Errorf("%s: %w", tld, err)
+		}
+		if len(p) == 0 {
+			continue
+		}
+		prices = append(prices, models.Price{
+			Registrar: autobuy.Namecheap,
+			TLD:       tld,
+			Register:  p["register"],
+			Renew:     p["renew"],
+			Transfer:  p["transfer"],
+			CheckedAt: now,
+		})
+	}
+	return prices, nil
+}
@@ -0,0 +1,165 @@
+// Package render prints lists of items in the formats every domainhunter
+// command offers: an aligned table for people, JSON, NDJSON or CSV for
+// machines.
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formats are the supported output formats
+var Formats = []string{"json", "ndjson", "csv", "table"}
+
+// Valid reports whether format is supported
+func Valid(format string) bool {
+	return slices.Contains(Formats, format)
+}
+
+// DisableColor keeps tables plain even on terminals. It starts out set
+// when NO_COLOR is (see https://no-color.org).
+var DisableColor = os.Getenv("NO_COLOR") != ""
+
+// Color is an ANSI SGR code for a table cell's text
+type Color string
+
+// Table cell colors
+const (
+	Default Color = ""
+	Green   Color = "32"
+	Red     Color = "31"
+	Yellow  Color = "33"
+	Dim     Color = "2"
+)
+
+// Table describes how items of type T print as rows. JSON and NDJSON
+// encode the items themselves.
+type Table[T any] struct {
+	// Columns head the table
+	Columns []string
+	// Row returns an item's cells, in Columns order
+	Row func(T) []string
+	// CSVColumns and CSVRow replace Columns and Row for CSV, e.g. with
+	// machine-readable times; by default CSV has Columns lowercased, with
+	// spaces as underscores
+	CSVColumns []string
+	CSVRow     func(T) []string
+	// Colors, if set, returns the colors of an item's cells, for tables
+	// written to a terminal
+	Colors func(T) []Color
+}
+
+// Write writes items to w in format:
+//   - json: an indented array
+//   - ndjson: one object per line
+//   - csv: a header row, then a row per item
+//   - table: aligned columns for terminals
+func (t Table[T]) Write(w io.Writer, format string, items []T) error {
+	switch format {
+	case "json":
+		if items == nil {
+			items = []T{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for _, it := range items {
+			if err := enc.Encode(it); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "csv":
+		cols, row := t.CSVColumns, t.CSVRow
+		if cols == nil {
+			for _, c := range t.Columns {
+				cols = append(cols, strings.ReplaceAll(strings.ToLower(c), " ", "_"))
+			}
+		}
+		if row == nil {
+			row = t.Row
+		}
+		cw := csv.NewWriter(w)
+		cw.Write(cols)
+		for _, it := range items {
+			cw.Write(row(it))
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case "table":
+		rows := [][]string{t.Columns}
+		colors := [][]Color{nil}
+		colored := t.Colors != nil && Colorful(w)
+		for _, it := range items {
+			rows = append(rows, t.Row(it))
+			if colored {
+				colors = append(colors, t.Colors(it))
+			}
+		}
+		return writeAligned(w, rows, colors)
+	}
+	return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(Formats, ", "))
+}
+
+// Colorful reports whether tables written to w get colors: w is a
+// terminal and color isn't disabled
+func Colorful(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || DisableColor {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Paint wraps s in color c, if any
+func Paint(s string, c Color) string {
+	if c == Default {
+		return s
+	}
+	return "\x1b[" + string(c) + "m" + s + "\x1b[0m"
+}
+
+// writeAligned writes rows as columns two spaces apart, each cell painted
+// in its color, if any. Cells are padded before they're painted, so colors
+// don't throw the alignment off.
+func writeAligned(w io.Writer, rows [][]string, colors [][]Color) error {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	for r, row := range rows {
+		line := ""
+		for i, cell := range row {
+			padded := cell
+			if i < len(row)-1 {
+				padded += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2)
+			}
+			if r < len(colors) && i < len(colors[r]) && cell != "" {
+				padded = Paint(cell, colors[r][i]) + padded[len(cell):]
+			}
+			line += padded
+		}
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
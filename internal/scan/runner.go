@@ -0,0 +1,384 @@
+package scan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/logging"
+	"github.com/berckan/domainhunter/internal/models"
+	"github.com/berckan/domainhunter/internal/storage"
+)
+
+// DefaultChunkSize is how many domains are checked between checkpoints
+const DefaultChunkSize = 1000
+
+// DefaultMaxAge is how old a checkpoint may be and still be resumed
+const DefaultMaxAge = 24 * time.Hour
+
+// Runner checks a domain list in chunks with the hybrid DNS+WHOIS check,
+// saving a checkpoint after every chunk so an interrupted scan resumes where
+// it stopped instead of starting over
+type Runner struct {
+	Checker   *checker.Checker
+	Store     storage.Store
+	ChunkSize int
+	// MaxAge is how stale a checkpoint may be; zero resumes any age
+	MaxAge time.Duration
+	// KeepCompleted leaves a finished scan's checkpoint in place, so a run
+	// of several scans can skip it when resumed. The caller deletes it.
+	KeepCompleted bool
+	// Budget, if set, stops scans once it is spent. It covers every scan
+	// the runner runs.
+	Budget Budget
+	// Spread, if set, paces the checker so the run's scans end around
+	// Spread.End
+	Spread *Spread
+
+	// OnProgress, if set, is called after each chunk
+	OnProgress func(done, total int)
+	// OnChunk, if set, is called with each chunk's findings once its
+	// checkpoint is saved, before the rest of the scan is checked
+	OnChunk func(available, dropping []models.DomainResult)
+	// OnResult, if set, gets every result of each chunk as soon as the
+	// chunk is checked, before its checkpoint is saved, so nothing is lost
+	// to a crash (a resumed chunk may repeat). An error stops the scan.
+	OnResult checker.Sink
+}
+
+// Budget caps the work of a run of one or more scans. It is checked
+// between chunks, so a run overshoots it by at most a chunk.
+type Budget struct {
+	// MaxWHOIS is how many WHOIS queries the checker may send; 0 is no limit
+	MaxWHOIS int64
+	// Deadline is when to stop; zero is never
+	Deadline time.Time
+}
+
+// ErrBudgetSpent means a scan stopped because the run's budget was spent.
+// Its checkpoint is kept, whatever KeepCompleted says, to carry on from.
+var ErrBudgetSpent = errors.New("run budget spent")
+
+// spent returns ErrBudgetSpent, saying why, once the budget is spent
+func (r *Runner) spent() error {
+	b := r.Budget
+	switch {
+	case b.MaxWHOIS > 0 && r.Checker.WHOISQueries() >= b.MaxWHOIS:
+		return fmt.Errorf("%w: %d WHOIS queries sent", ErrBudgetSpent, r.Checker.WHOISQueries())
+	case !b.Deadline.IsZero() && !time.Now().Before(b.Deadline):
+		return fmt.Errorf("%w: out of time", ErrBudgetSpent)
+	}
+	return nil
+}
+
+// Domains is a domain list the runner reads one chunk at a time, so a
+// generated list never has to be held whole
+type Domains interface {
+	Len() int
+	// Slice returns the domains from index from up to, not including, to
+	Slice(from, to int) []string
+	Contains(domain string) bool
+	// Fingerprint identifies the list, so checkpoints and run comparisons
+	// are only applied to the same scope. A generated list hashes what
+	// it's generated from rather than every domain.
+	Fingerprint() string
+}
+
+// List is a Domains held in memory
+type List []string
+
+// Len implements Domains
+func (l List) Len() int { return len(l) }
+
+// Slice implements Domains
+func (l List) Slice(from, to int) []string { return l[from:to] }
+
+// Contains implements Domains
+func (l List) Contains(domain string) bool { return slices.Contains(l, domain) }
+
+// Fingerprint implements Domains, hashing every domain
+func (l List) Fingerprint() string {
+	h := sha256.New()
+	for _, d := range l {
+		h.Write([]byte(d))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Concat chains several Domains into one, in order
+type Concat []Domains
+
+// Len implements Domains
+func (c Concat) Len() int {
+	n := 0
+	for _, d := range c {
+		n += d.Len()
+	}
+	return n
+}
+
+// Slice implements Domains
+func (c Concat) Slice(from, to int) []string {
+	var domains []string
+	for _, d := range c {
+		n := d.Len()
+		if from < n && to > 0 {
+			domains = append(domains, d.Slice(max(from, 0), min(to, n))...)
+		}
+		from, to = from-n, to-n
+	}
+	return domains
+}
+
+// Contains implements Domains
+func (c Concat) Contains(domain string) bool {
+	return slices.ContainsFunc(c, func(d Domains) bool { return d.Contains(domain) })
+}
+
+// Fingerprint implements Domains, hashing its parts' fingerprints in order
+func (c Concat) Fingerprint() string {
+	h := sha256.New()
+	for _, d := range c {
+		fmt.Fprintln(h, d.Fingerprint())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// tail is the domains of d from index from on
+type tail struct {
+	d    Domains
+	from int
+}
+
+// Len implements Domains
+func (t tail) Len() int { return max(t.d.Len()-t.from, 0) }
+
+// Slice implements Domains
+func (t tail) Slice(from, to int) []string { return t.d.Slice(t.from+from, t.from+to) }
+
+// Contains implements Domains
+func (t tail) Contains(domain string) bool {
+	for i := 0; i < t.Len(); i += DefaultChunkSize {
+		if slices.Contains(t.Slice(i, min(i+DefaultChunkSize, t.Len())), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprint implements Domains
+func (t tail) Fingerprint() string {
+	h := sha256.Sum256(fmt.Appendf(nil, "%s@%d", t.d.Fingerprint(), t.from))
+	return hex.EncodeToString(h[:])
+}
+
+// NewRunner creates a Runner with default chunk size and checkpoint age
+func NewRunner(c *checker.Checker, store storage.Store) *Runner {
+	return &Runner{
+		Checker:   c,
+		Store:     store,
+		ChunkSize: DefaultChunkSize,
+		MaxAge:    DefaultMaxAge,
+	}
+}
+
+// Result is what a scan found and how its checks went
+type Result struct {
+	// Available are the findings: available domains and premium ones a
+	// registrar quoted
+	Available []models.DomainResult
+	// Dropping are taken domains in redemption or pending delete
+	Dropping []models.DomainResult
+	Stats    models.ScanStats
+	// Unchecked are the domains a scan cut short didn't get to, in scan
+	// order with those whose WHOIS server was down last; Stats.Unchecked
+	// counts them. It is nil for a finished scan.
+	Unchecked Domains
+}
+
+// Run checks domains and returns the available ones with the scan's stats.
+// key identifies the scan across runs; a checkpoint is only resumed if it
+// was made for the same domain list. On success the checkpoint is removed
+// unless KeepCompleted is set. If ctx is cancelled, Run stops mid-chunk
+// and returns ctx.Err() with progress up to the last whole chunk saved,
+// along with a partial Result: what was checked, including the part of
+// the chunk in progress, and what wasn't.
+func (r *Runner) Run(ctx context.Context, key string, domains []string) (Result, error) {
+	return r.RunDomains(ctx, key, List(domains))
+}
+
+// RunDomains is Run over a Domains, reading it a chunk at a time
+func (r *Runner) RunDomains(ctx context.Context, key string, domains Domains) (Result, error) {
+	// Every line logged for this scan carries its key and an ID of its own
+	ctx = logging.With(ctx, "scan", key, "scan_id", logging.NewID())
+	cp := r.load(ctx, key, domains)
+	if cp.Done > 0 {
+		slog.InfoContext(ctx, "resuming scan", "done", cp.Done, "total", cp.Total)
+	}
+	if r.Spread != nil {
+		r.Spread.skip(cp.Done)
+	}
+
+	for cp.Done < domains.Len() {
+		if err := ctx.Err(); err != nil {
+			return r.partial(ctx, cp, domains, nil, false), err
+		}
+		if err := r.spent(); err != nil {
+			slog.InfoContext(ctx, "budget spent, stopping scan", "done", cp.Done, "total", cp.Total, "err", err)
+			return r.partial(ctx, cp, domains, nil, false), err
+		}
+
+		end := min(cp.Done+r.ChunkSize, domains.Len())
+		if r.Spread != nil {
+			r.Spread.start(r, end-cp.Done)
+			slog.DebugContext(ctx, "pace", "interval", r.Checker.Pace(), "remaining", r.Spread.Remaining, "end", r.Spread.End)
+		}
+		results, err := r.Checker.CheckBulkHybrid(ctx, domains.Slice(cp.Done, end))
+		if err != nil {
+			// The chunk is checked again on resume
+			return r.partial(ctx, cp, domains, results, false), err
+		}
+		available, dropping, err := r.add(&cp, results, true)
+		if err != nil {
+			return r.partial(ctx, cp, domains, nil, false), err
+		}
+		if r.Spread != nil {
+			r.Spread.done(end - cp.Done)
+		}
+		cp.Done = end
+		r.save(ctx, &cp)
+		if r.OnProgress != nil {
+			r.OnProgress(cp.Done, cp.Total)
+		}
+		if r.OnChunk != nil && (len(available) > 0 || len(dropping) > 0) {
+			r.OnChunk(available, dropping)
+		}
+	}
+
+	// Domains skipped while their WHOIS server was down get another go
+	// now that the rest is done
+	if len(cp.Deferred) > 0 {
+		if err := r.spent(); err != nil {
+			slog.InfoContext(ctx, "budget spent, leaving domains whose WHOIS server was down", "domains", len(cp.Deferred), "err", err)
+			return r.partial(ctx, cp, domains, nil, false), err
+		}
+		slog.InfoContext(ctx, "rechecking domains whose WHOIS server was down", "domains", len(cp.Deferred))
+		results, err := r.Checker.Recheck(ctx, cp.Deferred)
+		if err != nil {
+			// Rechecked again on resume
+			return r.partial(ctx, cp, domains, results, true), err
+		}
+		// Any still unknown now count as errors
+		available, dropping, err := r.add(&cp, results, false)
+		if err != nil {
+			return r.partial(ctx, cp, domains, nil, false), err
+		}
+		cp.Deferred = nil
+		r.save(ctx, &cp)
+		if r.OnChunk != nil && (len(available) > 0 || len(dropping) > 0) {
+			r.OnChunk(available, dropping)
+		}
+	}
+
+	if !r.KeepCompleted {
+		if err := r.Store.DeleteCheckpoint(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "delete checkpoint", "err", err)
+		}
+	}
+	return Result{Available: cp.Available, Dropping: cp.Dropping, Stats: cp.Stats}, nil
+}
+
+// partial is the Result of a scan cut short at cp. results are those of
+// the chunk in progress, or of the recheck of cp.Deferred when rechecking;
+// the ones it got through count as checked, though the checkpoint still
+// has the chunk checked again on resume.
+func (r *Runner) partial(ctx context.Context, cp models.Checkpoint, domains Domains, results []models.DomainResult, rechecking bool) Result {
+	var checked []models.DomainResult
+	var left []string
+	for _, res := range results {
+		if res.Status == models.StatusUnchecked {
+			left = append(left, res.Domain)
+		} else {
+			checked = append(checked, res)
+		}
+	}
+	next := cp.Done + len(results)
+	if rechecking {
+		next, cp.Deferred = cp.Done, nil
+	}
+	if _, _, err := r.add(&cp, checked, !rechecking); err != nil {
+		slog.ErrorContext(ctx, "partial results", "err", err)
+	}
+
+	unchecked := Concat{List(left), tail{domains, next}, List(cp.Deferred)}
+	cp.Stats.Unchecked = unchecked.Len()
+	return Result{Available: cp.Available, Dropping: cp.Dropping, Stats: cp.Stats, Unchecked: unchecked}
+}
+
+// add adds checked results to cp, passing each to OnResult, and returns
+// the findings (available domains and priced premium ones) and the
+// dropping ones. With deferring set, results skipped as
+// StatusUnknown are set aside in cp.Deferred instead.
+func (r *Runner) add(cp *models.Checkpoint, results []models.DomainResult, deferring bool) (available, dropping []models.DomainResult, err error) {
+	checked := results[:0:0]
+	for _, res := range results {
+		if deferring && res.Status == models.StatusUnknown {
+			cp.Deferred = append(cp.Deferred, res.Domain)
+			continue
+		}
+		if r.OnResult != nil {
+			if err := r.OnResult(res); err != nil {
+				return nil, nil, err
+			}
+		}
+		checked = append(checked, res)
+		switch {
+		case res.Finding():
+			available = append(available, res)
+		case res.Phase != "":
+			dropping = append(dropping, res)
+		}
+	}
+	cp.Stats.Add(checked)
+	cp.Available = append(cp.Available, available...)
+	cp.Dropping = append(cp.Dropping, dropping...)
+	return available, dropping, nil
+}
+
+// save stores cp as the scan's checkpoint
+func (r *Runner) save(ctx context.Context, cp *models.Checkpoint) {
+	cp.UpdatedAt = time.Now()
+	if err := r.Store.SaveCheckpoint(ctx, *cp); err != nil {
+		slog.ErrorContext(ctx, "save checkpoint", "err", err)
+	}
+}
+
+// load returns a resumable checkpoint for key, or a fresh one
+func (r *Runner) load(ctx context.Context, key string, domains Domains) models.Checkpoint {
+	fp := domains.Fingerprint()
+	fresh := models.Checkpoint{
+		Key:         key,
+		Fingerprint: fp,
+		Total:       domains.Len(),
+		StartedAt:   time.Now(),
+	}
+
+	cp, err := r.Store.GetCheckpoint(ctx, key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			slog.ErrorContext(ctx, "load checkpoint", "err", err)
+		}
+		return fresh
+	}
+	if cp.Fingerprint != fp || cp.Done > domains.Len() || (r.MaxAge > 0 && time.Since(cp.UpdatedAt) > r.MaxAge) {
+		return fresh
+	}
+	return cp
+}
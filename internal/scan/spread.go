@@ -0,0 +1,63 @@
+package scan
+
+import "time"
+
+// Spread stretches a run's checks evenly over a time window instead of
+// checking as fast as the checker allows. Before each chunk, the runner
+// sets the checker's pace from the time and domains left, corrected by how
+// long the domains checked so far actually took at the pace set.
+type Spread struct {
+	// End is when the run should be done
+	End time.Time
+	// Remaining is how many domains the run has left; the runner counts
+	// it down across all its scans
+	Remaining int
+
+	interval time.Duration // pace set for the chunk in progress
+	begun    time.Time     // when the first chunk started
+	checked  int           // domains checked since then
+}
+
+// queriesPerDomain is the first guess at the pace: most candidates of a
+// long scan have no DNS delegation and go on to WHOIS
+const queriesPerDomain = 2
+
+// start sets the pace for a chunk of n domains
+func (s *Spread) start(r *Runner, n int) {
+	left := time.Until(s.End)
+	if left <= 0 {
+		// Behind schedule: finish as fast as the checker allows
+		s.interval = 0
+		r.Checker.SetPace(0)
+		return
+	}
+	perDomain := left / time.Duration(max(s.Remaining, n, 1))
+
+	switch {
+	case s.interval == 0 || s.checked == 0:
+		s.interval = perDomain / queriesPerDomain
+	default:
+		// Pacing dominates a slow scan, so the time a domain takes scales
+		// with the interval; stay within a factor of 4 per chunk so one
+		// odd chunk can't throw the pace off
+		actual := time.Since(s.begun) / time.Duration(s.checked)
+		next := time.Duration(float64(s.interval) * float64(perDomain) / float64(max(actual, 1)))
+		s.interval = min(max(next, s.interval/4), 4*s.interval)
+	}
+	if s.begun.IsZero() {
+		s.begun = time.Now()
+	}
+	r.Checker.SetPace(s.interval)
+}
+
+// done counts a chunk of n domains as checked
+func (s *Spread) done(n int) {
+	s.Remaining -= n
+	s.checked += n
+}
+
+// skip counts n domains a resumed scan had already checked, which take
+// none of this run's time
+func (s *Spread) skip(n int) {
+	s.Remaining -= n
+}
@@ -0,0 +1,100 @@
+// Package score estimates how valuable an available domain is, so the
+// best finds can be listed first.
+package score
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Score rates domain from 0 to 100. It favours short names, premium TLDs
+// (in checker.PremiumTLDs order), letters over digits and names that can
+// be pronounced.
+func Score(domain string) int {
+	label, tld, _ := strings.Cut(strings.ToLower(domain), ".")
+
+	s := 0
+	switch n := len(label); {
+	case n <= 3:
+		s += 50 - 10*n
+	default:
+		s += max(0, 20-4*(n-3))
+	}
+
+	if i := slices.Index(checker.PremiumTLDs, tld); i >= 0 {
+		s += 30 - i
+	}
+
+	switch {
+	case strings.Contains(label, "-"):
+	case strings.Trim(label, "abcdefghijklmnopqrstuvwxyz") == "":
+		s += 10
+		if pronounceable(label) {
+			s += 10
+		}
+	case strings.Trim(label, "0123456789") == "":
+		s += 5
+	}
+	return min(s, 100)
+}
+
+// Sort orders results by descending appraisal, then descending score,
+// keeping input order for ties. Appraised results come first.
+func Sort(results []models.DomainResult) {
+	slices.SortStableFunc(results, func(a, b models.DomainResult) int {
+		va, oka := appraisal(a)
+		vb, okb := appraisal(b)
+		if c := cmp.Compare(boolInt(okb), boolInt(oka)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(vb, va); c != 0 {
+			return c
+		}
+		return cmp.Compare(Score(b.Domain), Score(a.Domain))
+	})
+}
+
+// appraisal returns r's appraised value, if enrichment found one
+func appraisal(r models.DomainResult) (float64, bool) {
+	if r.Enrichment == nil || r.Enrichment.Appraisal == nil {
+		return 0, false
+	}
+	return r.Enrichment.Appraisal.Value, true
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Top returns the n best results, best first, without changing results
+func Top(results []models.DomainResult, n int) []models.DomainResult {
+	top := slices.Clone(results)
+	Sort(top)
+	return top[:min(n, len(top))]
+}
+
+// pronounceable reports whether label never runs more than two
+// consonants or two vowels together
+func pronounceable(label string) bool {
+	run, prevVowel := 0, false
+	for i, c := range label {
+		vowel := strings.ContainsRune("aeiouy", c)
+		if i > 0 && vowel == prevVowel {
+			run++
+		} else {
+			run = 1
+		}
+		if run > 2 {
+			return false
+		}
+		prevVowel = vowel
+	}
+	return true
+}
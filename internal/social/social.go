@@ -0,0 +1,160 @@
+// Package social checks whether a name is free as a username on social
+// sites, to go with an available domain. Sites are probed over plain
+// HTTP, so answers are best-effort: anything unclear comes back unknown.
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Site probes one site for a free username
+type Site interface {
+	Name() string
+	// Valid reports whether the site allows handle as a username
+	Valid(handle string) bool
+	// Available reports whether nobody has handle yet
+	Available(ctx context.Context, handle string) (bool, error)
+}
+
+// Sites are the sites checked by default
+var Sites = []Site{GitHub, X, Instagram}
+
+// Check looks handle up on every site at once, returning one entry per
+// site in order
+func Check(ctx context.Context, sites []Site, handle string) []models.Handle {
+	handles := make([]models.Handle, len(sites))
+	var wg sync.WaitGroup
+	for i, s := range sites {
+		handles[i] = models.Handle{Site: s.Name(), Status: models.HandleInvalid}
+		if !s.Valid(handle) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handles[i].Status = status(s.Available(ctx, handle))
+		}()
+	}
+	wg.Wait()
+	return handles
+}
+
+func status(free bool, err error) models.HandleStatus {
+	switch {
+	case err != nil:
+		return models.HandleUnknown
+	case free:
+		return models.HandleAvailable
+	}
+	return models.HandleTaken
+}
+
+// Profile is a site whose profile pages are at a URL per username,
+// answering 404 for names nobody has
+type Profile struct {
+	Site    string
+	URL     string // with %s for the username
+	Pattern *regexp.Regexp
+}
+
+// Name implements Site
+func (p Profile) Name() string { return p.Site }
+
+// Valid implements Site
+func (p Profile) Valid(handle string) bool { return p.Pattern.MatchString(handle) }
+
+// Available implements Site. Anything but 200 or 404, such as a redirect
+// to a login page, is an error.
+func (p Profile) Available(ctx context.Context, handle string) (bool, error) {
+	resp, err := get(ctx, fmt.Sprintf(p.URL, url.PathEscape(handle)))
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return true, nil
+	case http.StatusOK:
+		return false, nil
+	}
+	return false, fmt.Errorf("%s returned status %d", p.Site, resp.StatusCode)
+}
+
+// GitHub allows up to 39 letters, digits and inner single hyphens
+var GitHub = Profile{
+	Site:    "GitHub",
+	URL:     "https://github.com/%s",
+	Pattern: regexp.MustCompile(`^[a-z0-9](-?[a-z0-9]){0,38}$`),
+}
+
+// Instagram allows up to 30 letters, digits, periods and underscores
+var Instagram = Profile{
+	Site:    "Instagram",
+	URL:     "https://www.instagram.com/%s/",
+	Pattern: regexp.MustCompile(`^[a-z0-9._]{1,30}$`),
+}
+
+// X asks X's signup form whether a username is taken, since its profile
+// pages answer 200 either way
+var X = xSite{}
+
+type xSite struct{}
+
+// xPattern is X's rule: 4 to 15 letters, digits and underscores
+var xPattern = regexp.MustCompile(`^[a-z0-9_]{4,15}$`)
+
+func (xSite) Name() string { return "X" }
+
+func (xSite) Valid(handle string) bool { return xPattern.MatchString(handle) }
+
+func (xSite) Available(ctx context.Context, handle string) (bool, error) {
+	resp, err := get(ctx, "https://api.x.com/i/users/username_available.json?username="+url.QueryEscape(handle))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("X returned status %d", resp.StatusCode)
+	}
+	var out struct {
+		Valid  bool   `json:"valid"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return false, err
+	}
+	switch {
+	case out.Valid:
+		return true, nil
+	case out.Reason == "taken":
+		return false, nil
+	}
+	return false, fmt.Errorf("X says %q", out.Reason)
+}
+
+// client doesn't follow redirects, which sites use to send bots to a
+// login page instead of answering
+var client = &http.Client{
+	Timeout: 10 * time.Second,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func get(ctx context.Context, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; DomainHunter)")
+	return client.Do(req)
+}
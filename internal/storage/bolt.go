@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"io"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// OpenBolt opens (or creates) a bbolt database file. bbolt is pure Go, so
+// this backend needs no CGO and keeps the server a single static binary.
+func OpenBolt(path string) (Store, error) {
+	bdb, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = bdb.Update(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		bdb.Close()
+		return nil, err
+	}
+
+	return &db{eng: &boltEngine{db: bdb}}, nil
+}
+
+type boltEngine struct {
+	db *bolt.DB
+}
+
+func (e *boltEngine) view(fn func(tx txn) error) error {
+	return e.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTxn{tx})
+	})
+}
+
+func (e *boltEngine) update(fn func(tx txn) error) error {
+	return e.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTxn{tx})
+	})
+}
+
+func (e *boltEngine) snapshot(w io.Writer) error {
+	return e.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
+func (e *boltEngine) close() error {
+	return e.db.Close()
+}
+
+type boltTxn struct {
+	tx *bolt.Tx
+}
+
+func (t boltTxn) bucket(name string) *bolt.Bucket {
+	return t.tx.Bucket([]byte(name))
+}
+
+func (t boltTxn) get(bucket, key string) []byte {
+	v := t.bucket(bucket).Get([]byte(key))
+	if v == nil {
+		return nil
+	}
+	// bbolt values are only valid for the life of the transaction
+	return append([]byte(nil), v...)
+}
+
+func (t boltTxn) put(bucket, key string, value []byte) error {
+	return t.bucket(bucket).Put([]byte(key), value)
+}
+
+func (t boltTxn) delete(bucket, key string) error {
+	return t.bucket(bucket).Delete([]byte(key))
+}
+
+func (t boltTxn) forEach(bucket string, fn func(key string, value []byte) error) error {
+	return t.bucket(bucket).ForEach(func(k, v []byte) error {
+		return fn(string(k), v)
+	})
+}
+
+func (t boltTxn) nextID(bucket string) (int64, error) {
+	id, err := t.bucket(bucket).NextSequence()
+	return int64(id), err
+}
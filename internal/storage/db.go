@@ -0,0 +1,305 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// db implements Store on top of an engine
+type db struct {
+	eng engine
+}
+
+func (d *db) SaveResults(_ context.Context, results []models.DomainResult) error {
+	return d.eng.update(func(tx txn) error {
+		for _, r := range results {
+			if err := putJSON(tx, bucketResults, r.Domain, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *db) GetResult(_ context.Context, domain string) (models.DomainResult, error) {
+	var r models.DomainResult
+	err := d.eng.view(func(tx txn) error {
+		return getJSON(tx, bucketResults, domain, &r)
+	})
+	return r, err
+}
+
+func (d *db) SaveScan(_ context.Context, scan *models.Scan) error {
+	return d.eng.update(func(tx txn) error {
+		id, err := tx.nextID(bucketScans)
+		if err != nil {
+			return err
+		}
+		scan.ID = id
+		return putJSON(tx, bucketScans, idKey(id), scan)
+	})
+}
+
+func (d *db) GetScan(_ context.Context, id int64) (models.Scan, error) {
+	var s models.Scan
+	err := d.eng.view(func(tx txn) error {
+		return getJSON(tx, bucketScans, idKey(id), &s)
+	})
+	return s, err
+}
+
+func (d *db) ListScans(_ context.Context, limit int) ([]models.Scan, error) {
+	return d.listScans(limit, func(models.Scan) bool { return true })
+}
+
+func (d *db) ListUserScans(_ context.Context, userID string, limit int) ([]models.Scan, error) {
+	return d.listScans(limit, func(s models.Scan) bool { return s.UserID == userID })
+}
+
+func (d *db) listScans(limit int, keep func(models.Scan) bool) ([]models.Scan, error) {
+	var scans []models.Scan
+	err := d.eng.view(func(tx txn) error {
+		return tx.forEach(bucketScans, func(_ string, v []byte) error {
+			var s models.Scan
+			if err := json.Unmarshal(v, &s); err != nil {
+				return err
+			}
+			if keep(s) {
+				scans = append(scans, s)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first
+	for i, j := 0, len(scans)-1; i < j; i, j = i+1, j-1 {
+		scans[i], scans[j] = scans[j], scans[i]
+	}
+	if limit > 0 && len(scans) > limit {
+		scans = scans[:limit]
+	}
+	return scans, nil
+}
+
+func (d *db) AddWatch(_ context.Context, w *models.WatchedDomain) error {
+	return d.eng.update(func(tx txn) error {
+		id, err := tx.nextID(bucketWatches)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		w.ID = id
+		w.CreatedAt = now
+		w.UpdatedAt = now
+		return putJSON(tx, bucketWatches, idKey(id), w)
+	})
+}
+
+func (d *db) UpdateWatch(_ context.Context, w *models.WatchedDomain) error {
+	return d.eng.update(func(tx txn) error {
+		if tx.get(bucketWatches, idKey(w.ID)) == nil {
+			return ErrNotFound
+		}
+		w.UpdatedAt = time.Now()
+		return putJSON(tx, bucketWatches, idKey(w.ID), w)
+	})
+}
+
+func (d *db) DeleteWatch(_ context.Context, id int64) error {
+	return d.eng.update(func(tx txn) error {
+		if tx.get(bucketWatches, idKey(id)) == nil {
+			return ErrNotFound
+		}
+		return tx.delete(bucketWatches, idKey(id))
+	})
+}
+
+func (d *db) ListWatches(_ context.Context) ([]models.WatchedDomain, error) {
+	return d.listWatches(func(models.WatchedDomain) bool { return true })
+}
+
+func (d *db) ListUserWatches(_ context.Context, userID string) ([]models.WatchedDomain, error) {
+	return d.listWatches(func(w models.WatchedDomain) bool { return w.UserID == userID })
+}
+
+func (d *db) listWatches(keep func(models.WatchedDomain) bool) ([]models.WatchedDomain, error) {
+	var watches []models.WatchedDomain
+	err := d.eng.view(func(tx txn) error {
+		return tx.forEach(bucketWatches, func(_ string, v []byte) error {
+			var w models.WatchedDomain
+			if err := json.Unmarshal(v, &w); err != nil {
+				return err
+			}
+			if keep(w) {
+				watches = append(watches, w)
+			}
+			return nil
+		})
+	})
+	return watches, err
+}
+
+func (d *db) GetCheckpoint(_ context.Context, key string) (models.Checkpoint, error) {
+	var cp models.Checkpoint
+	err := d.eng.view(func(tx txn) error {
+		return getJSON(tx, bucketCheckpts, key, &cp)
+	})
+	return cp, err
+}
+
+func (d *db) SaveCheckpoint(_ context.Context, cp models.Checkpoint) error {
+	return d.eng.update(func(tx txn) error {
+		return putJSON(tx, bucketCheckpts, cp.Key, cp)
+	})
+}
+
+func (d *db) DeleteCheckpoint(_ context.Context, key string) error {
+	return d.eng.update(func(tx txn) error {
+		return tx.delete(bucketCheckpts, key)
+	})
+}
+
+func (d *db) ListReported(_ context.Context) ([]models.ReportedFinding, error) {
+	var findings []models.ReportedFinding
+	err := d.eng.view(func(tx txn) error {
+		return tx.forEach(bucketReported, func(_ string, v []byte) error {
+			var f models.ReportedFinding
+			if err := json.Unmarshal(v, &f); err != nil {
+				return err
+			}
+			findings = append(findings, f)
+			return nil
+		})
+	})
+	return findings, err
+}
+
+func (d *db) SaveReported(_ context.Context, findings []models.ReportedFinding) error {
+	return d.eng.update(func(tx txn) error {
+		for _, f := range findings {
+			if err := putJSON(tx, bucketReported, f.Domain, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *db) DeleteReported(_ context.Context, domains []string) error {
+	return d.eng.update(func(tx txn) error {
+		for _, domain := range domains {
+			if err := tx.delete(bucketReported, domain); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *db) SaveServerStats(_ context.Context, stats []models.ServerStats) error {
+	return d.eng.update(func(tx txn) error {
+		for _, s := range stats {
+			if err := putJSON(tx, bucketServers, s.Server, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *db) ListServerStats(_ context.Context) ([]models.ServerStats, error) {
+	var stats []models.ServerStats
+	err := d.eng.view(func(tx txn) error {
+		return tx.forEach(bucketServers, func(_ string, v []byte) error {
+			var s models.ServerStats
+			if err := json.Unmarshal(v, &s); err != nil {
+				return err
+			}
+			stats = append(stats, s)
+			return nil
+		})
+	})
+	return stats, err
+}
+
+// priceKey orders price history by TLD, registrar and time
+func priceKey(p models.Price) string {
+	return p.TLD + "/" + p.Registrar + "/" + idKey(p.CheckedAt.UnixNano())
+}
+
+func (d *db) SavePrices(_ context.Context, prices []models.Price) error {
+	return d.eng.update(func(tx txn) error {
+		for _, p := range prices {
+			if err := putJSON(tx, bucketPrices, priceKey(p), p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *db) ListPrices(_ context.Context, tld string) ([]models.Price, error) {
+	var prices []models.Price
+	err := d.eng.view(func(tx txn) error {
+		return tx.forEach(bucketPrices, func(k string, v []byte) error {
+			if tld != "" && !strings.HasPrefix(k, tld+"/") {
+				return nil
+			}
+			var p models.Price
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			prices = append(prices, p)
+			return nil
+		})
+	})
+	return prices, err
+}
+
+func (d *db) GetSetting(_ context.Context, key string) (string, error) {
+	var value string
+	err := d.eng.view(func(tx txn) error {
+		b := tx.get(bucketSettings, key)
+		if b == nil {
+			return ErrNotFound
+		}
+		value = string(b)
+		return nil
+	})
+	return value, err
+}
+
+func (d *db) SetSetting(_ context.Context, key, value string) error {
+	return d.eng.update(func(tx txn) error {
+		return tx.put(bucketSettings, key, []byte(value))
+	})
+}
+
+// userSettingKey namespaces a setting under its owner
+func userSettingKey(userID, key string) string {
+	return "user/" + userID + "/" + key
+}
+
+func (d *db) GetUserSetting(ctx context.Context, userID, key string) (string, error) {
+	return d.GetSetting(ctx, userSettingKey(userID, key))
+}
+
+func (d *db) SetUserSetting(ctx context.Context, userID, key, value string) error {
+	return d.SetSetting(ctx, userSettingKey(userID, key), value)
+}
+
+func (d *db) Snapshot(w io.Writer) error {
+	return d.eng.snapshot(w)
+}
+
+func (d *db) Close() error {
+	return d.eng.close()
+}
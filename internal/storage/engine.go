@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+)
+
+// Bucket names
+const (
+	bucketResults  = "results"
+	bucketScans    = "scans"
+	bucketWatches  = "watches"
+	bucketSettings = "settings"
+	bucketServers  = "servers"
+	bucketReported = "reported"
+	bucketCheckpts = "checkpoints"
+	bucketJobs     = "jobs"
+	bucketPrices   = "prices"
+)
+
+var buckets = []string{
+	bucketResults, bucketScans, bucketWatches, bucketSettings, bucketServers,
+	bucketReported, bucketCheckpts, bucketJobs, bucketPrices,
+}
+
+// engine is the minimal ordered key/value store the Store methods are
+// written against, so backends only need to provide buckets and transactions
+type engine interface {
+	view(fn func(tx txn) error) error
+	update(fn func(tx txn) error) error
+	snapshot(w io.Writer) error
+	close() error
+}
+
+// txn is a transaction over named buckets. forEach visits keys in byte order.
+type txn interface {
+	get(bucket, key string) []byte
+	put(bucket, key string, value []byte) error
+	delete(bucket, key string) error
+	forEach(bucket string, fn func(key string, value []byte) error) error
+	nextID(bucket string) (int64, error)
+}
+
+// idKey encodes an ID big-endian so byte order matches numeric order
+func idKey(id int64) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(id))
+	return string(b[:])
+}
+
+func getJSON(tx txn, bucket, key string, v any) error {
+	b := tx.get(bucket, key)
+	if b == nil {
+		return ErrNotFound
+	}
+	return json.Unmarshal(b, v)
+}
+
+func putJSON(tx txn, bucket, key string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return tx.put(bucket, key, b)
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// errStop ends a forEach early
+var errStop = errors.New("stop")
+
+func (d *db) SaveJob(_ context.Context, job *models.Job) error {
+	return d.eng.update(func(tx txn) error {
+		if job.ID == 0 {
+			id, err := tx.nextID(bucketJobs)
+			if err != nil {
+				return err
+			}
+			job.ID = id
+		}
+		return putJSON(tx, bucketJobs, idKey(job.ID), job)
+	})
+}
+
+func (d *db) GetJob(_ context.Context, id int64) (models.Job, error) {
+	var job models.Job
+	err := d.eng.view(func(tx txn) error {
+		return getJSON(tx, bucketJobs, idKey(id), &job)
+	})
+	return job, err
+}
+
+func (d *db) ListJobs(_ context.Context, states ...models.JobState) ([]models.Job, error) {
+	var jobs []models.Job
+	err := d.eng.view(func(tx txn) error {
+		return tx.forEach(bucketJobs, func(_ string, v []byte) error {
+			var job models.Job
+			if err := json.Unmarshal(v, &job); err != nil {
+				return err
+			}
+			if len(states) == 0 || slices.Contains(states, job.State) {
+				jobs = append(jobs, job)
+			}
+			return nil
+		})
+	})
+	return jobs, err
+}
+
+func (d *db) ClaimJob(_ context.Context, now time.Time) (models.Job, error) {
+	var claimed models.Job
+	err := d.eng.update(func(tx txn) error {
+		found := false
+		err := tx.forEach(bucketJobs, func(_ string, v []byte) error {
+			var job models.Job
+			if err := json.Unmarshal(v, &job); err != nil {
+				return err
+			}
+			if job.State != models.JobQueued || job.NotBefore.After(now) {
+				return nil
+			}
+			claimed, found = job, true
+			return errStop
+		})
+		if err != nil && err != errStop {
+			return err
+		}
+		if !found {
+			return ErrNotFound
+		}
+
+		claimed.State = models.JobRunning
+		claimed.Attempts++
+		claimed.StartedAt = now
+		return putJSON(tx, bucketJobs, idKey(claimed.ID), claimed)
+	})
+	return claimed, err
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// NewMemory returns a Store that keeps everything in process memory.
+// It behaves like the bbolt backend, including rollback of failed updates,
+// which makes it a drop-in for tests and throwaway runs.
+func NewMemory() Store {
+	e := &memoryEngine{
+		buckets: make(map[string]map[string][]byte),
+		seq:     make(map[string]int64),
+	}
+	for _, name := range buckets {
+		e.buckets[name] = make(map[string][]byte)
+	}
+	return &db{eng: e}
+}
+
+type memoryEngine struct {
+	mu      sync.RWMutex
+	buckets map[string]map[string][]byte
+	seq     map[string]int64
+}
+
+func (e *memoryEngine) view(fn func(tx txn) error) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return fn(&memoryTxn{e: e})
+}
+
+func (e *memoryEngine) update(fn func(tx txn) error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tx := &memoryTxn{e: e, writable: true}
+	if err := fn(tx); err != nil {
+		tx.rollback()
+		return err
+	}
+	return nil
+}
+
+// snapshot copies everything into a temporary bbolt file so memory
+// snapshots can be restored into the bbolt backend
+func (e *memoryEngine) snapshot(w io.Writer) error {
+	f, err := os.CreateTemp("", "domainhunter-snapshot-*.db")
+	if err != nil {
+		return err
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	store, err := OpenBolt(path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	dst := store.(*db).eng.(*boltEngine)
+
+	e.mu.RLock()
+	err = dst.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			b := tx.Bucket([]byte(name))
+			for k, v := range e.buckets[name] {
+				if err := b.Put([]byte(k), v); err != nil {
+					return err
+				}
+			}
+			// Keep ID sequences so restored stores don't reuse IDs
+			if err := b.SetSequence(uint64(e.seq[name])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	e.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return dst.snapshot(w)
+}
+
+func (e *memoryEngine) close() error {
+	return nil
+}
+
+// undo restores one key (or sequence) to its value before the transaction
+type undo struct {
+	bucket  string
+	key     string
+	value   []byte
+	existed bool
+	seq     bool
+	prevSeq int64
+}
+
+type memoryTxn struct {
+	e        *memoryEngine
+	writable bool
+	log      []undo
+}
+
+func (t *memoryTxn) get(bucket, key string) []byte {
+	v, ok := t.e.buckets[bucket][key]
+	if !ok {
+		return nil
+	}
+	return append([]byte(nil), v...)
+}
+
+func (t *memoryTxn) put(bucket, key string, value []byte) error {
+	t.remember(bucket, key)
+	t.e.buckets[bucket][key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (t *memoryTxn) delete(bucket, key string) error {
+	t.remember(bucket, key)
+	delete(t.e.buckets[bucket], key)
+	return nil
+}
+
+func (t *memoryTxn) forEach(bucket string, fn func(key string, value []byte) error) error {
+	b := t.e.buckets[bucket]
+	keys := make([]string, 0, len(b))
+	for k := range b {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := fn(k, b[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *memoryTxn) nextID(bucket string) (int64, error) {
+	t.log = append(t.log, undo{bucket: bucket, seq: true, prevSeq: t.e.seq[bucket]})
+	t.e.seq[bucket]++
+	return t.e.seq[bucket], nil
+}
+
+func (t *memoryTxn) remember(bucket, key string) {
+	v, ok := t.e.buckets[bucket][key]
+	t.log = append(t.log, undo{bucket: bucket, key: key, value: v, existed: ok})
+}
+
+func (t *memoryTxn) rollback() {
+	for i := len(t.log) - 1; i >= 0; i-- {
+		u := t.log[i]
+		switch {
+		case u.seq:
+			t.e.seq[u.bucket] = u.prevSeq
+		case u.existed:
+			t.e.buckets[u.bucket][u.key] = u.value
+		default:
+			delete(t.e.buckets[u.bucket], u.key)
+		}
+	}
+}
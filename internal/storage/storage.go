@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("storage: not found")
+
+// Store persists check results, scan history, the watch list and settings.
+// Watches, scans and settings can be owned by a user; the unscoped List
+// methods return every user's records and are meant for admin views.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// SaveResults stores the latest result for each domain
+	SaveResults(ctx context.Context, results []models.DomainResult) error
+	// GetResult returns the latest stored result for domain
+	GetResult(ctx context.Context, domain string) (models.DomainResult, error)
+
+	// SaveScan inserts a scan, assigning its ID
+	SaveScan(ctx context.Context, scan *models.Scan) error
+	// GetScan returns a scan by ID
+	GetScan(ctx context.Context, id int64) (models.Scan, error)
+	// ListScans returns up to limit scans, newest first (0 = all)
+	ListScans(ctx context.Context, limit int) ([]models.Scan, error)
+	// ListUserScans is ListScans restricted to scans owned by userID
+	ListUserScans(ctx context.Context, userID string, limit int) ([]models.Scan, error)
+
+	// AddWatch inserts a watched domain, assigning its ID and timestamps
+	AddWatch(ctx context.Context, w *models.WatchedDomain) error
+	// UpdateWatch overwrites an existing watched domain
+	UpdateWatch(ctx context.Context, w *models.WatchedDomain) error
+	// DeleteWatch removes a watched domain by ID
+	DeleteWatch(ctx context.Context, id int64) error
+	// ListWatches returns all watched domains in insertion order
+	ListWatches(ctx context.Context) ([]models.WatchedDomain, error)
+	// ListUserWatches is ListWatches restricted to entries owned by userID
+	ListUserWatches(ctx context.Context, userID string) ([]models.WatchedDomain, error)
+
+	// SaveJob inserts a job (assigning its ID when zero) or updates it
+	SaveJob(ctx context.Context, job *models.Job) error
+	// GetJob returns a job by ID
+	GetJob(ctx context.Context, id int64) (models.Job, error)
+	// ListJobs returns jobs in the given states (all when none), oldest first
+	ListJobs(ctx context.Context, states ...models.JobState) ([]models.Job, error)
+	// ClaimJob atomically moves the oldest runnable queued job to running.
+	// It returns ErrNotFound when nothing is runnable.
+	ClaimJob(ctx context.Context, now time.Time) (models.Job, error)
+
+	// GetCheckpoint returns the checkpoint stored under key, or ErrNotFound
+	GetCheckpoint(ctx context.Context, key string) (models.Checkpoint, error)
+	// SaveCheckpoint upserts a checkpoint
+	SaveCheckpoint(ctx context.Context, cp models.Checkpoint) error
+	// DeleteCheckpoint removes a checkpoint once its scan completes
+	DeleteCheckpoint(ctx context.Context, key string) error
+
+	// ListReported returns all findings already sent in a report
+	ListReported(ctx context.Context) ([]models.ReportedFinding, error)
+	// SaveReported upserts reported findings
+	SaveReported(ctx context.Context, findings []models.ReportedFinding) error
+	// DeleteReported forgets reported findings so they count as new again
+	DeleteReported(ctx context.Context, domains []string) error
+
+	// SaveServerStats upserts WHOIS server telemetry
+	SaveServerStats(ctx context.Context, stats []models.ServerStats) error
+	// ListServerStats returns telemetry for all known WHOIS servers
+	ListServerStats(ctx context.Context) ([]models.ServerStats, error)
+
+	// SavePrices records price observations, keeping earlier ones
+	SavePrices(ctx context.Context, prices []models.Price) error
+	// ListPrices returns the price history for tld (all TLDs when empty),
+	// grouped by TLD and registrar and oldest first within each
+	ListPrices(ctx context.Context, tld string) ([]models.Price, error)
+
+	// GetSetting returns a setting value, or ErrNotFound
+	GetSetting(ctx context.Context, key string) (string, error)
+	// SetSetting stores a setting value
+	SetSetting(ctx context.Context, key, value string) error
+	// GetUserSetting returns a per-user setting (e.g. notification channels)
+	GetUserSetting(ctx context.Context, userID, key string) (string, error)
+	// SetUserSetting stores a per-user setting
+	SetUserSetting(ctx context.Context, userID, key, value string) error
+
+	// Snapshot writes a consistent copy of the whole database to w in
+	// bbolt file format, without blocking readers
+	Snapshot(w io.Writer) error
+
+	// Close releases the underlying database
+	Close() error
+}
+
+// Open returns the Store for path: ":memory:" selects the in-memory
+// backend, anything else is treated as a bbolt database file
+func Open(path string) (Store, error) {
+	if path == ":memory:" {
+		return NewMemory(), nil
+	}
+	return OpenBolt(path)
+}
@@ -0,0 +1,150 @@
+// Package domainhunter checks domain availability from other Go programs.
+// It is the stable surface of DomainHunter's checker: DNS first to rule out
+// delegated names quickly, then WHOIS to confirm the rest, with per-server
+// rate limits, retries and circuit breakers.
+//
+//	c := domainhunter.New(domainhunter.WithWHOISConcurrency(2))
+//	results, err := c.CheckBulk(ctx, []string{"example.com", "getfoo.io"})
+//
+// The package follows semantic versioning with the module's release tags:
+// within a major version its exported identifiers keep their signatures
+// and results keep their documented meaning, and new statuses or fields
+// are only added. The internal packages it wraps make no such promise.
+package domainhunter
+
+import (
+	"context"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/checker"
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+// Status is a domain's availability
+type Status string
+
+const (
+	// StatusAvailable can be registered
+	StatusAvailable Status = "available"
+	// StatusTaken is registered, or its check failed; see Result.Error
+	StatusTaken Status = "taken"
+	// StatusPremium is a registry premium name; Result.Price has the quote
+	// when a registrar gave one
+	StatusPremium Status = "premium"
+	// StatusUnknown was skipped because its WHOIS server was down or
+	// throttling queries; Result.Error says which
+	StatusUnknown Status = "unknown"
+	// StatusUnchecked wasn't reached before the context was done
+	StatusUnchecked Status = "unchecked"
+	// StatusError couldn't be checked at all, e.g. an invalid name
+	StatusError Status = "error"
+)
+
+// Result is the outcome of checking one domain
+type Result struct {
+	Domain string `json:"domain"`
+	Status Status `json:"status"`
+	// Method is the lookup that decided the status: "dns" or "whois"
+	Method    string    `json:"method,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Error records a failed lookup, even when the status fell back to
+	// taken
+	Error string `json:"error,omitempty"`
+	// DNSRcode is the DNS answer, e.g. NXDOMAIN, for domains the DNS phase
+	// checked
+	DNSRcode string `json:"dns_rcode,omitempty"`
+	// Price is a premium name's first-year price in US dollars
+	Price float64 `json:"price,omitempty"`
+}
+
+// Record is a domain's parsed WHOIS record; fields the registry didn't
+// return are zero
+type Record struct {
+	Registrar string `json:"registrar,omitempty"`
+	// Statuses are EPP status codes, e.g. clientTransferProhibited
+	Statuses    []string  `json:"statuses,omitempty"`
+	NameServers []string  `json:"name_servers,omitempty"`
+	Created     time.Time `json:"created,omitzero"`
+	Updated     time.Time `json:"updated,omitzero"`
+	Expires     time.Time `json:"expires,omitzero"`
+}
+
+// Checker checks domains. It is safe for concurrent use; share one so its
+// rate limits and caches cover every check.
+type Checker struct {
+	c *checker.Checker
+}
+
+// New creates a Checker with the defaults of the domainhunter CLI's normal
+// profile, adjusted by opts
+func New(opts ...Option) *Checker {
+	var o []checker.Option
+	for _, opt := range opts {
+		if opt.apply != nil {
+			o = append(o, opt.apply)
+		}
+	}
+	return &Checker{checker.New(o...)}
+}
+
+// Check checks one domain by WHOIS. Once ctx is done the query is
+// abandoned and the result carries ctx's error.
+func (c *Checker) Check(ctx context.Context, domain string) Result {
+	return result(c.c.Check(ctx, domain))
+}
+
+// CheckBulk checks domains by DNS, confirming those without a delegation
+// by WHOIS. Results are in domain order; if ctx is done first, the domains
+// not reached are StatusUnchecked and ctx.Err() is returned with them.
+func (c *Checker) CheckBulk(ctx context.Context, domains []string) ([]Result, error) {
+	rs, err := c.c.CheckBulkHybrid(ctx, domains)
+	return results(rs), err
+}
+
+// CheckBulkWHOIS is CheckBulk confirming every domain by WHOIS, for TLDs
+// where a delegation doesn't mean the name is registered
+func (c *Checker) CheckBulkWHOIS(ctx context.Context, domains []string) ([]Result, error) {
+	rs, err := c.c.CheckBulk(ctx, domains)
+	return results(rs), err
+}
+
+// Lookup returns domain's parsed WHOIS record
+func (c *Checker) Lookup(ctx context.Context, domain string) (Record, error) {
+	rec, err := c.c.Lookup(ctx, domain)
+	if err != nil {
+		return Record{}, err
+	}
+	return Record{
+		Registrar:   rec.Registrar,
+		Statuses:    rec.Statuses,
+		NameServers: rec.NameServers,
+		Created:     rec.Created,
+		Updated:     rec.Updated,
+		Expires:     rec.Expires,
+	}, nil
+}
+
+// result converts a checker result
+func result(r models.DomainResult) Result {
+	return Result{
+		Domain:    r.Domain,
+		Status:    Status(r.Status),
+		Method:    r.Method,
+		CheckedAt: r.CheckedAt,
+		Error:     r.Error,
+		DNSRcode:  r.DNSRcode,
+		Price:     r.Price,
+	}
+}
+
+// results converts checker results, keeping nil as nil
+func results(rs []models.DomainResult) []Result {
+	if rs == nil {
+		return nil
+	}
+	out := make([]Result, len(rs))
+	for i, r := range rs {
+		out[i] = result(r)
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package domainhunter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/models"
+)
+
+func TestResult(t *testing.T) {
+	checked := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	in := models.DomainResult{
+		Domain:    "ab.io",
+		Status:    models.StatusPremium,
+		Method:    models.MethodWHOIS,
+		Phase:     "redemption",
+		CheckedAt: checked,
+		Error:     "slow server",
+		DNSRcode:  models.RcodeNXDomain,
+		Price:     120,
+	}
+	want := Result{
+		Domain:    "ab.io",
+		Status:    StatusPremium,
+		Method:    "whois",
+		CheckedAt: checked,
+		Error:     "slow server",
+		DNSRcode:  "NXDOMAIN",
+		Price:     120,
+	}
+	if got := result(in); got != want {
+		t.Errorf("result() = %+v, want %+v", got, want)
+	}
+}
+
+// Every Result field is copied from the checker's result, so a field added
+// to one side only is caught here
+func TestResultFields(t *testing.T) {
+	from, to := reflect.TypeFor[models.DomainResult](), reflect.TypeFor[Result]()
+	for i := range to.NumField() {
+		f := to.Field(i)
+		if _, ok := from.FieldByName(f.Name); !ok {
+			t.Errorf("Result.%s has no counterpart in models.DomainResult", f.Name)
+		}
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	for _, s := range []models.DomainStatus{
+		models.StatusAvailable, models.StatusTaken, models.StatusPremium,
+		models.StatusUnknown, models.StatusUnchecked, models.StatusError,
+	} {
+		if got := result(models.DomainResult{Status: s}).Status; string(got) != string(s) {
+			t.Errorf("status %q converted to %q", s, got)
+		}
+	}
+}
+
+func TestResults(t *testing.T) {
+	if got := results(nil); got != nil {
+		t.Errorf("results(nil) = %v, want nil", got)
+	}
+	if got := results([]models.DomainResult{}); got == nil || len(got) != 0 {
+		t.Errorf("results(empty) = %#v, want an empty slice", got)
+	}
+
+	in := []models.DomainResult{
+		{Domain: "a.io", Status: models.StatusAvailable},
+		{Domain: "b.io", Status: models.StatusTaken},
+	}
+	got := results(in)
+	if len(got) != len(in) {
+		t.Fatalf("got %d results, want %d", len(got), len(in))
+	}
+	for i := range in {
+		if got[i] != result(in[i]) {
+			t.Errorf("results()[%d] = %+v, want %+v", i, got[i], result(in[i]))
+		}
+	}
+}
+
+func TestNilOption(t *testing.T) {
+	// A zero Option is ignored rather than panicking
+	if New(Option{}, WithDNSConcurrency(1)) == nil {
+		t.Fatal("New returned nil")
+	}
+}
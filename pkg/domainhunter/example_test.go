@@ -0,0 +1,42 @@
+package domainhunter_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/berckan/domainhunter/pkg/domainhunter"
+)
+
+func Example() {
+	c := domainhunter.New(domainhunter.WithWHOISConcurrency(2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	results, err := c.CheckBulk(ctx, []string{"example.com", "getfoo.io"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range results {
+		switch r.Status {
+		case domainhunter.StatusAvailable:
+			fmt.Println(r.Domain, "is available")
+		case domainhunter.StatusPremium:
+			fmt.Printf("%s is premium at $%.2f\n", r.Domain, r.Price)
+		case domainhunter.StatusUnknown, domainhunter.StatusUnchecked:
+			fmt.Println(r.Domain, "needs checking again")
+		default:
+			fmt.Println(r.Domain, "is", r.Status)
+		}
+	}
+}
+
+func ExampleChecker_Lookup() {
+	c := domainhunter.New()
+	rec, err := c.Lookup(context.Background(), "example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(rec.Registrar, rec.Expires.Format(time.DateOnly))
+}
@@ -0,0 +1,59 @@
+package domainhunter
+
+import (
+	"net"
+	"time"
+
+	"github.com/berckan/domainhunter/internal/checker"
+)
+
+// Option configures a Checker; zero values leave the default. It is
+// opaque: build one with the With functions below, which are the only
+// options the package supports.
+type Option struct {
+	apply checker.Option
+}
+
+// WithDNSServer sends DNS lookups to the resolver at addr (host:port)
+// instead of 8.8.8.8
+func WithDNSServer(addr string) Option {
+	return Option{checker.WithDNSServer(addr)}
+}
+
+// WithDNSConcurrency sets how many DNS lookups run at once (default 50)
+func WithDNSConcurrency(n int) Option {
+	return Option{checker.WithDNSConcurrency(n)}
+}
+
+// WithDNSQPS caps the DNS query rate (default 1000 per second)
+func WithDNSQPS(qps float64) Option {
+	return Option{checker.WithDNSQPS(qps)}
+}
+
+// WithDNSTimeout caps the time spent on one domain's DNS lookup, retries
+// included (default 10s)
+func WithDNSTimeout(d time.Duration) Option {
+	return Option{checker.WithDNSTimeout(d)}
+}
+
+// WithWHOISConcurrency sets how many WHOIS queries run at once to each
+// registry server (default 5). Servers that throttle get fewer.
+func WithWHOISConcurrency(n int) Option {
+	return Option{checker.WithWHOISConcurrency(n)}
+}
+
+// WithWHOISQPS caps the query rate to each WHOIS server (default
+// unlimited)
+func WithWHOISQPS(qps float64) Option {
+	return Option{checker.WithWHOISQPS(qps)}
+}
+
+// WithWHOISTimeout caps how long one WHOIS query may take (default 30s)
+func WithWHOISTimeout(d time.Duration) Option {
+	return Option{checker.WithWHOISTimeout(d)}
+}
+
+// WithSourceAddr makes WHOIS connections from the given local IP
+func WithSourceAddr(ip net.IP) Option {
+	return Option{checker.WithSourceAddr(ip)}
+}